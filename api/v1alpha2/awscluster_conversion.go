@@ -55,12 +55,50 @@ func (src *AWSCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 
 	dst.Spec.ImageLookupOrg = restored.Spec.ImageLookupOrg
 	dst.Spec.ImageLookupBaseOS = restored.Spec.ImageLookupBaseOS
+	dst.Spec.ControlPlaneEndpointDNS = restored.Spec.ControlPlaneEndpointDNS
+	dst.Spec.IAMInstanceProfiles = restored.Spec.IAMInstanceProfiles
+	dst.Spec.GenerateSSHKey = restored.Spec.GenerateSSHKey
+	dst.Spec.S3Bucket = restored.Spec.S3Bucket
+	dst.Spec.ImageLookupOwners = restored.Spec.ImageLookupOwners
+	dst.Spec.ImageLookupFilters = restored.Spec.ImageLookupFilters
+	dst.Spec.SessionManager = restored.Spec.SessionManager
+	dst.Spec.APIServerPort = restored.Spec.APIServerPort
 	if restored.Spec.ControlPlaneLoadBalancer != nil {
 		dst.Spec.ControlPlaneLoadBalancer = restored.Spec.ControlPlaneLoadBalancer
 	}
+	dst.Spec.NetworkSpec.AdditionalControlPlaneIngressRules = restored.Spec.NetworkSpec.AdditionalControlPlaneIngressRules
+	dst.Spec.NetworkSpec.AdditionalNodeIngressRules = restored.Spec.NetworkSpec.AdditionalNodeIngressRules
+	dst.Spec.NetworkSpec.NodeEgressRules = restored.Spec.NetworkSpec.NodeEgressRules
+	dst.Spec.NetworkSpec.ElasticFabricAdapterSupport = restored.Spec.NetworkSpec.ElasticFabricAdapterSupport
+	dst.Spec.NetworkSpec.VPC.PrivateEndpoints = restored.Spec.NetworkSpec.VPC.PrivateEndpoints
+	dst.Spec.NetworkSpec.VPC.FlowLogs = restored.Spec.NetworkSpec.VPC.FlowLogs
+	dst.Spec.NetworkSpec.VPC.NatGatewayStrategy = restored.Spec.NetworkSpec.VPC.NatGatewayStrategy
+	dst.Spec.NetworkSpec.VPC.DHCPOptions = restored.Spec.NetworkSpec.VPC.DHCPOptions
+	dst.Spec.NetworkSpec.VPC.Peering = restored.Spec.NetworkSpec.VPC.Peering
+	dst.Spec.NetworkSpec.VPC.InstanceTenancy = restored.Spec.NetworkSpec.VPC.InstanceTenancy
+	dst.Spec.NetworkSpec.SubnetFilters = restored.Spec.NetworkSpec.SubnetFilters
+	dst.Spec.NetworkSpec.TransitGateway = restored.Spec.NetworkSpec.TransitGateway
+	dst.Spec.NetworkSpec.NetworkACLs = restored.Spec.NetworkSpec.NetworkACLs
+	dst.Spec.NetworkSpec.FullyPrivate = restored.Spec.NetworkSpec.FullyPrivate
+	dst.Spec.NetworkSpec.SecurityGroupDriftRemediation = restored.Spec.NetworkSpec.SecurityGroupDriftRemediation
+	dst.Spec.NetworkSpec.AvailabilityZones = restored.Spec.NetworkSpec.AvailabilityZones
+	dst.Spec.NetworkSpec.AvailabilityZoneUsageLimit = restored.Spec.NetworkSpec.AvailabilityZoneUsageLimit
+	if len(restored.Spec.NetworkSpec.Subnets) == len(dst.Spec.NetworkSpec.Subnets) {
+		for i, subnet := range dst.Spec.NetworkSpec.Subnets {
+			restoreSubnetSpec(restored.Spec.NetworkSpec.Subnets[i], subnet)
+		}
+	}
 	dst.Status.FailureDomains = restored.Status.FailureDomains
+	dst.Status.Network.Subnets = restored.Status.Network.Subnets
+	dst.Status.Network.TransitGatewayAttachment = restored.Status.Network.TransitGatewayAttachment
+	dst.Status.Network.VPCPeeringConnection = restored.Status.Network.VPCPeeringConnection
 	dst.Status.Network.APIServerELB.AvailabilityZones = restored.Status.Network.APIServerELB.AvailabilityZones
 	dst.Status.Network.APIServerELB.Attributes.CrossZoneLoadBalancing = restored.Status.Network.APIServerELB.Attributes.CrossZoneLoadBalancing
+	dst.Status.Network.APIServerELB.CanonicalHostedZoneID = restored.Status.Network.APIServerELB.CanonicalHostedZoneID
+	dst.Status.Network.APIServerELB.Attributes.AccessLog = restored.Status.Network.APIServerELB.Attributes.AccessLog
+	dst.Status.Network.APIServerELB.ProxyProtocol = restored.Status.Network.APIServerELB.ProxyProtocol
+	dst.Status.Network.APIServerInternalELB = restored.Status.Network.APIServerInternalELB
+	dst.Status.SessionManagerTargets = restored.Status.SessionManagerTargets
 
 	if restored.Status.Bastion != nil {
 		restored.Status.Bastion.DeepCopyInto(dst.Status.Bastion)
@@ -69,6 +107,17 @@ func (src *AWSCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	return nil
 }
 
+// restoreSubnetSpec restores the v1alpha3 only fields of a subnet.
+func restoreSubnetSpec(restored, dst *infrav1alpha3.SubnetSpec) {
+	if restored == nil || dst == nil {
+		return
+	}
+	dst.NatGatewayEIPAllocationID = restored.NatGatewayEIPAllocationID
+	dst.OutpostARN = restored.OutpostARN
+	dst.LocalGatewayID = restored.LocalGatewayID
+	dst.AdditionalRoutes = restored.AdditionalRoutes
+}
+
 // ConvertFrom converts from the Hub version (v1alpha3) to this version.
 func (dst *AWSCluster) ConvertFrom(srcRaw conversion.Hub) error { // nolint
 	src := srcRaw.(*infrav1alpha3.AWSCluster)
@@ -186,3 +235,68 @@ func Convert_v1alpha3_AWSLoadBalancerSpec_To_v1alpha2_AWSLoadBalancerSpec(in *in
 func Convert_v1alpha3_ClassicELBAttributes_To_v1alpha2_ClassicELBAttributes(in *infrav1alpha3.ClassicELBAttributes, out *ClassicELBAttributes, s apiconversion.Scope) error { //nolint
 	return autoConvert_v1alpha3_ClassicELBAttributes_To_v1alpha2_ClassicELBAttributes(in, out, s)
 }
+
+// Convert_v1alpha2_NetworkSpec_To_v1alpha3_NetworkSpec.
+func Convert_v1alpha2_NetworkSpec_To_v1alpha3_NetworkSpec(in *NetworkSpec, out *infrav1alpha3.NetworkSpec, s apiconversion.Scope) error { //nolint
+	// Subnets are converted manually, as the generated conversion requires a conversion scope.
+	spec := *in
+	spec.Subnets = nil
+	if err := autoConvert_v1alpha2_NetworkSpec_To_v1alpha3_NetworkSpec(&spec, out, s); err != nil {
+		return err
+	}
+
+	if in.Subnets != nil {
+		out.Subnets = make(infrav1alpha3.Subnets, len(in.Subnets))
+		for i := range in.Subnets {
+			if in.Subnets[i] == nil {
+				continue
+			}
+			out.Subnets[i] = &infrav1alpha3.SubnetSpec{}
+			if err := Convert_v1alpha2_SubnetSpec_To_v1alpha3_SubnetSpec(in.Subnets[i], out.Subnets[i], s); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Convert_v1alpha3_NetworkSpec_To_v1alpha2_NetworkSpec.
+func Convert_v1alpha3_NetworkSpec_To_v1alpha2_NetworkSpec(in *infrav1alpha3.NetworkSpec, out *NetworkSpec, s apiconversion.Scope) error { //nolint
+	// Subnets are converted manually, as the generated conversion requires a conversion scope.
+	spec := *in
+	spec.Subnets = nil
+	if err := autoConvert_v1alpha3_NetworkSpec_To_v1alpha2_NetworkSpec(&spec, out, s); err != nil {
+		return err
+	}
+
+	if in.Subnets != nil {
+		out.Subnets = make(Subnets, len(in.Subnets))
+		for i := range in.Subnets {
+			if in.Subnets[i] == nil {
+				continue
+			}
+			out.Subnets[i] = &SubnetSpec{}
+			if err := Convert_v1alpha3_SubnetSpec_To_v1alpha2_SubnetSpec(in.Subnets[i], out.Subnets[i], s); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Convert_v1alpha3_VPCSpec_To_v1alpha2_VPCSpec.
+func Convert_v1alpha3_VPCSpec_To_v1alpha2_VPCSpec(in *infrav1alpha3.VPCSpec, out *VPCSpec, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1alpha3_VPCSpec_To_v1alpha2_VPCSpec(in, out, s)
+}
+
+// Convert_v1alpha3_Network_To_v1alpha2_Network.
+func Convert_v1alpha3_Network_To_v1alpha2_Network(in *infrav1alpha3.Network, out *Network, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1alpha3_Network_To_v1alpha2_Network(in, out, s)
+}
+
+// Convert_v1alpha3_SubnetSpec_To_v1alpha2_SubnetSpec.
+func Convert_v1alpha3_SubnetSpec_To_v1alpha2_SubnetSpec(in *infrav1alpha3.SubnetSpec, out *SubnetSpec, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1alpha3_SubnetSpec_To_v1alpha2_SubnetSpec(in, out, s)
+}
@@ -57,6 +57,27 @@ func restoreAWSMachineSpec(restored *infrav1alpha3.AWSMachineSpec, dst *infrav1a
 
 	// manual conversion for UncompressedUserData
 	dst.UncompressedUserData = restored.UncompressedUserData
+
+	dst.OutpostARN = restored.OutpostARN
+	dst.PlacementGroup = restored.PlacementGroup
+	dst.Placement = restored.Placement
+	dst.CPUOptions = restored.CPUOptions
+	dst.NetworkInterfaceSpecs = restored.NetworkInterfaceSpecs
+	dst.InstanceMetadataOptions = restored.InstanceMetadataOptions
+	dst.UnlimitedCPUCredits = restored.UnlimitedCPUCredits
+	dst.NonRootVolumes = restored.NonRootVolumes
+	dst.EphemeralVolumes = restored.EphemeralVolumes
+	dst.AssociateElasticIP = restored.AssociateElasticIP
+	dst.ElasticIPPool = restored.ElasticIPPool
+	dst.CapacityReservationID = restored.CapacityReservationID
+	dst.CapacityReservationPreference = restored.CapacityReservationPreference
+	dst.CloudInit.UserDataParts = restored.CloudInit.UserDataParts
+	dst.Bottlerocket = restored.Bottlerocket
+	dst.Ignition = restored.Ignition
+	dst.GPUAMIFamily = restored.GPUAMIFamily
+	dst.ImageLookupSSMParameter = restored.ImageLookupSSMParameter
+	dst.ImageLookupOwners = restored.ImageLookupOwners
+	dst.ImageLookupFilters = restored.ImageLookupFilters
 }
 
 // ConvertFrom converts from the Hub version (v1alpha3) to this version.
@@ -107,7 +128,7 @@ func Convert_v1alpha2_AWSMachineSpec_To_v1alpha3_AWSMachineSpec(in *AWSMachineSp
 
 	// Manually convert RootDeviceSize. This may be overridden by restoring / upconverting from annotation.
 	if in.RootDeviceSize != 0 {
-		out.RootVolume = &infrav1alpha3.RootVolume{
+		out.RootVolume = &infrav1alpha3.Volume{
 			Size: in.RootDeviceSize,
 		}
 	}
@@ -178,7 +199,7 @@ func Convert_v1alpha2_Instance_To_v1alpha3_Instance(in *Instance, out *infrav1al
 
 	// Manually convert RootDeviceSize.
 	if in.RootDeviceSize != 0 {
-		out.RootVolume = &infrav1alpha3.RootVolume{
+		out.RootVolume = &infrav1alpha3.Volume{
 			Size: in.RootDeviceSize,
 		}
 	}
@@ -39,10 +39,29 @@ type AWSClusterSpec struct {
 	// +optional
 	SSHKeyName *string `json:"sshKeyName,omitempty"`
 
+	// GenerateSSHKey makes the controller create the EC2 key pair "<namespace>.<cluster name>-ssh-key" for the
+	// cluster and store its private key in the secret "<cluster name>-ssh-key" in the namespace of the cluster.
+	// The key pair is used by the bastion host and by machines that don't specify an SSH key name, and is deleted
+	// with the cluster. Existing key pairs with the same name that aren't tagged as owned by the cluster are
+	// never replaced nor deleted.
+	// Cannot be set together with SSHKeyName.
+	// +optional
+	GenerateSSHKey bool `json:"generateSSHKey,omitempty"`
+
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
 
+	// APIServerPort is the port the API server listens on. It is used for the load balancer listener,
+	// instance port and health check, the control plane security group rules and the control plane endpoint.
+	// The bootstrap configuration has to bind the API server to the same port. When not set, the load
+	// balancer listens on the API server port of the cluster network, or 6443, and forwards to port 6443
+	// of the control plane instances. Cannot be changed once set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	APIServerPort *int32 `json:"apiServerPort,omitempty"`
+
 	// AdditionalTags is an optional set of tags to add to AWS resources managed by the AWS provider, in addition to the
 	// ones added by default.
 	// +optional
@@ -64,9 +83,107 @@ type AWSClusterSpec struct {
 	// different ImageLookupBaseOS.
 	ImageLookupBaseOS string `json:"imageLookupBaseOS,omitempty"`
 
+	// ImageLookupOwners are additional AWS account IDs owning the machine images
+	// looked up when a machine does not specify an AMI, e.g. the accounts of a
+	// golden image pipeline. When set, these will be used for all cluster
+	// machines unless a machine specifies different ImageLookupOwners.
+	// +optional
+	ImageLookupOwners []string `json:"imageLookupOwners,omitempty"`
+
+	// ImageLookupFilters are additional filters, e.g. tag:approved=true, the
+	// machine images looked up when a machine does not specify an AMI must
+	// match. When set, these will be used for all cluster machines unless a
+	// machine specifies different ImageLookupFilters.
+	// +optional
+	ImageLookupFilters []Filter `json:"imageLookupFilters,omitempty"`
+
 	// Bastion contains options to configure the bastion host.
 	// +optional
 	Bastion Bastion `json:"bastion"`
+
+	// SessionManager configures access to the cluster instances through AWS Systems Manager Session
+	// Manager instead of SSH through a bastion host.
+	// +optional
+	SessionManager SessionManager `json:"sessionManager,omitempty"`
+
+	// ControlPlaneEndpointDNS configures a Route53 alias record pointing at the control plane load balancer.
+	// When set, the control plane endpoint uses the record name instead of the load balancer DNS name.
+	// It is immutable since the control plane endpoint can't be changed once set.
+	// +optional
+	ControlPlaneEndpointDNS *ControlPlaneEndpointDNSSpec `json:"controlPlaneEndpointDNS,omitempty"`
+
+	// IAMInstanceProfiles configures IAM instance profiles created and managed by the controller for the cluster.
+	// Machines that don't specify an IAM instance profile use the managed profile of their role.
+	// The profiles and their roles are deleted with the cluster.
+	// +optional
+	IAMInstanceProfiles *IAMInstanceProfiles `json:"iamInstanceProfiles,omitempty"`
+
+	// S3Bucket configures an S3 bucket created and managed by the controller for the cluster. It stores
+	// the bootstrap data of machines using Ignition, which is too large to be passed as user data.
+	// The bucket is deleted with the cluster.
+	// +optional
+	S3Bucket *S3Bucket `json:"s3Bucket,omitempty"`
+}
+
+// ControlPlaneEndpointDNSSpec defines the Route53 record of the control plane endpoint.
+type ControlPlaneEndpointDNSSpec struct {
+	// HostedZoneID is the id of the Route53 hosted zone the record is created in.
+	HostedZoneID string `json:"hostedZoneID"`
+
+	// RecordName is the fully qualified domain name of the record, e.g. api.cluster.example.com.
+	RecordName string `json:"recordName"`
+}
+
+// IAMInstanceProfiles defines the IAM instance profiles managed for a cluster.
+type IAMInstanceProfiles struct {
+	// ControlPlane configures the instance profile of the control plane machines.
+	// +optional
+	ControlPlane *IAMInstanceProfileSpec `json:"controlPlane,omitempty"`
+
+	// Nodes configures the instance profile of the worker machines.
+	// +optional
+	Nodes *IAMInstanceProfileSpec `json:"nodes,omitempty"`
+}
+
+// IAMInstanceProfileSpec defines an IAM instance profile and the role it's created with.
+type IAMInstanceProfileSpec struct {
+	// ManagedPolicyARNs are the ARNs of the managed policies attached to the role.
+	// Defaults to the policies created by clusterawsadm for machines of the same role.
+	// The controllers can only attach the policies created by clusterawsadm, AmazonSSMManagedInstanceCore
+	// and the extra policies clusterawsadm was bootstrapped with.
+	// +optional
+	ManagedPolicyARNs []string `json:"managedPolicyARNs,omitempty"`
+
+	// PermissionsBoundary is the ARN of the managed policy used as permissions boundary of the role.
+	// It must match the permissions boundary clusterawsadm was bootstrapped with, if any.
+	// Removing it from the spec doesn't remove it from an existing role.
+	// +optional
+	PermissionsBoundary *string `json:"permissionsBoundary,omitempty"`
+}
+
+// S3BucketNamePrefix is the prefix of the names of the S3 buckets managed for clusters. The controllers
+// policy created by clusterawsadm only grants access to buckets with this prefix.
+const S3BucketNamePrefix = "cluster-api-provider-aws-"
+
+// S3Bucket defines the S3 bucket managed for a cluster.
+type S3Bucket struct {
+	// Name is the globally unique name of the bucket. It must start with cluster-api-provider-aws-.
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9.-]+[a-z0-9]$`
+	Name string `json:"name"`
+
+	// ControlPlaneIAMInstanceProfile is the instance profile of the control plane machines, whose role
+	// is granted read access to their bootstrap data. Defaults to the managed control plane instance
+	// profile if IAMInstanceProfiles is set, or to control-plane.cluster-api-provider-aws.sigs.k8s.io.
+	// +optional
+	ControlPlaneIAMInstanceProfile string `json:"controlPlaneIAMInstanceProfile,omitempty"`
+
+	// NodesIAMInstanceProfiles are the instance profiles of the worker machines, whose roles are
+	// granted read access to their bootstrap data. Defaults to the managed nodes instance profile
+	// if IAMInstanceProfiles is set, or to nodes.cluster-api-provider-aws.sigs.k8s.io.
+	// +optional
+	NodesIAMInstanceProfiles []string `json:"nodesIAMInstanceProfiles,omitempty"`
 }
 
 type Bastion struct {
@@ -76,8 +193,29 @@ type Bastion struct {
 	Enabled bool `json:"enabled"`
 }
 
+// SessionManager defines access to the cluster instances through AWS Systems Manager Session Manager.
+type SessionManager struct {
+	// Enabled makes the instances of the cluster reachable through Session Manager. The bastion host
+	// is not created and no SSH ingress from the internet is allowed. The ssm, ssmmessages and
+	// ec2messages VPC endpoints are created when private endpoints are enabled, and managed IAM
+	// instance profiles get the AmazonSSMManagedInstanceCore policy attached.
+	// Cannot be set together with Bastion.Enabled.
+	// +optional
+	Enabled bool `json:"enabled"`
+}
+
 // AWSLoadBalancerSpec defines the desired state of an AWS load balancer
 type AWSLoadBalancerSpec struct {
+	// Name sets the name of the classic ELB load balancer. As per AWS, the name must be unique
+	// within your set of load balancers for the region, must have a maximum of 32 characters, must
+	// contain only alphanumeric characters or hyphens, and cannot begin or end with a hyphen. Once
+	// set, the value cannot be changed.
+	// Defaults to a name generated from the cluster name.
+	// +kubebuilder:validation:MaxLength:=32
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9]([A-Za-z0-9]{0,31}|[-A-Za-z0-9]{0,30}[A-Za-z0-9])$`
+	// +optional
+	Name *string `json:"name,omitempty"`
+
 	// Scheme sets the scheme of the load balancer (defaults to Internet-facing)
 	// +optional
 	Scheme *ClassicELBScheme `json:"scheme,omitempty"`
@@ -92,6 +230,108 @@ type AWSLoadBalancerSpec struct {
 	// Defaults to false.
 	// +optional
 	CrossZoneLoadBalancing bool `json:"crossZoneLoadBalancing,omitempty"`
+
+	// InternalLoadBalancer provisions an internal classic ELB in the private subnets in addition to the
+	// internet-facing one. The control plane endpoint of the cluster stays on the internet-facing load
+	// balancer, the DNS name of the internal load balancer is reported in status.network.apiServerInternalElb
+	// and has to be added to the API server certificate SANs for clients in the VPC to use it.
+	// Requires the internet-facing scheme and cannot be changed once set.
+	// +optional
+	InternalLoadBalancer bool `json:"internalLoadBalancer,omitempty"`
+
+	// ExistingName is the name of a pre-created classic ELB to use for the control plane instead of
+	// creating one. The load balancer is used as is: control plane instances are registered with and
+	// deregistered from it, and it is not deleted when the cluster is deleted. It is immutable since
+	// the control plane endpoint can't be moved to another load balancer.
+	// +optional
+	ExistingName *string `json:"existingName,omitempty"`
+
+	// IdleTimeout is the time a connection to the API server may be idle before the load balancer closes it.
+	// Long running requests, e.g. kubectl exec or watches, are dropped once they are idle for this long.
+	// Must be between 1s and 4000s. Defaults to 10m.
+	// +optional
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+
+	// HealthCheck configures the health check the load balancer runs against the API server.
+	// +optional
+	HealthCheck *AWSLoadBalancerHealthCheck `json:"healthCheck,omitempty"`
+
+	// Subnets sets the IDs of the subnets the load balancer is placed in, instead of one public subnet
+	// (or private subnet, for internal load balancers) per availability zone picked by the controller.
+	// The subnets must belong to the cluster's VPC, with at most one subnet per availability zone.
+	// The additional internal load balancer, if any, always uses the private subnets.
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
+
+	// AdditionalSecurityGroups sets the IDs of security groups to attach to the load balancer in
+	// addition to the managed apiserver-lb security group, e.g. for corporate managed allowlists.
+	// Classic ELBs support at most five security groups.
+	// +kubebuilder:validation:MaxItems=4
+	// +optional
+	AdditionalSecurityGroups []string `json:"additionalSecurityGroups,omitempty"`
+
+	// ProxyProtocol enables proxy protocol (version 1) on the load balancer, so that the client address
+	// is passed on to the instances. The API server does not understand proxy protocol itself, this
+	// requires a proxy terminating it in front of the API server on the control plane instances.
+	// Defaults to false.
+	// +optional
+	ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+
+	// AccessLogs enables access logging of the load balancer to an S3 bucket.
+	// The bucket policy must allow the Elastic Load Balancing account of the region to write to it.
+	// +optional
+	AccessLogs *AWSLoadBalancerAccessLogs `json:"accessLogs,omitempty"`
+}
+
+// AWSLoadBalancerAccessLogs defines where the control plane load balancer publishes its access logs.
+type AWSLoadBalancerAccessLogs struct {
+	// BucketName is the name of the S3 bucket the access logs are stored in.
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=63
+	BucketName string `json:"bucketName"`
+
+	// BucketPrefix is the logical hierarchy created in the bucket, e.g. my-bucket-prefix/prod.
+	// Defaults to the root of the bucket.
+	// +optional
+	BucketPrefix string `json:"bucketPrefix,omitempty"`
+
+	// EmitInterval is the interval in minutes at which access logs are published. Defaults to 60.
+	// +kubebuilder:validation:Enum=5;60
+	// +optional
+	EmitInterval *int64 `json:"emitInterval,omitempty"`
+}
+
+// AWSLoadBalancerHealthCheck defines the health check of the control plane load balancer.
+// Fields that are not set keep their default value.
+type AWSLoadBalancerHealthCheck struct {
+	// Protocol is the protocol used to check the API server port. Defaults to SSL.
+	// +kubebuilder:validation:Enum=SSL;TCP
+	// +optional
+	Protocol *ClassicELBProtocol `json:"protocol,omitempty"`
+
+	// Interval is the approximate amount of time between health checks of an instance.
+	// Must be between 5s and 300s. Defaults to 10s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Timeout is the amount of time during which no response means a failed health check.
+	// Must be between 2s and 60s and less than the interval. Defaults to 5s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// HealthyThreshold is the number of consecutive successful health checks before an instance is
+	// considered healthy. Defaults to 5.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	HealthyThreshold *int64 `json:"healthyThreshold,omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed health checks before an instance is
+	// considered unhealthy. Defaults to 3.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	UnhealthyThreshold *int64 `json:"unhealthyThreshold,omitempty"`
 }
 
 // AWSClusterStatus defines the observed state of AWSCluster
@@ -100,6 +340,11 @@ type AWSClusterStatus struct {
 	Network        Network                  `json:"network,omitempty"`
 	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
 	Bastion        *Instance                `json:"bastion,omitempty"`
+
+	// SessionManagerTargets are the IDs of the running instances of the cluster that can be used
+	// as Session Manager targets, e.g. aws ssm start-session --target <id>.
+	// +optional
+	SessionManagerTargets []string `json:"sessionManagerTargets,omitempty"`
 }
 
 // +kubebuilder:object:root=true
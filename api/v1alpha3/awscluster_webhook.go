@@ -17,15 +17,300 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 // log is for logging in this package.
 var _ = logf.Log.WithName("awscluster-resource")
 
+// elbNameRegex matches names accepted by classic ELB: at most 32 alphanumeric characters or
+// hyphens, not beginning or ending with a hyphen.
+var elbNameRegex = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9]{0,31}|[-A-Za-z0-9]{0,30}[A-Za-z0-9])$`)
+
 func (r *AWSCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
 }
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha3-awscluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,versions=v1alpha3,name=validation.awscluster.infrastructure.cluster.x-k8s.io
+
+var _ webhook.Validator = &AWSCluster{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *AWSCluster) ValidateCreate() error {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, r.validateControlPlaneLoadBalancerName()...)
+	allErrs = append(allErrs, r.validateInternalLoadBalancer()...)
+	allErrs = append(allErrs, r.validateSSHKey()...)
+	allErrs = append(allErrs, r.validateSessionManager()...)
+	allErrs = append(allErrs, r.validateFlowLogs()...)
+	allErrs = append(allErrs, r.validateS3Bucket()...)
+	allErrs = append(allErrs, r.validateControlPlaneLoadBalancerHealthCheck()...)
+	allErrs = append(allErrs, r.validateControlPlaneLoadBalancerIdleTimeout()...)
+	allErrs = append(allErrs, r.validateAdditionalRoutes()...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *AWSCluster) ValidateUpdate(old runtime.Object) error {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, r.validateControlPlaneLoadBalancerName()...)
+	allErrs = append(allErrs, r.validateInternalLoadBalancer()...)
+	allErrs = append(allErrs, r.validateSSHKey()...)
+	allErrs = append(allErrs, r.validateSessionManager()...)
+	allErrs = append(allErrs, r.validateFlowLogs()...)
+	allErrs = append(allErrs, r.validateS3Bucket()...)
+	allErrs = append(allErrs, r.validateControlPlaneLoadBalancerHealthCheck()...)
+	allErrs = append(allErrs, r.validateControlPlaneLoadBalancerIdleTimeout()...)
+	allErrs = append(allErrs, r.validateAdditionalRoutes()...)
+
+	oldC := old.(*AWSCluster)
+	if oldC.Spec.GenerateSSHKey != r.Spec.GenerateSSHKey {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "generateSSHKey"), r.Spec.GenerateSSHKey, "field is immutable"))
+	}
+
+	if (oldC.Spec.APIServerPort == nil) != (r.Spec.APIServerPort == nil) ||
+		(oldC.Spec.APIServerPort != nil && *oldC.Spec.APIServerPort != *r.Spec.APIServerPort) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "apiServerPort"), r.Spec.APIServerPort, "field is immutable"))
+	}
+
+	if oldC.Spec.S3Bucket != nil && (r.Spec.S3Bucket == nil || oldC.Spec.S3Bucket.Name != r.Spec.S3Bucket.Name) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "s3Bucket", "name"), r.Spec.S3Bucket, "field is immutable once set"))
+	}
+
+	if (oldC.Spec.ControlPlaneEndpointDNS == nil) != (r.Spec.ControlPlaneEndpointDNS == nil) ||
+		(oldC.Spec.ControlPlaneEndpointDNS != nil && *oldC.Spec.ControlPlaneEndpointDNS != *r.Spec.ControlPlaneEndpointDNS) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "controlPlaneEndpointDNS"), r.Spec.ControlPlaneEndpointDNS, "field is immutable"))
+	}
+
+	if oldC.Spec.NetworkSpec.FullyPrivate != r.Spec.NetworkSpec.FullyPrivate {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "networkSpec", "fullyPrivate"), r.Spec.NetworkSpec.FullyPrivate, "field is immutable"))
+	}
+
+	// An unset tenancy is the default tenancy.
+	oldTenancy, newTenancy := oldC.Spec.NetworkSpec.VPC.InstanceTenancy, r.Spec.NetworkSpec.VPC.InstanceTenancy
+	if oldTenancy == "" {
+		oldTenancy = "default"
+	}
+	if newTenancy == "" {
+		newTenancy = "default"
+	}
+	if oldTenancy != newTenancy {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "networkSpec", "vpc", "instanceTenancy"), r.Spec.NetworkSpec.VPC.InstanceTenancy, "field is immutable"))
+	}
+
+	var oldName, newName, oldExistingName, newExistingName *string
+	var oldInternal, newInternal bool
+	if oldC.Spec.ControlPlaneLoadBalancer != nil {
+		oldName = oldC.Spec.ControlPlaneLoadBalancer.Name
+		oldExistingName = oldC.Spec.ControlPlaneLoadBalancer.ExistingName
+		oldInternal = oldC.Spec.ControlPlaneLoadBalancer.InternalLoadBalancer
+	}
+	if r.Spec.ControlPlaneLoadBalancer != nil {
+		newName = r.Spec.ControlPlaneLoadBalancer.Name
+		newExistingName = r.Spec.ControlPlaneLoadBalancer.ExistingName
+		newInternal = r.Spec.ControlPlaneLoadBalancer.InternalLoadBalancer
+	}
+	if (oldName == nil) != (newName == nil) || (oldName != nil && *oldName != *newName) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "name"), newName, "field is immutable"))
+	}
+	if (oldExistingName == nil) != (newExistingName == nil) || (oldExistingName != nil && *oldExistingName != *newExistingName) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "existingName"), newExistingName, "field is immutable"))
+	}
+	if oldInternal != newInternal {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "internalLoadBalancer"), newInternal, "field is immutable"))
+	}
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *AWSCluster) ValidateDelete() error {
+	return nil
+}
+
+func (r *AWSCluster) validateControlPlaneLoadBalancerName() field.ErrorList {
+	var allErrs field.ErrorList
+
+	lb := r.Spec.ControlPlaneLoadBalancer
+	if lb == nil || lb.Name == nil {
+		return allErrs
+	}
+
+	if !elbNameRegex.MatchString(*lb.Name) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "name"), *lb.Name, "must have a maximum of 32 alphanumeric characters or hyphens, and cannot begin or end with a hyphen"))
+	}
+
+	if lb.ExistingName != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "controlPlaneLoadBalancer", "name"), "cannot be set together with spec.controlPlaneLoadBalancer.existingName"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSCluster) validateInternalLoadBalancer() field.ErrorList {
+	var allErrs field.ErrorList
+
+	lb := r.Spec.ControlPlaneLoadBalancer
+	if lb == nil || !lb.InternalLoadBalancer {
+		return allErrs
+	}
+
+	if lb.Scheme != nil && *lb.Scheme != ClassicELBSchemeInternetFacing {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "controlPlaneLoadBalancer", "internalLoadBalancer"), "requires spec.controlPlaneLoadBalancer.scheme to be internet-facing"))
+	}
+
+	if lb.ExistingName != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "controlPlaneLoadBalancer", "internalLoadBalancer"), "cannot be set together with spec.controlPlaneLoadBalancer.existingName"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSCluster) validateSSHKey() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.GenerateSSHKey && r.Spec.SSHKeyName != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "generateSSHKey"), "cannot be set together with spec.sshKeyName"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSCluster) validateSessionManager() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.SessionManager.Enabled && r.Spec.Bastion.Enabled {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "sessionManager", "enabled"), "cannot be set together with spec.bastion.enabled"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSCluster) validateFlowLogs() field.ErrorList {
+	var allErrs field.ErrorList
+
+	spec := r.Spec.NetworkSpec.VPC.FlowLogs
+	if spec == nil {
+		return allErrs
+	}
+
+	path := field.NewPath("spec", "networkSpec", "vpc", "flowLogs")
+	switch spec.DestinationType {
+	case "", FlowLogsDestinationCloudWatchLogs:
+		if spec.LogGroupName == "" {
+			allErrs = append(allErrs, field.Required(path.Child("logGroupName"), "required if the destination type is cloud-watch-logs"))
+		}
+		if spec.DeliverLogsPermissionARN == "" {
+			allErrs = append(allErrs, field.Required(path.Child("deliverLogsPermissionARN"), "required if the destination type is cloud-watch-logs"))
+		} else if parts := strings.SplitN(spec.DeliverLogsPermissionARN, ":", 6); len(parts) != 6 || !strings.HasPrefix(parts[5], "role"+FlowLogsRolePath) {
+			allErrs = append(allErrs, field.Invalid(path.Child("deliverLogsPermissionARN"), spec.DeliverLogsPermissionARN, fmt.Sprintf("must be the ARN of a role with the path %s", FlowLogsRolePath)))
+		}
+	case FlowLogsDestinationS3:
+		if spec.S3BucketARN == "" {
+			allErrs = append(allErrs, field.Required(path.Child("s3BucketARN"), "required if the destination type is s3"))
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSCluster) validateS3Bucket() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.S3Bucket != nil && !strings.HasPrefix(r.Spec.S3Bucket.Name, S3BucketNamePrefix) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "s3Bucket", "name"), r.Spec.S3Bucket.Name, fmt.Sprintf("must start with %s", S3BucketNamePrefix)))
+	}
+
+	return allErrs
+}
+
+func (r *AWSCluster) validateControlPlaneLoadBalancerHealthCheck() field.ErrorList {
+	var allErrs field.ErrorList
+
+	lb := r.Spec.ControlPlaneLoadBalancer
+	if lb == nil || lb.HealthCheck == nil {
+		return allErrs
+	}
+	hc := lb.HealthCheck
+	fldPath := field.NewPath("spec", "controlPlaneLoadBalancer", "healthCheck")
+
+	// The defaults match the health check of the load balancer when the fields aren't set.
+	interval, timeout := 10*time.Second, 5*time.Second
+	if hc.Interval != nil {
+		interval = hc.Interval.Duration
+		if interval < 5*time.Second || interval > 300*time.Second {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), hc.Interval.Duration.String(), "must be between 5s and 300s"))
+		}
+	}
+	if hc.Timeout != nil {
+		timeout = hc.Timeout.Duration
+		if timeout < 2*time.Second || timeout > 60*time.Second {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), hc.Timeout.Duration.String(), "must be between 2s and 60s"))
+		}
+	}
+	if timeout >= interval {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), timeout.String(), fmt.Sprintf("must be less than the interval (%s)", interval)))
+	}
+
+	if hc.HealthyThreshold != nil && (*hc.HealthyThreshold < 2 || *hc.HealthyThreshold > 10) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("healthyThreshold"), *hc.HealthyThreshold, "must be between 2 and 10"))
+	}
+	if hc.UnhealthyThreshold != nil && (*hc.UnhealthyThreshold < 2 || *hc.UnhealthyThreshold > 10) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("unhealthyThreshold"), *hc.UnhealthyThreshold, "must be between 2 and 10"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSCluster) validateAdditionalRoutes() field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, sn := range r.Spec.NetworkSpec.Subnets {
+		if sn == nil {
+			continue
+		}
+		for j, route := range sn.AdditionalRoutes {
+			targets := 0
+			for _, target := range []*string{route.TransitGatewayID, route.VPCPeeringConnectionID, route.InstanceID, route.NatGatewayID} {
+				if target != nil {
+					targets++
+				}
+			}
+			if targets != 1 {
+				fldPath := field.NewPath("spec", "networkSpec", "subnets").Index(i).Child("additionalRoutes").Index(j)
+				allErrs = append(allErrs, field.Invalid(fldPath, route.DestinationCidrBlock, fmt.Sprintf("must have exactly one target, got %d", targets)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSCluster) validateControlPlaneLoadBalancerIdleTimeout() field.ErrorList {
+	var allErrs field.ErrorList
+
+	lb := r.Spec.ControlPlaneLoadBalancer
+	if lb == nil || lb.IdleTimeout == nil {
+		return allErrs
+	}
+
+	if lb.IdleTimeout.Duration < time.Second || lb.IdleTimeout.Duration > 4000*time.Second {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "idleTimeout"), lb.IdleTimeout.Duration.String(), "must be between 1s and 4000s"))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,711 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestAWSCluster_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *AWSCluster
+		wantErr bool
+	}{
+		{
+			name:    "allow clusters without a load balancer name",
+			cluster: &AWSCluster{},
+			wantErr: false,
+		},
+		{
+			name: "allow a valid load balancer name",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Name: pointer.StringPtr("my-cluster-apiserver"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure the load balancer name doesn't begin with a hyphen",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Name: pointer.StringPtr("-my-cluster"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure the load balancer name is at most 32 characters",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Name: pointer.StringPtr("a-very-long-cluster-name-apiserver"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure the load balancer name isn't set together with an existing load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Name:         pointer.StringPtr("my-cluster-apiserver"),
+						ExistingName: pointer.StringPtr("shared-apiserver"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure the internal load balancer requires an internet-facing scheme",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Scheme:               &ClassicELBSchemeInternal,
+						InternalLoadBalancer: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow the internal load balancer together with a control plane endpoint DNS record",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						InternalLoadBalancer: true,
+					},
+					ControlPlaneEndpointDNS: &ControlPlaneEndpointDNSSpec{
+						HostedZoneID: "Z1234567890",
+						RecordName:   "api.example.com",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure a generated SSH key isn't set together with an SSH key name",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SSHKeyName:     pointer.StringPtr("my-key"),
+					GenerateSSHKey: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow session manager access without a bastion",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SessionManager: SessionManager{Enabled: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure session manager access isn't enabled together with the bastion",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					Bastion:        Bastion{Enabled: true},
+					SessionManager: SessionManager{Enabled: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow flow logs published to cloudwatch logs",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							FlowLogs: &FlowLogsSpec{
+								LogGroupName:             "flow-logs",
+								DeliverLogsPermissionARN: "arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/delivery",
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "reject a flow logs delivery role outside the flow logs role path",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							FlowLogs: &FlowLogsSpec{
+								LogGroupName:             "flow-logs",
+								DeliverLogsPermissionARN: "arn:aws:iam::123456789012:role/flow-logs",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure flow logs published to cloudwatch logs have a deliver logs permission",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							FlowLogs: &FlowLogsSpec{
+								LogGroupName: "flow-logs",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow s3 bucket names with the managed prefix",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "cluster-api-provider-aws-bootstrap"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure s3 bucket names have the managed prefix",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "bootstrap"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure flow logs published to s3 have a bucket",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							FlowLogs: &FlowLogsSpec{
+								DestinationType: FlowLogsDestinationS3,
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cluster.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldCluster *AWSCluster
+		newCluster *AWSCluster
+		wantErr    bool
+	}{
+		{
+			name: "load balancer name is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Name: pointer.StringPtr("old-apiserver"),
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Name: pointer.StringPtr("new-apiserver"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "load balancer name cannot be added",
+			oldCluster: &AWSCluster{},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Name: pointer.StringPtr("new-apiserver"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "internal load balancer cannot be enabled",
+			oldCluster: &AWSCluster{},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						InternalLoadBalancer: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "generated SSH key cannot be enabled",
+			oldCluster: &AWSCluster{},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					GenerateSSHKey: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "api server port is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					APIServerPort: pointer.Int32Ptr(6443),
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					APIServerPort: pointer.Int32Ptr(443),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "api server port cannot be added",
+			oldCluster: &AWSCluster{},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					APIServerPort: pointer.Int32Ptr(443),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "s3 bucket name is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "cluster-api-provider-aws-old"},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "cluster-api-provider-aws-new"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "s3 bucket can be added",
+			oldCluster: &AWSCluster{},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "cluster-api-provider-aws-new"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "control plane endpoint dns is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneEndpointDNS: &ControlPlaneEndpointDNSSpec{
+						HostedZoneID: "Z123",
+						RecordName:   "api.old.example.com",
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneEndpointDNS: &ControlPlaneEndpointDNSSpec{
+						HostedZoneID: "Z123",
+						RecordName:   "api.new.example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "control plane endpoint dns cannot be added",
+			oldCluster: &AWSCluster{},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneEndpointDNS: &ControlPlaneEndpointDNSSpec{
+						HostedZoneID: "Z123",
+						RecordName:   "api.new.example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "control plane endpoint dns cannot be removed",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneEndpointDNS: &ControlPlaneEndpointDNSSpec{
+						HostedZoneID: "Z123",
+						RecordName:   "api.old.example.com",
+					},
+				},
+			},
+			newCluster: &AWSCluster{},
+			wantErr:    true,
+		},
+		{
+			name:       "fully private network cannot be enabled",
+			oldCluster: &AWSCluster{},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						FullyPrivate: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fully private network cannot be disabled",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						FullyPrivate: true,
+					},
+				},
+			},
+			newCluster: &AWSCluster{},
+			wantErr:    true,
+		},
+		{
+			name: "instance tenancy is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							InstanceTenancy: "default",
+						},
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							InstanceTenancy: "dedicated",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "default instance tenancy can be set explicitly",
+			oldCluster: &AWSCluster{},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							InstanceTenancy: "default",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "existing load balancer is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						ExistingName: pointer.StringPtr("old-apiserver"),
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						ExistingName: pointer.StringPtr("new-apiserver"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "existing load balancer cannot be added",
+			oldCluster: &AWSCluster{},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						ExistingName: pointer.StringPtr("my-apiserver"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "other load balancer fields can be changed",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Name: pointer.StringPtr("my-apiserver"),
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Name:                   pointer.StringPtr("my-apiserver"),
+						CrossZoneLoadBalancing: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.newCluster.ValidateUpdate(tt.oldCluster); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateHealthCheck(t *testing.T) {
+	duration := func(d time.Duration) *metav1.Duration {
+		return &metav1.Duration{Duration: d}
+	}
+
+	tests := []struct {
+		name        string
+		healthCheck *AWSLoadBalancerHealthCheck
+		wantErr     bool
+	}{
+		{
+			name:        "defaults are valid",
+			healthCheck: &AWSLoadBalancerHealthCheck{},
+			wantErr:     false,
+		},
+		{
+			name: "valid interval and timeout",
+			healthCheck: &AWSLoadBalancerHealthCheck{
+				Interval: duration(30 * time.Second),
+				Timeout:  duration(10 * time.Second),
+			},
+			wantErr: false,
+		},
+		{
+			name: "interval too short",
+			healthCheck: &AWSLoadBalancerHealthCheck{
+				Interval: duration(4 * time.Second),
+				Timeout:  duration(2 * time.Second),
+			},
+			wantErr: true,
+		},
+		{
+			name: "interval too long",
+			healthCheck: &AWSLoadBalancerHealthCheck{
+				Interval: duration(301 * time.Second),
+			},
+			wantErr: true,
+		},
+		{
+			name: "timeout too short",
+			healthCheck: &AWSLoadBalancerHealthCheck{
+				Timeout: duration(time.Second),
+			},
+			wantErr: true,
+		},
+		{
+			name: "timeout too long",
+			healthCheck: &AWSLoadBalancerHealthCheck{
+				Interval: duration(120 * time.Second),
+				Timeout:  duration(61 * time.Second),
+			},
+			wantErr: true,
+		},
+		{
+			name: "timeout not less than the interval",
+			healthCheck: &AWSLoadBalancerHealthCheck{
+				Interval: duration(10 * time.Second),
+				Timeout:  duration(10 * time.Second),
+			},
+			wantErr: true,
+		},
+		{
+			name: "timeout not less than the default interval",
+			healthCheck: &AWSLoadBalancerHealthCheck{
+				Timeout: duration(20 * time.Second),
+			},
+			wantErr: true,
+		},
+		{
+			name: "healthy threshold out of range",
+			healthCheck: &AWSLoadBalancerHealthCheck{
+				HealthyThreshold: pointer.Int64Ptr(11),
+			},
+			wantErr: true,
+		},
+		{
+			name: "unhealthy threshold out of range",
+			healthCheck: &AWSLoadBalancerHealthCheck{
+				UnhealthyThreshold: pointer.Int64Ptr(1),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						HealthCheck: tt.healthCheck,
+					},
+				},
+			}
+			if err := cluster.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := cluster.ValidateUpdate(cluster.DeepCopy()); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateIdleTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		idleTimeout time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "minimum idle timeout",
+			idleTimeout: time.Second,
+			wantErr:     false,
+		},
+		{
+			name:        "maximum idle timeout",
+			idleTimeout: 4000 * time.Second,
+			wantErr:     false,
+		},
+		{
+			name:        "idle timeout too short",
+			idleTimeout: 500 * time.Millisecond,
+			wantErr:     true,
+		},
+		{
+			name:        "idle timeout too long",
+			idleTimeout: 4001 * time.Second,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						IdleTimeout: &metav1.Duration{Duration: tt.idleTimeout},
+					},
+				},
+			}
+			if err := cluster.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := cluster.ValidateUpdate(cluster.DeepCopy()); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateAdditionalRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   Route
+		wantErr bool
+	}{
+		{
+			name: "route with a transit gateway target",
+			route: Route{
+				DestinationCidrBlock: "10.100.0.0/16",
+				TransitGatewayID:     pointer.StringPtr("tgw-1"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "route with a nat gateway target",
+			route: Route{
+				DestinationCidrBlock: "10.100.0.0/16",
+				NatGatewayID:         pointer.StringPtr("nat-1"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "route without a target",
+			route: Route{
+				DestinationCidrBlock: "10.100.0.0/16",
+			},
+			wantErr: true,
+		},
+		{
+			name: "route with several targets",
+			route: Route{
+				DestinationCidrBlock:   "10.100.0.0/16",
+				VPCPeeringConnectionID: pointer.StringPtr("pcx-1"),
+				InstanceID:             pointer.StringPtr("i-1"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							&SubnetSpec{
+								ID:               "subnet-1",
+								AdditionalRoutes: []Route{tt.route},
+							},
+						},
+					},
+				},
+			}
+			if err := cluster.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := cluster.ValidateUpdate(cluster.DeepCopy()); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -26,6 +26,12 @@ const (
 	// MachineFinalizer allows ReconcileAWSMachine to clean up AWS resources associated with AWSMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "awsmachine.infrastructure.cluster.x-k8s.io"
+
+	// DefaultIgnitionVersion is the default Ignition config specification version of the userdata.
+	DefaultIgnitionVersion = "2.3"
+
+	// GPUAMIFamilyEKS is the family of Amazon EKS optimized accelerated AMIs.
+	GPUAMIFamilyEKS = "eks"
 )
 
 // AWSMachineSpec defines the desired state of AWSMachine
@@ -34,6 +40,8 @@ type AWSMachineSpec struct {
 	ProviderID *string `json:"providerID,omitempty"`
 
 	// AMI is the reference to the AMI from which to create the machine instance.
+	// If not set, an AMI is looked up for the Kubernetes version and the architecture
+	// of the instance type, e.g. arm64 for Graviton instance types.
 	AMI AWSResourceReference `json:"ami,omitempty"`
 
 	// ImageLookupOrg is the AWS Organization ID to use for image lookup if AMI is not set.
@@ -43,6 +51,36 @@ type AWSMachineSpec struct {
 	// image lookup the AMI is not set.
 	ImageLookupBaseOS string `json:"imageLookupBaseOS,omitempty"`
 
+	// ImageLookupOwners are additional AWS account IDs owning the images to use
+	// for image lookup if AMI is not set. Images owned by ImageLookupOrg are
+	// looked up as well.
+	// +optional
+	ImageLookupOwners []string `json:"imageLookupOwners,omitempty"`
+
+	// ImageLookupFilters are additional DescribeImages filters, e.g.
+	// tag:approved=true, the images must match for image lookup if AMI is not set.
+	// +optional
+	ImageLookupFilters []Filter `json:"imageLookupFilters,omitempty"`
+
+	// ImageLookupSSMParameter is the name of an SSM parameter holding the AMI ID to use if the AMI
+	// is not set, e.g. a parameter published by an image pipeline. The parameter is resolved when
+	// the instance is created, and takes precedence over the other image lookup options.
+	// It must be under /cluster-api-provider-aws/, the only parameters of the account the controllers
+	// policy created by clusterawsadm grants access to.
+	// +optional
+	ImageLookupSSMParameter string `json:"imageLookupSSMParameter,omitempty"`
+
+	// GPUAMIFamily is the family of GPU-optimized AMIs to use for image lookup if the AMI is not
+	// set and the instance type has GPUs, so that the NVIDIA drivers exposing the GPUs to the
+	// container runtime are installed. The only valid value is eks for the Amazon EKS optimized
+	// accelerated AMI of the Kubernetes version. This AMI ships the kubelet and the NVIDIA drivers
+	// but not kubeadm, so the machine's bootstrap data must join the node without kubeadm, e.g.
+	// through the /etc/eks/bootstrap.sh script of the AMI. Instance types without GPUs use the
+	// regular image lookup.
+	// +kubebuilder:validation:Enum=eks
+	// +optional
+	GPUAMIFamily string `json:"gpuAmiFamily,omitempty"`
+
 	// InstanceType is the type of instance to create. Example: m4.xlarge
 	InstanceType string `json:"instanceType,omitempty"`
 
@@ -66,7 +104,8 @@ type AWSMachineSpec struct {
 
 	// AdditionalSecurityGroups is an array of references to security groups that should be applied to the
 	// instance. These security groups would be set in addition to any security groups defined
-	// at the cluster level or in the actuator.
+	// at the cluster level or in the actuator. Security groups are referenced by ID or by filters,
+	// e.g. tag:Name, which are resolved in the VPC of the cluster.
 	// +optional
 	AdditionalSecurityGroups []AWSResourceReference `json:"additionalSecurityGroups,omitempty"`
 
@@ -80,13 +119,83 @@ type AWSMachineSpec struct {
 	// +optional
 	Subnet *AWSResourceReference `json:"subnet,omitempty"`
 
-	// SSHKeyName is the name of the ssh key to attach to the instance. Valid values are empty string (do not use SSH keys), a valid SSH key name, or omitted (use the default SSH key name)
+	// OutpostARN is the Amazon Resource Name of the AWS Outpost to place the instance on.
+	// When set, the instance is launched in one of the cluster subnets on that Outpost and
+	// only gp2 root volumes are supported.
+	// +optional
+	OutpostARN *string `json:"outpostArn,omitempty"`
+
+	// CPUOptions sets the number of CPU cores and threads per core of the instance, e.g. to disable
+	// hyperthreading for license bound or latency sensitive workloads.
+	// Defaults to the CPU options of the instance type.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+
+	// UnlimitedCPUCredits sets the credit option for CPU usage of burstable performance instance types
+	// (T2, T3, T3a and T4g), true for unlimited and false for standard.
+	// Defaults to the credit option of the instance type.
+	// +optional
+	UnlimitedCPUCredits *bool `json:"unlimitedCpuCredits,omitempty"`
+
+	// CapacityReservationID is the ID of the On-Demand Capacity Reservation to launch the instance in.
+	// The instance type and the availability zone of the machine must match the capacity reservation.
+	// +optional
+	CapacityReservationID *string `json:"capacityReservationID,omitempty"`
+
+	// CapacityReservationPreference is the preference of the instance for open Capacity Reservations,
+	// open to run in any open Capacity Reservation with matching attributes or none to never use one.
+	// Cannot be set together with CapacityReservationID.
+	// Defaults to open.
+	// +kubebuilder:validation:Enum=open;none
+	// +optional
+	CapacityReservationPreference string `json:"capacityReservationPreference,omitempty"`
+
+	// InstanceMetadataOptions configures the instance metadata service of the instance, e.g. to
+	// require session tokens (IMDSv2) as recommended by the CIS benchmarks.
+	// Defaults to the options of the AMI, or to requiring session tokens when the controller
+	// runs with the IMDSv2 required feature gate enabled.
+	// +optional
+	InstanceMetadataOptions *InstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+
+	// Placement defines where the instance is launched, e.g. on a Dedicated Host.
+	// +optional
+	Placement *Placement `json:"placement,omitempty"`
+
+	// PlacementGroup is the placement group to launch the instance in. Placement groups which
+	// don't exist yet are created and deleted together with the cluster.
+	// +optional
+	PlacementGroup *PlacementGroup `json:"placementGroup,omitempty"`
+
+	// SSHKeyName is the name of the ssh key to attach to the instance. Valid values are empty string (do not use SSH keys), a valid SSH key name, or omitted (use the SSH key of the AWSCluster, its generated key pair, or the default SSH key name)
 	// +optional
 	SSHKeyName *string `json:"sshKeyName,omitempty"`
 
 	// RootVolume encapsulates the configuration options for the root volume
 	// +optional
-	RootVolume *RootVolume `json:"rootVolume,omitempty"`
+	RootVolume *Volume `json:"rootVolume,omitempty"`
+
+	// NonRootVolumes are additional EBS volumes attached to the instance, e.g. to keep etcd or
+	// container runtime data on dedicated volumes.
+	// +optional
+	NonRootVolumes []Volume `json:"nonRootVolumes,omitempty"`
+
+	// EphemeralVolumes maps instance store volumes of the instance type to devices, e.g. to use local
+	// disks for scratch or container storage. NVMe instance store volumes, like those of i3 instances,
+	// are always exposed and don't need to be mapped.
+	// +optional
+	EphemeralVolumes []EphemeralVolume `json:"ephemeralVolumes,omitempty"`
+
+	// AssociateElasticIP associates an Elastic IP with the instance, e.g. to give ingress nodes a
+	// stable public IP. The Elastic IP is taken from ElasticIPPool, or allocated for the machine and
+	// released when the machine is deleted. The instance must be in a public subnet.
+	// +optional
+	AssociateElasticIP bool `json:"associateElasticIP,omitempty"`
+
+	// ElasticIPPool is a list of allocation IDs of existing Elastic IPs to associate an unassociated
+	// one of with the instance. Elastic IPs from the pool are never released.
+	// Can only be set together with AssociateElasticIP.
+	// +optional
+	ElasticIPPool []string `json:"elasticIPPool,omitempty"`
 
 	// NetworkInterfaces is a list of ENIs to associate with the instance.
 	// A maximum of 2 may be specified.
@@ -94,6 +203,14 @@ type AWSMachineSpec struct {
 	// +kubebuilder:validation:MaxItems=2
 	NetworkInterfaces []string `json:"networkInterfaces,omitempty"`
 
+	// NetworkInterfaceSpecs are network interfaces created and attached when the instance is launched,
+	// e.g. Elastic Fabric Adapters for MPI or ML training workloads or secondary interfaces of multi-homed
+	// nodes. They default to the subnet and the security groups of the instance.
+	// The network interface with device index 0 is the primary network interface of the instance.
+	// Cannot be set together with NetworkInterfaces.
+	// +optional
+	NetworkInterfaceSpecs []NetworkInterfaceSpec `json:"networkInterfaceSpecs,omitempty"`
+
 	// UncompressedUserData specify whether the user data is gzip-compressed before it is sent to ec2 instance.
 	// cloud-init has built-in support for gzip-compressed user data
 	// user data stored in aws secret manager is always gzip-compressed.
@@ -105,6 +222,43 @@ type AWSMachineSpec struct {
 	// CloudInit is used.
 	// +optional
 	CloudInit CloudInit `json:"cloudInit,omitempty"`
+
+	// Bottlerocket, when set, launches the machine with Bottlerocket OS. The kubeadm bootstrap data
+	// is rendered into Bottlerocket TOML settings, and the AMI is looked up from the Bottlerocket
+	// SSM parameters if AMI is not set. Bottlerocket is only supported on worker machines, so it can't
+	// be set on machines with the cluster.x-k8s.io/control-plane label. It requires
+	// cloudInit.insecureSkipSecretsManager to be set.
+	// +optional
+	Bottlerocket *BottlerocketSpec `json:"bottlerocket,omitempty"`
+
+	// Ignition, when set, treats the bootstrap data as an Ignition config, e.g. for Flatcar Container
+	// Linux or Fedora CoreOS. The bootstrap data is stored in the S3 bucket of the cluster and the
+	// instance is launched with uncompressed user data pointing at it. Requires the AWSCluster's
+	// spec.s3Bucket and cloudInit.insecureSkipSecretsManager to be set.
+	// +optional
+	Ignition *Ignition `json:"ignition,omitempty"`
+}
+
+// Ignition defines options related to machines bootstrapped with Ignition.
+type Ignition struct {
+	// Version is the Ignition config specification version of the user data pointing at the bootstrap data.
+	// Defaults to 2.3.
+	// +kubebuilder:validation:Enum="2.3";"3.0";"3.1"
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// BottlerocketSpec defines options for machines running Bottlerocket OS.
+type BottlerocketSpec struct {
+	// AdminContainer enables or disables the admin host container.
+	// Defaults to the setting of the Bottlerocket image.
+	// +optional
+	AdminContainer *bool `json:"adminContainer,omitempty"`
+
+	// ControlContainer enables or disables the control host container, which runs
+	// the SSM agent. Defaults to the setting of the Bottlerocket image.
+	// +optional
+	ControlContainer *bool `json:"controlContainer,omitempty"`
 }
 
 // CloudInit defines options related to the bootstrapping systems where
@@ -125,6 +279,25 @@ type CloudInit struct {
 	// the workload cluster.
 	// +optional
 	SecretPrefix string `json:"secretPrefix,omitempty"`
+
+	// UserDataParts are additional cloud-init parts merged with the bootstrap data, e.g. to install
+	// certificates or configure registry mirrors without changing the bootstrap provider.
+	// Shell scripts run before the runcmd commands of the bootstrap data, such as kubeadm, and
+	// cloud-configs are merged with the bootstrap data with their lists appended, so their runcmd
+	// commands run after it. Requires bootstrap data in the cloud-config or shell script format.
+	// +optional
+	UserDataParts []UserDataPart `json:"userDataParts,omitempty"`
+}
+
+// UserDataPart defines a part of a cloud-init multi-part user data document.
+type UserDataPart struct {
+	// ContentType is the MIME type of the part.
+	// +kubebuilder:validation:Enum=text/cloud-config;text/x-shellscript;text/cloud-boothook
+	ContentType string `json:"contentType"`
+
+	// Content is the content of the part.
+	// +kubebuilder:validation:MinLength=1
+	Content string `json:"content"`
 }
 
 // AWSMachineStatus defines the observed state of AWSMachine
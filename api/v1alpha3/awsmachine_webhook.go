@@ -17,12 +17,16 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -31,6 +35,19 @@ import (
 // log is for logging in this package.
 var _ = logf.Log.WithName("awsmachine-resource")
 
+// burstableInstanceTypeRegex matches the burstable performance instance types, e.g. t3.medium.
+var burstableInstanceTypeRegex = regexp.MustCompile(`^t(2|3|3a|4g)\.`)
+
+// kmsKeyRegex matches the KMS key identifiers accepted for EBS encryption: a key ID, an alias,
+// or the ARN of either.
+var kmsKeyRegex = regexp.MustCompile(`^(arn:aws[a-z-]*:kms:[a-z0-9-]+:[0-9]{12}:(key/[0-9a-f-]+|alias/[a-zA-Z0-9/_-]+)|alias/[a-zA-Z0-9/_-]+|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+
+// minRootVolumeSize is the smallest root volume size (in Gi) accepted for a machine.
+const minRootVolumeSize = 8
+
+// ImageLookupSSMParameterPrefix is the path of the SSM parameters machines can resolve their AMI from.
+const ImageLookupSSMParameterPrefix = "/cluster-api-provider-aws/"
+
 func (r *AWSMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
@@ -46,7 +63,21 @@ func (r *AWSMachine) ValidateCreate() error {
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, r.validateCloudInitSecret()...)
+	allErrs = append(allErrs, r.validateRootVolumeSize()...)
+	allErrs = append(allErrs, r.validateImageLookupSSMParameter()...)
 	allErrs = append(allErrs, r.validateVolumeTypeIOPS()...)
+	allErrs = append(allErrs, r.validateVolumeDeviceNames()...)
+	allErrs = append(allErrs, r.validateVolumeEncryptionKeys()...)
+	allErrs = append(allErrs, r.validateOutpostVolumeType()...)
+	allErrs = append(allErrs, r.validatePlacementGroup()...)
+	allErrs = append(allErrs, r.validatePlacement()...)
+	allErrs = append(allErrs, r.validateNetworkInterfaceSpecs()...)
+	allErrs = append(allErrs, r.validateCapacityReservation()...)
+	allErrs = append(allErrs, r.validateUnlimitedCPUCredits()...)
+	allErrs = append(allErrs, r.validateElasticIP()...)
+	allErrs = append(allErrs, r.validateAdditionalSecurityGroups()...)
+	allErrs = append(allErrs, r.validateBottlerocket()...)
+	allErrs = append(allErrs, r.validateIgnition()...)
 
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
@@ -122,6 +153,34 @@ func (r *AWSMachine) validateCloudInitSecret() field.ErrorList {
 	return allErrs
 }
 
+func (r *AWSMachine) validateRootVolumeSize() field.ErrorList {
+	return validateRootVolumeSize(r.Spec.RootVolume, field.NewPath("spec", "rootVolume", "size"))
+}
+
+func validateRootVolumeSize(rootVolume *RootVolume, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if rootVolume != nil && rootVolume.Size < minRootVolumeSize {
+		allErrs = append(allErrs, field.Invalid(fldPath, rootVolume.Size, fmt.Sprintf("must be at least %d", minRootVolumeSize)))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateImageLookupSSMParameter() field.ErrorList {
+	return validateImageLookupSSMParameter(r.Spec.ImageLookupSSMParameter, field.NewPath("spec", "imageLookupSSMParameter"))
+}
+
+func validateImageLookupSSMParameter(name string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if name != "" && !strings.HasPrefix(name, ImageLookupSSMParameterPrefix) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, fmt.Sprintf("must start with %s", ImageLookupSSMParameterPrefix)))
+	}
+
+	return allErrs
+}
+
 func (r *AWSMachine) validateVolumeTypeIOPS() field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -129,6 +188,228 @@ func (r *AWSMachine) validateVolumeTypeIOPS() field.ErrorList {
 		allErrs = append(allErrs, field.Required(field.NewPath("spec.rootVolumeOptions.iops"), "iops required if type is 'io1'"))
 	}
 
+	for i, volume := range r.Spec.NonRootVolumes {
+		if volume.Type == "io1" && volume.IOPS == 0 {
+			allErrs = append(allErrs, field.Required(field.NewPath("spec", "nonRootVolumes").Index(i).Child("iops"), "iops required if type is 'io1'"))
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateVolumeDeviceNames() field.ErrorList {
+	return validateVolumeDeviceNames(&r.Spec, field.NewPath("spec"))
+}
+
+func validateVolumeDeviceNames(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	deviceNames := map[string]bool{}
+	for i, volume := range spec.NonRootVolumes {
+		if volume.DeviceName == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("nonRootVolumes").Index(i).Child("deviceName"), "device name is required for non-root volumes"))
+			continue
+		}
+		if deviceNames[volume.DeviceName] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("nonRootVolumes").Index(i).Child("deviceName"), volume.DeviceName))
+		}
+		deviceNames[volume.DeviceName] = true
+	}
+
+	for i, volume := range spec.EphemeralVolumes {
+		if deviceNames[volume.DeviceName] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("ephemeralVolumes").Index(i).Child("deviceName"), volume.DeviceName))
+		}
+		deviceNames[volume.DeviceName] = true
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateVolumeEncryptionKeys() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.RootVolume != nil && r.Spec.RootVolume.EncryptionKey != "" && !kmsKeyRegex.MatchString(r.Spec.RootVolume.EncryptionKey) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "rootVolume", "encryptionKey"), r.Spec.RootVolume.EncryptionKey, "must be a KMS key ID, alias or ARN"))
+	}
+
+	for i, volume := range r.Spec.NonRootVolumes {
+		if volume.EncryptionKey != "" && !kmsKeyRegex.MatchString(volume.EncryptionKey) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "nonRootVolumes").Index(i).Child("encryptionKey"), volume.EncryptionKey, "must be a KMS key ID, alias or ARN"))
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateOutpostVolumeType() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.OutpostARN != nil && r.Spec.RootVolume != nil && r.Spec.RootVolume.Type != "" && r.Spec.RootVolume.Type != "gp2" {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("spec", "rootVolume", "type"), r.Spec.RootVolume.Type, []string{"gp2"}))
+	}
+
+	if r.Spec.OutpostARN != nil {
+		for i, volume := range r.Spec.NonRootVolumes {
+			if volume.Type != "" && volume.Type != "gp2" {
+				allErrs = append(allErrs, field.NotSupported(field.NewPath("spec", "nonRootVolumes").Index(i).Child("type"), volume.Type, []string{"gp2"}))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validatePlacementGroup() field.ErrorList {
+	var allErrs field.ErrorList
+
+	pg := r.Spec.PlacementGroup
+	if pg == nil || pg.Strategy == "" || pg.Strategy == "partition" {
+		return allErrs
+	}
+
+	if pg.PartitionCount != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "placementGroup", "partitionCount"), "can only be set for placement groups with the partition strategy"))
+	}
+	if pg.PartitionNumber != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "placementGroup", "partitionNumber"), "can only be set for placement groups with the partition strategy"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validatePlacement() field.ErrorList {
+	var allErrs field.ErrorList
+
+	p := r.Spec.Placement
+	if p == nil {
+		return allErrs
+	}
+
+	if p.HostID != nil && p.HostResourceGroupARN != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "placement", "hostResourceGroupArn"), "cannot be set together with spec.placement.hostID"))
+	}
+
+	if p.Tenancy != nil && *p.Tenancy != "host" && (p.HostID != nil || p.HostResourceGroupARN != nil) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "placement", "tenancy"), *p.Tenancy, "must be host when launching on a Dedicated Host"))
+	}
+
+	if p.Affinity != nil && p.HostID == nil && p.HostResourceGroupARN == nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "placement", "affinity"), "can only be set together with spec.placement.hostID or spec.placement.hostResourceGroupArn"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateNetworkInterfaceSpecs() field.ErrorList {
+	var allErrs field.ErrorList
+
+	specs := r.Spec.NetworkInterfaceSpecs
+	if len(specs) == 0 {
+		return allErrs
+	}
+
+	if len(r.Spec.NetworkInterfaces) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "networkInterfaceSpecs"), "cannot be set together with spec.networkInterfaces"))
+	}
+
+	deviceIndexes := map[int64]bool{}
+	for i, spec := range specs {
+		if deviceIndexes[spec.DeviceIndex] {
+			allErrs = append(allErrs, field.Duplicate(field.NewPath("spec", "networkInterfaceSpecs").Index(i).Child("deviceIndex"), spec.DeviceIndex))
+		}
+		deviceIndexes[spec.DeviceIndex] = true
+	}
+
+	if !deviceIndexes[0] {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "networkInterfaceSpecs"), "must include the primary network interface with device index 0"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateCapacityReservation() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.CapacityReservationID != nil && r.Spec.CapacityReservationPreference != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "capacityReservationPreference"), "cannot be set together with spec.capacityReservationID"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateUnlimitedCPUCredits() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.UnlimitedCPUCredits != nil && !burstableInstanceTypeRegex.MatchString(r.Spec.InstanceType) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "unlimitedCpuCredits"), "can only be set for burstable performance instance types"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateElasticIP() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(r.Spec.ElasticIPPool) > 0 && !r.Spec.AssociateElasticIP {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "elasticIPPool"), "can only be set together with spec.associateElasticIP"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateAdditionalSecurityGroups() field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, sg := range r.Spec.AdditionalSecurityGroups {
+		if sg.ID == nil && len(sg.Filters) == 0 {
+			allErrs = append(allErrs, field.Required(field.NewPath("spec", "additionalSecurityGroups").Index(i), "either id or filters must be set"))
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateBottlerocket() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.Bottlerocket == nil {
+		return allErrs
+	}
+
+	if !r.Spec.CloudInit.InsecureSkipSecretsManager {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "cloudInit", "insecureSkipSecretsManager"), "must be true if spec.bottlerocket is set"))
+	}
+	if len(r.Spec.CloudInit.UserDataParts) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "cloudInit", "userDataParts"), "cannot be set if spec.bottlerocket is set"))
+	}
+	if r.Spec.GPUAMIFamily != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "gpuAmiFamily"), "cannot be set if spec.bottlerocket is set"))
+	}
+	if _, ok := r.Labels[clusterv1.MachineControlPlaneLabelName]; ok {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "bottlerocket"), "cannot be set on control plane machines"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachine) validateIgnition() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.Ignition == nil {
+		return allErrs
+	}
+
+	if !r.Spec.CloudInit.InsecureSkipSecretsManager {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "cloudInit", "insecureSkipSecretsManager"), "must be true if spec.ignition is set"))
+	}
+	if len(r.Spec.CloudInit.UserDataParts) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "cloudInit", "userDataParts"), "cannot be set if spec.ignition is set"))
+	}
+	if r.Spec.Bottlerocket != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "bottlerocket"), "cannot be set together with spec.ignition"))
+	}
+
 	return allErrs
 }
 
@@ -19,6 +19,7 @@ package v1alpha3
 import (
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
 
@@ -32,13 +33,387 @@ func TestAWSMachine_ValidateCreate(t *testing.T) {
 			name: "ensure IOPS exists if type equal to io1",
 			machine: &AWSMachine{
 				Spec: AWSMachineSpec{
-					RootVolume: &RootVolume{
+					RootVolume: &Volume{
 						Type: "io1",
 					},
 				},
 			},
 			wantErr: true,
 		},
+		{
+			name: "ensure outpost machines use gp2 root volumes",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					OutpostARN: pointer.StringPtr("arn:aws:outposts:us-east-1:123456789012:outpost/op-0123456789abcdef0"),
+					RootVolume: &Volume{
+						Type: "io1",
+						IOPS: 100,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure partition numbers are only set for partition placement groups",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PlacementGroup: &PlacementGroup{
+						Name:            "hpc",
+						Strategy:        "spread",
+						PartitionNumber: pointer.Int64Ptr(1),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure host ID and host resource group aren't both set",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Placement: &Placement{
+						HostID:               pointer.StringPtr("h-0123456789abcdef0"),
+						HostResourceGroupARN: pointer.StringPtr("arn:aws:resource-groups:us-east-1:123456789012:group/byol"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure Dedicated Host machines use the host tenancy",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Placement: &Placement{
+						HostID:  pointer.StringPtr("h-0123456789abcdef0"),
+						Tenancy: pointer.StringPtr("dedicated"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure network interface specs include the primary network interface",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NetworkInterfaceSpecs: []NetworkInterfaceSpec{
+						{DeviceIndex: 1, InterfaceType: "efa"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure network interface specs have unique device indexes",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NetworkInterfaceSpecs: []NetworkInterfaceSpec{
+						{DeviceIndex: 0, InterfaceType: "efa"},
+						{DeviceIndex: 0},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure network interface specs and network interfaces aren't both set",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NetworkInterfaces: []string{"eni-0123456789abcdef0"},
+					NetworkInterfaceSpecs: []NetworkInterfaceSpec{
+						{DeviceIndex: 0, InterfaceType: "efa"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow an efa primary network interface",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NetworkInterfaceSpecs: []NetworkInterfaceSpec{
+						{DeviceIndex: 0, InterfaceType: "efa"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure capacity reservation ID and preference aren't both set",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					CapacityReservationID:         pointer.StringPtr("cr-0123456789abcdef0"),
+					CapacityReservationPreference: "none",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure unlimited cpu credits are only set for burstable instance types",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:        "m5.large",
+					UnlimitedCPUCredits: pointer.BoolPtr(true),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow unlimited cpu credits for burstable instance types",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:        "t3a.medium",
+					UnlimitedCPUCredits: pointer.BoolPtr(true),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure non-root volumes have a device name",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NonRootVolumes: []Volume{
+						{Size: 50},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure non-root volumes have unique device names",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NonRootVolumes: []Volume{
+						{DeviceName: "/dev/sdb", Size: 50},
+						{DeviceName: "/dev/sdb", Size: 100},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure ephemeral volumes don't reuse non-root volume device names",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NonRootVolumes: []Volume{
+						{DeviceName: "/dev/sdb", Size: 50},
+					},
+					EphemeralVolumes: []EphemeralVolume{
+						{DeviceName: "/dev/sdb", VirtualName: "ephemeral0"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure volume encryption keys are KMS key identifiers",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NonRootVolumes: []Volume{
+						{DeviceName: "/dev/sdb", Size: 50, EncryptionKey: "etcd-key"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow per-volume customer managed keys",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					RootVolume: &Volume{
+						Size:          50,
+						EncryptionKey: "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+					},
+					NonRootVolumes: []Volume{
+						{DeviceName: "/dev/sdb", Size: 50, EncryptionKey: "alias/etcd"},
+						{DeviceName: "/dev/sdc", Size: 50, EncryptionKey: "1234abcd-12ab-34cd-56ef-1234567890ab"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure io1 non-root volumes have iops",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NonRootVolumes: []Volume{
+						{DeviceName: "/dev/sdb", Size: 50, Type: "io1"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure an elastic IP pool is only set when associating an elastic IP",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					ElasticIPPool: []string{"eipalloc-0123456789abcdef0"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure additional security groups are referenced by id or filters",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AdditionalSecurityGroups: []AWSResourceReference{
+						{ARN: pointer.StringPtr("arn:aws:ec2:us-east-1:123456789012:security-group/sg-1")},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure bottlerocket machines skip secrets manager",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Bottlerocket: &BottlerocketSpec{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure bottlerocket machines don't set user data parts",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Bottlerocket: &BottlerocketSpec{},
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+						UserDataParts: []UserDataPart{
+							{ContentType: "text/x-shellscript", Content: "#!/bin/sh"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure bottlerocket machines don't set a gpu ami family",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Bottlerocket: &BottlerocketSpec{},
+					GPUAMIFamily: GPUAMIFamilyEKS,
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure bottlerocket isn't used on control plane machines",
+			machine: &AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"cluster.x-k8s.io/control-plane": "",
+					},
+				},
+				Spec: AWSMachineSpec{
+					Bottlerocket: &BottlerocketSpec{},
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow bottlerocket machines that skip secrets manager",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Bottlerocket: &BottlerocketSpec{AdminContainer: pointer.BoolPtr(true)},
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure ignition machines skip secrets manager",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Ignition: &Ignition{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure ignition and bottlerocket aren't both set",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Ignition:     &Ignition{},
+					Bottlerocket: &BottlerocketSpec{},
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow ignition machines that skip secrets manager",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Ignition: &Ignition{Version: "2.3"},
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allow gp2 root volumes on outposts",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					OutpostARN: pointer.StringPtr("arn:aws:outposts:us-east-1:123456789012:outpost/op-0123456789abcdef0"),
+					RootVolume: &Volume{
+						Size: 8,
+						Type: "gp2",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allow ssm parameters under the managed path",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					ImageLookupSSMParameter: "/cluster-api-provider-aws/golden-images/latest",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure ssm parameters are under the managed path",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					ImageLookupSSMParameter: "/golden-images/latest",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensure root volumes are at least 8Gi",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					RootVolume: &Volume{
+						Size: 4,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow non-root volumes smaller than 8Gi",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NonRootVolumes: []Volume{
+						{DeviceName: "/dev/sdb", Size: 1},
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
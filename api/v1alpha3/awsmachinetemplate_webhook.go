@@ -53,6 +53,10 @@ func (r *AWSMachineTemplate) ValidateCreate() error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "providerID"), "cannot be set in templates"))
 	}
 
+	allErrs = append(allErrs, validateRootVolumeSize(spec.RootVolume, field.NewPath("spec", "template", "spec", "rootVolume", "size"))...)
+	allErrs = append(allErrs, validateImageLookupSSMParameter(spec.ImageLookupSSMParameter, field.NewPath("spec", "template", "spec", "imageLookupSSMParameter"))...)
+	allErrs = append(allErrs, validateVolumeDeviceNames(&spec, field.NewPath("spec", "template", "spec"))...)
+
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
@@ -59,6 +59,72 @@ func TestAWSMachineTemplateInvalid(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "don't allow root volumes smaller than 8Gi",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							RootVolume: &Volume{
+								Size: 4,
+							},
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "don't allow non-root volumes without a device name",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							NonRootVolumes: []Volume{
+								{Size: 50},
+							},
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "don't allow duplicate volume device names",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							NonRootVolumes: []Volume{
+								{DeviceName: "/dev/sdb", Size: 50},
+								{DeviceName: "/dev/sdb", Size: 100},
+							},
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "allow non-root volumes with distinct device names",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							NonRootVolumes: []Volume{
+								{DeviceName: "/dev/sdb", Size: 50},
+								{DeviceName: "/dev/sdc", Size: 100},
+							},
+						},
+					},
+				},
+			},
+			wantError: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
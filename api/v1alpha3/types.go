@@ -22,6 +22,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -76,6 +77,43 @@ type Network struct {
 
 	// APIServerELB is the Kubernetes api server classic load balancer.
 	APIServerELB ClassicELB `json:"apiServerElb,omitempty"`
+
+	// APIServerInternalELB is the internal Kubernetes api server classic load balancer, if any.
+	// +optional
+	APIServerInternalELB *ClassicELB `json:"apiServerInternalElb,omitempty"`
+
+	// Subnets are the subnets discovered through the subnet filters of the network spec, if any.
+	// +optional
+	Subnets Subnets `json:"subnets,omitempty"`
+
+	// TransitGatewayAttachment is the attachment of the VPC to a Transit Gateway, if any.
+	// +optional
+	TransitGatewayAttachment *TransitGatewayAttachment `json:"transitGatewayAttachment,omitempty"`
+
+	// VPCPeeringConnection is the peering connection between the VPC and the peer VPC, if any.
+	// +optional
+	VPCPeeringConnection *VPCPeeringConnection `json:"vpcPeeringConnection,omitempty"`
+}
+
+// TransitGatewayAttachment describes the attachment of a VPC to a Transit Gateway.
+type TransitGatewayAttachment struct {
+	// ID is the id of the Transit Gateway attachment.
+	ID string `json:"id"`
+
+	// State is the state of the Transit Gateway attachment.
+	State string `json:"state,omitempty"`
+}
+
+// VPCPeeringConnection describes the peering connection between a VPC and its peer VPC.
+type VPCPeeringConnection struct {
+	// ID is the id of the VPC peering connection.
+	ID string `json:"id"`
+
+	// State is the state of the VPC peering connection.
+	State string `json:"state,omitempty"`
+
+	// PeerCidrBlock is the CIDR block of the peer VPC.
+	PeerCidrBlock string `json:"peerCidrBlock,omitempty"`
 }
 
 // ClassicELBScheme defines the scheme of a classic load balancer.
@@ -117,6 +155,10 @@ type ClassicELB struct {
 	// DNSName is the dns name of the load balancer.
 	DNSName string `json:"dnsName,omitempty"`
 
+	// CanonicalHostedZoneID is the id of the Route53 hosted zone of the load balancer DNS name.
+	// +optional
+	CanonicalHostedZoneID string `json:"canonicalHostedZoneID,omitempty"`
+
 	// Scheme is the load balancer scheme, either internet-facing or private.
 	Scheme ClassicELBScheme `json:"scheme,omitempty"`
 
@@ -138,6 +180,10 @@ type ClassicELB struct {
 	// Attributes defines extra attributes associated with the load balancer.
 	Attributes ClassicELBAttributes `json:"attributes,omitempty"`
 
+	// ProxyProtocol is true when the load balancer sends the proxy protocol header to its instances.
+	// +optional
+	ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+
 	// Tags is a map of tags associated with the load balancer.
 	Tags map[string]string `json:"tags,omitempty"`
 }
@@ -151,6 +197,23 @@ type ClassicELBAttributes struct {
 	// CrossZoneLoadBalancing enables the classic load balancer load balancing.
 	// +optional
 	CrossZoneLoadBalancing bool `json:"crossZoneLoadBalancing,omitempty"`
+
+	// AccessLog defines the access logging of the load balancer, it's nil when access logging is disabled.
+	// +optional
+	AccessLog *ClassicELBAccessLog `json:"accessLog,omitempty"`
+}
+
+// ClassicELBAccessLog defines the access logging configuration of a classic load balancer.
+type ClassicELBAccessLog struct {
+	// S3BucketName is the name of the S3 bucket the access logs are stored in.
+	S3BucketName string `json:"s3BucketName"`
+
+	// S3BucketPrefix is the logical hierarchy created in the bucket.
+	// +optional
+	S3BucketPrefix string `json:"s3BucketPrefix,omitempty"`
+
+	// EmitInterval is the interval in minutes at which access logs are published.
+	EmitInterval int64 `json:"emitInterval"`
 }
 
 // ClassicELBListener defines an AWS classic load balancer listener.
@@ -179,6 +242,165 @@ type NetworkSpec struct {
 	// Subnets configuration.
 	// +optional
 	Subnets Subnets `json:"subnets,omitempty"`
+
+	// SubnetFilters selects the subnets of an unmanaged VPC to use, e.g. by tag,
+	// instead of listing every subnet in Subnets. Matching subnets are recorded in the cluster status.
+	// +optional
+	SubnetFilters []Filter `json:"subnetFilters,omitempty"`
+
+	// AvailabilityZones restricts the availability zones default subnets are created in.
+	// When empty, any available zone of the region can be used. In both cases the zones
+	// actually used are capped by AvailabilityZoneUsageLimit.
+	// +optional
+	AvailabilityZones []string `json:"availabilityZones,omitempty"`
+
+	// AvailabilityZoneUsageLimit is the maximum number of availability zones default subnets
+	// are spread across, with one private and one public subnet per zone.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AvailabilityZoneUsageLimit *int `json:"availabilityZoneUsageLimit,omitempty"`
+
+	// AdditionalControlPlaneIngressRules is an optional set of ingress rules to add to the control plane security group.
+	// +optional
+	AdditionalControlPlaneIngressRules IngressRules `json:"additionalControlPlaneIngressRules,omitempty"`
+
+	// AdditionalNodeIngressRules is an optional set of ingress rules to add to the node security group.
+	// +optional
+	AdditionalNodeIngressRules IngressRules `json:"additionalNodeIngressRules,omitempty"`
+
+	// NodeEgressRules is an optional set of egress rules for the node security group.
+	// When set, the default rule allowing all outbound traffic is removed and the node security group
+	// only allows traffic to the cluster security groups and to the destinations of these rules.
+	// For egress rules, CidrBlocks and SourceSecurityGroupIDs are the destinations of the traffic.
+	// Removing all the rules restores the default rule.
+	// +optional
+	NodeEgressRules IngressRules `json:"nodeEgressRules,omitempty"`
+
+	// ElasticFabricAdapterSupport allows all traffic between instances in the node security group,
+	// which Elastic Fabric Adapter network interfaces require. Enable it for clusters with AWSMachines
+	// that launch EFA network interfaces.
+	// +optional
+	ElasticFabricAdapterSupport bool `json:"elasticFabricAdapterSupport,omitempty"`
+
+	// TransitGateway configures the attachment of a managed VPC to an existing Transit Gateway.
+	// Removing it deletes the attachment and the routes through the Transit Gateway.
+	// +optional
+	TransitGateway *TransitGatewaySpec `json:"transitGateway,omitempty"`
+
+	// NetworkACLs configures the network ACLs the provider manages for the subnets of a managed VPC.
+	// Subnets of a tier without a configuration keep the default network ACL of the VPC. Removing the
+	// configuration of a tier associates its subnets with the default network ACL again.
+	// +optional
+	NetworkACLs *NetworkACLsSpec `json:"networkACLs,omitempty"`
+
+	// FullyPrivate creates a managed VPC without an internet gateway, NAT gateways or public subnets.
+	// The cluster relies on existing connectivity, e.g. Direct Connect or a Transit Gateway, and VPC endpoints
+	// to reach the outside world. The control plane load balancer is always internal and the bastion host,
+	// if enabled, has no public IP. It is immutable since the gateways and subnets of an existing VPC
+	// are not converted.
+	// +optional
+	FullyPrivate bool `json:"fullyPrivate,omitempty"`
+
+	// SecurityGroupDriftRemediation enables the periodic reconciliation of security group rules.
+	// When set, ingress and node egress rules added or removed out-of-band are detected and reverted
+	// to the declared state, and an event describing the drift is emitted. The rules last applied are
+	// tracked in an annotation, so that changes of the spec aren't reported as drift.
+	// +optional
+	SecurityGroupDriftRemediation *SecurityGroupDriftRemediationSpec `json:"securityGroupDriftRemediation,omitempty"`
+}
+
+// SecurityGroupDriftRemediationSpec configures the periodic reconciliation of security group rules.
+type SecurityGroupDriftRemediationSpec struct {
+	// Interval is how often security group rules are checked for drift.
+	// Defaults to 5 minutes.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// NetworkACLsSpec configures the network ACLs associated with each subnet tier.
+type NetworkACLsSpec struct {
+	// Public is the network ACL associated with the public subnets.
+	// +optional
+	Public *NetworkACLSpec `json:"public,omitempty"`
+
+	// Private is the network ACL associated with the private subnets.
+	// +optional
+	Private *NetworkACLSpec `json:"private,omitempty"`
+}
+
+// NetworkACLSpec defines the rules of a network ACL.
+// Rules are evaluated in ascending rule number order, traffic matching no rule is denied.
+type NetworkACLSpec struct {
+	// Ingress are the inbound rules of the network ACL.
+	// +optional
+	Ingress []NetworkACLRule `json:"ingress,omitempty"`
+
+	// Egress are the outbound rules of the network ACL.
+	// +optional
+	Egress []NetworkACLRule `json:"egress,omitempty"`
+}
+
+// NetworkACLRuleAction defines whether a network ACL rule allows or denies traffic.
+type NetworkACLRuleAction string
+
+var (
+	// NetworkACLRuleActionAllow allows the traffic matching the rule.
+	NetworkACLRuleActionAllow = NetworkACLRuleAction("allow")
+
+	// NetworkACLRuleActionDeny denies the traffic matching the rule.
+	NetworkACLRuleActionDeny = NetworkACLRuleAction("deny")
+)
+
+// NetworkACLRule defines a network ACL rule.
+type NetworkACLRule struct {
+	// RuleNumber is the unique number of the rule within its direction, rules are evaluated in ascending order.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32766
+	RuleNumber int64 `json:"ruleNumber"`
+
+	// Protocol is the protocol the rule applies to. Rules only apply to IPv4 traffic, so ICMPv6 isn't supported.
+	// +kubebuilder:validation:Enum=-1;4;tcp;udp;icmp
+	Protocol SecurityGroupProtocol `json:"protocol"`
+
+	// Action is either allow or deny.
+	// +kubebuilder:validation:Enum=allow;deny
+	Action NetworkACLRuleAction `json:"action"`
+
+	// CidrBlock is the IPv4 network range the rule applies to.
+	CidrBlock string `json:"cidrBlock"`
+
+	// FromPort is the first port of the range the rule applies to, for tcp and udp rules.
+	// +optional
+	FromPort int64 `json:"fromPort,omitempty"`
+
+	// ToPort is the last port of the range the rule applies to, for tcp and udp rules.
+	// +optional
+	ToPort int64 `json:"toPort,omitempty"`
+}
+
+// TransitGatewaySpec configures the attachment of a managed VPC to an existing Transit Gateway.
+type TransitGatewaySpec struct {
+	// ID is the id of the Transit Gateway to attach the VPC to.
+	ID string `json:"id"`
+
+	// SubnetIDs are the subnets the attachment is placed in, at most one per availability zone.
+	// Defaults to one private subnet per availability zone.
+	// +optional
+	SubnetIDs []string `json:"subnetIDs,omitempty"`
+
+	// AssociationRouteTableID is the id of the Transit Gateway route table to associate the attachment with.
+	// +optional
+	AssociationRouteTableID *string `json:"associationRouteTableID,omitempty"`
+
+	// PropagationRouteTableIDs are the ids of the Transit Gateway route tables the VPC routes are propagated to.
+	// +optional
+	PropagationRouteTableIDs []string `json:"propagationRouteTableIDs,omitempty"`
+
+	// DestinationCidrBlocks are the CIDR blocks, e.g. on-premises networks, that are routed
+	// from the cluster subnets through the Transit Gateway.
+	// +optional
+	DestinationCidrBlocks []string `json:"destinationCidrBlocks,omitempty"`
 }
 
 // VPCSpec configures an AWS VPC.
@@ -194,10 +416,99 @@ type VPCSpec struct {
 	// +optional
 	InternetGatewayID *string `json:"internetGatewayId,omitempty"`
 
+	// InstanceTenancy is the tenancy of the instances launched into a managed VPC.
+	// Instances launched into a VPC with dedicated tenancy run on single-tenant hardware.
+	// Defaults to default. It is immutable since the tenancy can only be set when the VPC is created.
+	// +kubebuilder:validation:Enum=default;dedicated
+	// +optional
+	InstanceTenancy string `json:"instanceTenancy,omitempty"`
+
+	// PrivateEndpoints configures the VPC endpoints the provider creates in a managed VPC.
+	// When set, the cluster can reach the listed AWS services without going through a NAT gateway.
+	// +optional
+	PrivateEndpoints *PrivateEndpointsSpec `json:"privateEndpoints,omitempty"`
+
+	// NatGatewayStrategy defines how NAT gateways are provisioned for the private subnets of a managed VPC.
+	// Defaults to perAZ. Switching to none deletes the NAT gateways, their Elastic IPs and the default routes
+	// of the private subnets through them.
+	// +kubebuilder:validation:Enum=single;perAZ;none
+	// +optional
+	NatGatewayStrategy NatGatewayStrategy `json:"natGatewayStrategy,omitempty"`
+
+	// FlowLogs configures VPC flow logs for a managed VPC.
+	// +optional
+	FlowLogs *FlowLogsSpec `json:"flowLogs,omitempty"`
+
+	// DHCPOptions configures the DHCP options set the provider creates and associates with a managed VPC.
+	// When not set, the VPC keeps the options set assigned by AWS. Removing it associates the VPC with
+	// the default options set again and deletes the managed one.
+	// +optional
+	DHCPOptions *DHCPOptions `json:"dhcpOptions,omitempty"`
+
+	// Peering configures a peering connection between a managed VPC and another VPC, e.g. the VPC
+	// of the management cluster, with routes installed in both VPCs. Removing it, or changing the peer VPC,
+	// deletes the peering connection and its routes.
+	// +optional
+	Peering *VPCPeeringSpec `json:"peering,omitempty"`
+
 	// Tags is a collection of tags describing the resource.
 	Tags Tags `json:"tags,omitempty"`
 }
 
+// VPCPeeringSpec configures a peering connection to a VPC in the same account and region.
+type VPCPeeringSpec struct {
+	// PeerVPCID is the id of the VPC to peer with.
+	PeerVPCID string `json:"peerVpcId"`
+
+	// PeerRouteTableIDs are the route tables of the peer VPC that get a route to the managed VPC.
+	// Defaults to all route tables of the peer VPC.
+	// +optional
+	PeerRouteTableIDs []string `json:"peerRouteTableIDs,omitempty"`
+}
+
+// NatGatewayStrategy defines how NAT gateways are provisioned for the private subnets of a managed VPC.
+type NatGatewayStrategy string
+
+var (
+	// NatGatewayStrategyPerAZ creates a NAT gateway in every availability zone with a public subnet.
+	NatGatewayStrategyPerAZ = NatGatewayStrategy("perAZ")
+
+	// NatGatewayStrategySingle creates a single NAT gateway shared by all private subnets.
+	NatGatewayStrategySingle = NatGatewayStrategy("single")
+
+	// NatGatewayStrategyNone does not create NAT gateways, private subnets have no route to the internet.
+	NatGatewayStrategyNone = NatGatewayStrategy("none")
+)
+
+// DHCPOptions configures the DHCP options set associated with a managed VPC.
+type DHCPOptions struct {
+	// DomainName is the domain name instances use to complete unqualified hostnames.
+	// Defaults to the region's default domain name, e.g. ec2.internal in us-east-1.
+	// +optional
+	DomainName *string `json:"domainName,omitempty"`
+
+	// DomainNameServers are the IP addresses of up to four domain name servers.
+	// Defaults to AmazonProvidedDNS.
+	// +kubebuilder:validation:MaxItems=4
+	// +optional
+	DomainNameServers []string `json:"domainNameServers,omitempty"`
+
+	// NTPServers are the IP addresses of up to four NTP servers.
+	// +kubebuilder:validation:MaxItems=4
+	// +optional
+	NTPServers []string `json:"ntpServers,omitempty"`
+}
+
+// PrivateEndpointsSpec configures the VPC endpoints created in a managed VPC.
+type PrivateEndpointsSpec struct {
+	// Services is the list of AWS services to create VPC endpoints for, e.g. s3, ecr.api, ecr.dkr, ec2,
+	// elasticloadbalancing, sts and secretsmanager. A gateway endpoint is created for s3, interface
+	// endpoints are created for every other service.
+	// Defaults to all of the above.
+	// +optional
+	Services []string `json:"services,omitempty"`
+}
+
 // String returns a string representation of the VPC.
 func (v *VPCSpec) String() string {
 	return fmt.Sprintf("id=%s", v.ID)
@@ -208,6 +519,52 @@ func (v *VPCSpec) IsUnmanaged(clusterName string) bool {
 	return v.ID != "" && !v.Tags.HasOwned(clusterName)
 }
 
+// FlowLogsDestinationType is the type of destination VPC flow logs are published to.
+type FlowLogsDestinationType string
+
+var (
+	// FlowLogsDestinationCloudWatchLogs publishes flow logs to a CloudWatch Logs log group.
+	FlowLogsDestinationCloudWatchLogs = FlowLogsDestinationType("cloud-watch-logs")
+
+	// FlowLogsDestinationS3 publishes flow logs to an S3 bucket.
+	FlowLogsDestinationS3 = FlowLogsDestinationType("s3")
+)
+
+// FlowLogsRolePath is the path of the IAM roles flow logs can use to publish to CloudWatch Logs.
+const FlowLogsRolePath = "/cluster-api-provider-aws/flow-logs/"
+
+// FlowLogsSpec configures VPC flow logs.
+type FlowLogsSpec struct {
+	// DestinationType is the type of destination flow logs are published to.
+	// Defaults to cloud-watch-logs.
+	// +kubebuilder:validation:Enum=cloud-watch-logs;s3
+	// +optional
+	DestinationType FlowLogsDestinationType `json:"destinationType,omitempty"`
+
+	// LogGroupName is the name of the CloudWatch Logs log group flow logs are published to.
+	// Required when the destination type is cloud-watch-logs.
+	// +optional
+	LogGroupName string `json:"logGroupName,omitempty"`
+
+	// DeliverLogsPermissionARN is the ARN of the IAM role that allows flow logs to be published
+	// to the CloudWatch Logs log group. Required when the destination type is cloud-watch-logs.
+	// The role must have the path /cluster-api-provider-aws/flow-logs/, the only roles the controllers
+	// policy created by clusterawsadm can pass to flow logs.
+	// +optional
+	DeliverLogsPermissionARN string `json:"deliverLogsPermissionARN,omitempty"`
+
+	// S3BucketARN is the ARN of the S3 bucket, optionally including a folder, flow logs are published to.
+	// Required when the destination type is s3.
+	// +optional
+	S3BucketARN string `json:"s3BucketARN,omitempty"`
+
+	// TrafficType is the type of traffic to log.
+	// Defaults to ALL.
+	// +kubebuilder:validation:Enum=ACCEPT;REJECT;ALL
+	// +optional
+	TrafficType string `json:"trafficType,omitempty"`
+}
+
 // SubnetSpec configures an AWS Subnet.
 type SubnetSpec struct {
 	// ID defines a unique identifier to reference this resource.
@@ -232,10 +589,54 @@ type SubnetSpec struct {
 	// +optional
 	NatGatewayID *string `json:"natGatewayId,omitempty"`
 
+	// NatGatewayEIPAllocationID is the allocation id of a pre-allocated Elastic IP to use for the NAT gateway
+	// created in this public subnet. When not set, the provider allocates and manages an Elastic IP.
+	// +optional
+	NatGatewayEIPAllocationID *string `json:"natGatewayEIPAllocationId,omitempty"`
+
+	// OutpostARN is the Amazon Resource Name of the AWS Outpost the subnet is created on.
+	// +optional
+	OutpostARN *string `json:"outpostArn,omitempty"`
+
+	// LocalGatewayID is the id of an Outpost local gateway. When set, the subnet's default route
+	// targets the local gateway instead of an internet or NAT gateway.
+	// +optional
+	LocalGatewayID *string `json:"localGatewayId,omitempty"`
+
+	// AdditionalRoutes are routes the provider adds to the route table of a managed subnet
+	// alongside the default routes, and keeps reconciled.
+	// +optional
+	AdditionalRoutes []Route `json:"additionalRoutes,omitempty"`
+
 	// Tags is a collection of tags describing the resource.
+	// For managed subnets, these tags are applied in addition to the standard role and load balancer
+	// discovery tags, and take precedence over the additional tags of the cluster.
+	// +optional
 	Tags Tags `json:"tags,omitempty"`
 }
 
+// Route defines a route in a subnet route table. Exactly one target must be set.
+type Route struct {
+	// DestinationCidrBlock is the IPv4 network range the route applies to.
+	DestinationCidrBlock string `json:"destinationCidrBlock"`
+
+	// TransitGatewayID is the id of the Transit Gateway to route traffic through.
+	// +optional
+	TransitGatewayID *string `json:"transitGatewayId,omitempty"`
+
+	// VPCPeeringConnectionID is the id of the VPC peering connection to route traffic through.
+	// +optional
+	VPCPeeringConnectionID *string `json:"vpcPeeringConnectionId,omitempty"`
+
+	// InstanceID is the id of the instance to route traffic through, e.g. a NAT instance or a virtual appliance.
+	// +optional
+	InstanceID *string `json:"instanceId,omitempty"`
+
+	// NatGatewayID is the id of the NAT gateway to route traffic through.
+	// +optional
+	NatGatewayID *string `json:"natGatewayId,omitempty"`
+}
+
 // String returns a string representation of the subnet.
 func (s *SubnetSpec) String() string {
 	return fmt.Sprintf("id=%s/az=%s/public=%v", s.ID, s.AvailabilityZone, s.IsPublic)
@@ -294,6 +695,21 @@ func (s Subnets) FilterByZone(zone string) (res Subnets) {
 	return
 }
 
+// FilterByOutpost returns a slice containing all subnets that live on the Outpost specified.
+// An empty ARN returns the subnets that live in the region rather than on an Outpost.
+func (s Subnets) FilterByOutpost(arn string) (res Subnets) {
+	for _, x := range s {
+		outpost := ""
+		if x.OutpostARN != nil {
+			outpost = *x.OutpostARN
+		}
+		if outpost == arn {
+			res = append(res, x)
+		}
+	}
+	return
+}
+
 // RouteTable defines an AWS routing table.
 type RouteTable struct {
 	ID string `json:"id"`
@@ -317,6 +733,9 @@ var (
 
 	// SecurityGroupLB defines a container for the cloud provider to inject its load balancer ingress rules
 	SecurityGroupLB = SecurityGroupRole("lb")
+
+	// SecurityGroupVPCEndpoint defines the role for the interface VPC endpoints created in a managed VPC
+	SecurityGroupVPCEndpoint = SecurityGroupRole("vpc-endpoint")
 )
 
 // SecurityGroup defines an AWS security group.
@@ -540,20 +959,205 @@ type Instance struct {
 
 	// Configuration options for the root storage volume.
 	// +optional
-	RootVolume *RootVolume `json:"rootVolume,omitempty"`
+	RootVolume *Volume `json:"rootVolume,omitempty"`
+
+	// Configuration options for the non-root storage volumes.
+	// +optional
+	NonRootVolumes []Volume `json:"nonRootVolumes,omitempty"`
+
+	// Instance store volumes mapped to devices of the instance.
+	// +optional
+	EphemeralVolumes []EphemeralVolume `json:"ephemeralVolumes,omitempty"`
 
 	// Specifies ENIs attached to instance
 	NetworkInterfaces []string `json:"networkInterfaces,omitempty"`
 
+	// NetworkInterfaceSpecs are the network interfaces created for the instance when it's launched.
+	// +optional
+	NetworkInterfaceSpecs []NetworkInterfaceSpec `json:"networkInterfaceSpecs,omitempty"`
+
+	// PlacementGroupName is the name of the placement group the instance is launched in.
+	// +optional
+	PlacementGroupName string `json:"placementGroupName,omitempty"`
+
+	// PartitionNumber is the partition of the placement group the instance is launched in.
+	// +optional
+	PartitionNumber *int64 `json:"partitionNumber,omitempty"`
+
+	// HostID is the ID of the Dedicated Host the instance is launched on.
+	// +optional
+	HostID *string `json:"hostID,omitempty"`
+
+	// HostResourceGroupARN is the ARN of the host resource group the instance is launched in.
+	// +optional
+	HostResourceGroupARN *string `json:"hostResourceGroupArn,omitempty"`
+
+	// HostAffinity is the affinity of the instance with the Dedicated Host it's launched on.
+	// +optional
+	HostAffinity *string `json:"hostAffinity,omitempty"`
+
+	// Tenancy is the tenancy of the instance.
+	// +optional
+	Tenancy *string `json:"tenancy,omitempty"`
+
+	// CPUOptions are the CPU options of the instance.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+
+	// UnlimitedCPUCredits is the credit option for CPU usage of a burstable performance instance.
+	// +optional
+	UnlimitedCPUCredits *bool `json:"unlimitedCpuCredits,omitempty"`
+
+	// CapacityReservationID is the ID of the Capacity Reservation the instance is launched in.
+	// +optional
+	CapacityReservationID *string `json:"capacityReservationID,omitempty"`
+
+	// CapacityReservationPreference is the preference of the instance for open Capacity Reservations.
+	// +optional
+	CapacityReservationPreference string `json:"capacityReservationPreference,omitempty"`
+
+	// InstanceMetadataOptions are the instance metadata service options of the instance.
+	// +optional
+	InstanceMetadataOptions *InstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+
 	// The tags associated with the instance.
 	Tags map[string]string `json:"tags,omitempty"`
 }
 
-// RootVolume encapsulates the configuration options for the root volume
-type RootVolume struct {
-	// Size specifies size (in Gi) of the root storage device.
-	// Must be greater than the image root snapshot size or 8 (whichever is greater).
-	// +kubebuilder:validation:Minimum=8
+// CPUOptions defines the CPU options of an instance.
+type CPUOptions struct {
+	// CoreCount is the number of CPU cores of the instance.
+	// +kubebuilder:validation:Minimum=1
+	CoreCount int64 `json:"coreCount"`
+
+	// ThreadsPerCore is the number of threads per CPU core, 1 disables hyperthreading.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2
+	ThreadsPerCore int64 `json:"threadsPerCore"`
+}
+
+// InstanceMetadataOptions defines the instance metadata service options of an instance.
+type InstanceMetadataOptions struct {
+	// HTTPEndpoint enables or disables the instance metadata service endpoint.
+	// +kubebuilder:validation:Enum=enabled;disabled
+	// +optional
+	HTTPEndpoint string `json:"httpEndpoint,omitempty"`
+
+	// HTTPTokens is the session token state of metadata requests, required enforces IMDSv2.
+	// +kubebuilder:validation:Enum=optional;required
+	// +optional
+	HTTPTokens string `json:"httpTokens,omitempty"`
+
+	// HTTPPutResponseHopLimit is the number of network hops the token response may travel,
+	// which needs to be raised for containers without host networking to reach IMDSv2.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	HTTPPutResponseHopLimit int64 `json:"httpPutResponseHopLimit,omitempty"`
+}
+
+// NetworkInterfaceSpec defines a network interface created for an instance when it's launched.
+type NetworkInterfaceSpec struct {
+	// DeviceIndex is the position of the network interface in the attachment order.
+	// +kubebuilder:validation:Minimum=0
+	DeviceIndex int64 `json:"deviceIndex"`
+
+	// InterfaceType is the type of the network interface, efa for an Elastic Fabric Adapter.
+	// Defaults to interface.
+	// +kubebuilder:validation:Enum=interface;efa
+	// +optional
+	InterfaceType string `json:"interfaceType,omitempty"`
+
+	// SubnetID is the subnet the network interface is created in, e.g. a storage network.
+	// It must be in the availability zone of the instance.
+	// Defaults to the subnet of the instance.
+	// +optional
+	SubnetID *string `json:"subnetID,omitempty"`
+
+	// SecurityGroupIDs are the security groups of the network interface.
+	// Defaults to the security groups of the instance.
+	// +optional
+	SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
+
+	// Description is the description of the network interface.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// Placement defines where an instance is launched.
+type Placement struct {
+	// HostID is the ID of the Dedicated Host to launch the instance on.
+	// +optional
+	HostID *string `json:"hostID,omitempty"`
+
+	// HostResourceGroupARN is the ARN of the host resource group to launch the instance in, e.g. to
+	// launch instances with bring your own license software on hosts managed by AWS License Manager.
+	// Cannot be set together with HostID.
+	// +optional
+	HostResourceGroupARN *string `json:"hostResourceGroupArn,omitempty"`
+
+	// Affinity sets whether the instance is restarted on the same Dedicated Host (host) or on any
+	// available Dedicated Host (default) after it's stopped. Requires HostID or HostResourceGroupARN.
+	// +kubebuilder:validation:Enum=default;host
+	// +optional
+	Affinity *string `json:"affinity,omitempty"`
+
+	// Tenancy is the tenancy of the instance: shared hardware (default), single-tenant hardware
+	// (dedicated) or a Dedicated Host (host). Defaults to host when launched on a Dedicated Host,
+	// and to the tenancy of the VPC otherwise.
+	// +kubebuilder:validation:Enum=default;dedicated;host
+	// +optional
+	Tenancy *string `json:"tenancy,omitempty"`
+}
+
+// PlacementGroup defines the placement group an instance is launched in.
+type PlacementGroup struct {
+	// Name is the name of the placement group.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name"`
+
+	// Strategy is the placement strategy of the placement group, used when the placement group is created.
+	// Required if the placement group doesn't exist yet.
+	// +kubebuilder:validation:Enum=cluster;spread;partition
+	// +optional
+	Strategy string `json:"strategy,omitempty"`
+
+	// PartitionCount is the number of partitions of a placement group with the partition strategy,
+	// used when the placement group is created. Defaults to 2.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=7
+	// +optional
+	PartitionCount *int64 `json:"partitionCount,omitempty"`
+
+	// PartitionNumber is the partition to launch the instance in, for placement groups with the partition strategy.
+	// Defaults to a partition picked by AWS.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=7
+	// +optional
+	PartitionNumber *int64 `json:"partitionNumber,omitempty"`
+}
+
+// EphemeralVolume maps an instance store volume to a device.
+type EphemeralVolume struct {
+	// DeviceName is the device name of the instance store volume, e.g. /dev/sdc.
+	DeviceName string `json:"deviceName"`
+
+	// VirtualName is the name of the instance store volume, from ephemeral0 to ephemeral23.
+	// +kubebuilder:validation:Pattern=`^ephemeral([0-9]|1[0-9]|2[0-3])$`
+	VirtualName string `json:"virtualName"`
+}
+
+// Volume encapsulates the configuration options for a storage device.
+type Volume struct {
+	// DeviceName is the device name of the volume, e.g. /dev/sdb. It's required for non-root volumes
+	// and ignored for the root volume, which uses the root device name of the AMI.
+	// +optional
+	DeviceName string `json:"deviceName,omitempty"`
+
+	// Size specifies size (in Gi) of the storage device.
+	// For the root volume it must be greater than the image root snapshot size or 8 (whichever is greater).
+	// +kubebuilder:validation:Minimum=1
 	Size int64 `json:"size"`
 
 	// Type is the type of the root volume (e.g. gp2, io1, etc...).
@@ -568,9 +1172,15 @@ type RootVolume struct {
 	// +optional
 	Encrypted bool `json:"encrypted,omitempty"`
 
-	// EncryptionKey is the KMS key to use to encrypt the volume. Can be either a KMS key ID or ARN.
+	// EncryptionKey is the KMS key to use to encrypt the volume. Can be a KMS key ID, alias, key ARN
+	// or alias ARN, e.g. a customer managed key per volume. Setting it implies Encrypted.
 	// If Encrypted is set and this is omitted, the default AWS key will be used.
 	// The key must already exist and be accessible by the controller.
 	// +optional
 	EncryptionKey string `json:"encryptionKey,omitempty"`
 }
+
+// RootVolume encapsulates the configuration options for the root storage device.
+// It's kept as an alias of Volume for existing API consumers.
+// +kubebuilder:object:generate=false
+type RootVolume = Volume
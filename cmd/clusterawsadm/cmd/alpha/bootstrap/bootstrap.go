@@ -29,6 +29,7 @@ import (
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	awssts "github.com/aws/aws-sdk-go/service/sts"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/endpoints"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/cloudformation"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/sts"
 )
@@ -47,6 +48,7 @@ aws_session_token = {{ .SessionToken }}
 var (
 	extraControlPlanePolicies []string
 	extraNodePolicies         []string
+	permissionsBoundary       string
 )
 
 // RootCmd is the root of the `alpha bootstrap command`
@@ -68,13 +70,19 @@ func RootCmd() *cobra.Command {
 	newCmd.AddCommand(encodeAWSSecret())
 	newCmd.AddCommand(generateAWSDefaultProfileWithChain())
 
-	newCmd.PersistentFlags().String("partition", "aws", "AWS partition, for AWS GovCloud (US) it is aws-us-gov")
+	newCmd.PersistentFlags().String("partition", endpoints.DefaultPartition, "AWS partition, for AWS GovCloud (US) it is aws-us-gov and for the AWS China regions it is aws-cn. Defaults to the partition of the configured region when one is available")
 
 	return newCmd
 }
 
-func getPartitionFlag(cmd *cobra.Command) string {
-	return cmd.Flags().Lookup("partition").Value.String()
+// getPartition returns the partition set with the partition flag, or the partition of the region
+// if the flag isn't set.
+func getPartition(cmd *cobra.Command, region string) string {
+	flag := cmd.Flags().Lookup("partition")
+	if flag.Changed || region == "" {
+		return flag.Value.String()
+	}
+	return endpoints.PartitionForRegion(region)
 }
 
 func generateCmd() *cobra.Command {
@@ -104,8 +112,8 @@ Instructions for obtaining the AWS account ID can be found on https://docs.aws.a
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			partition := getPartitionFlag(cmd)
-			template := cloudformation.BootstrapTemplate(args[0], partition, extraControlPlanePolicies, extraNodePolicies)
+			partition := getPartition(cmd, "")
+			template := cloudformation.BootstrapTemplate(args[0], partition, permissionsBoundary, extraControlPlanePolicies, extraNodePolicies)
 			j, err := template.YAML()
 			if err != nil {
 				return err
@@ -118,6 +126,7 @@ Instructions for obtaining the AWS account ID can be found on https://docs.aws.a
 
 	newCmd.Flags().StringSliceVar(&extraControlPlanePolicies, "extra-controlplane-policies", []string{}, "Comma-separated list of extra policies (ARNs) to add to the created control plane role (must already exist)")
 	newCmd.Flags().StringSliceVar(&extraNodePolicies, "extra-node-policies", []string{}, "Comma-separated list of extra policies (ARNs) to add to the created nodes role (must already exist)")
+	newCmd.Flags().StringVar(&permissionsBoundary, "permissions-boundary", "", "ARN of the managed policy used as permissions boundary of the created users and roles (must already exist)")
 
 	return newCmd
 }
@@ -147,8 +156,8 @@ func createStackCmd() *cobra.Command {
 			}
 
 			cfnSvc := cloudformation.NewService(cfn.New(sess))
-			partition := getPartitionFlag(cmd)
-			err = cfnSvc.ReconcileBootstrapStack(stackName, accountID, partition, extraControlPlanePolicies, extraNodePolicies)
+			partition := getPartition(cmd, aws.StringValue(sess.Config.Region))
+			err = cfnSvc.ReconcileBootstrapStack(stackName, accountID, partition, permissionsBoundary, extraControlPlanePolicies, extraNodePolicies)
 			if err != nil {
 				fmt.Printf("Error: %v", err)
 				return err
@@ -160,6 +169,7 @@ func createStackCmd() *cobra.Command {
 
 	newCmd.Flags().StringSliceVar(&extraControlPlanePolicies, "extra-controlplane-policies", []string{}, "Comma-separated list of extra policies (ARNs) to add to the created control plane role (must already exist)")
 	newCmd.Flags().StringSliceVar(&extraNodePolicies, "extra-node-policies", []string{}, "Comma-separated list of extra policies (ARNs) to add to the created nodes role (must already exist)")
+	newCmd.Flags().StringVar(&permissionsBoundary, "permissions-boundary", "", "ARN of the managed policy used as permissions boundary of the created users and roles (must already exist)")
 
 	return newCmd
 }
@@ -219,7 +229,7 @@ func generateIAMPolicyDocJSON() *cobra.Command {
 			}
 
 			cfnSvc := cloudformation.NewService(cfn.New(sess))
-			partition := getPartitionFlag(cmd)
+			partition := getPartition(cmd, aws.StringValue(sess.Config.Region))
 			err = cfnSvc.GenerateManagedIAMPolicyDocuments(policyDocDir, accountID, partition)
 
 			if err != nil {
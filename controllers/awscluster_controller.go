@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -26,9 +27,13 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/endpoints"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/route53"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/s3"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -47,11 +52,15 @@ type AWSClusterReconciler struct {
 	client.Client
 	Recorder record.EventRecorder
 	Log      logr.Logger
+
+	// Endpoints configures how the endpoints of the AWS services are resolved.
+	Endpoints endpoints.Options
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create
 
 func (r *AWSClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
 	ctx := context.TODO()
@@ -91,6 +100,7 @@ func (r *AWSClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reter
 		Logger:     log,
 		Cluster:    cluster,
 		AWSCluster: awsCluster,
+		Endpoints:  r.Endpoints,
 	})
 	if err != nil {
 		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
@@ -118,8 +128,13 @@ func reconcileDelete(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 
 	ec2svc := ec2.NewService(clusterScope)
 	elbsvc := elb.NewService(clusterScope)
+	route53svc := route53.NewService(clusterScope)
 	awsCluster := clusterScope.AWSCluster
 
+	if err := route53svc.DeleteControlPlaneEndpointDNS(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "error deleting control plane endpoint DNS record for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
 	if err := elbsvc.DeleteLoadbalancers(); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "error deleting load balancer for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
@@ -128,6 +143,22 @@ func reconcileDelete(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 		return reconcile.Result{}, errors.Wrapf(err, "error deleting bastion for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
 
+	if err := ec2svc.DeleteSSHKeyPair(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "error deleting SSH key pair for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
+	if err := ec2svc.DeletePlacementGroups(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "error deleting placement groups for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
+	if err := s3.NewService(clusterScope).DeleteBucket(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "error deleting S3 bucket for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
+	if err := iam.NewService(clusterScope).DeleteInstanceProfiles(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "error deleting IAM instance profiles for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
 	if err := ec2svc.DeleteNetwork(); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "error deleting network for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
@@ -158,10 +189,26 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile network for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
 
+	if err := iam.NewService(clusterScope).ReconcileInstanceProfiles(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile IAM instance profiles for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
+	if err := s3.NewService(clusterScope).ReconcileBucket(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile S3 bucket for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
+	if err := ec2Service.ReconcileSSHKeyPair(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile SSH key pair for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
 	if err := ec2Service.ReconcileBastion(); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile bastion host for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
 
+	if err := ec2Service.ReconcileSessionManagerTargets(); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile Session Manager targets for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+	}
+
 	if err := elbService.ReconcileLoadbalancers(); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile load balancers for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
@@ -176,8 +223,22 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 		return reconcile.Result{RequeueAfter: 15 * time.Second}, nil
 	}
 
+	host := awsCluster.Status.Network.APIServerELB.DNSName
+	if dns := awsCluster.Spec.ControlPlaneEndpointDNS; dns != nil {
+		if awsCluster.Status.Network.APIServerELB.CanonicalHostedZoneID == "" {
+			clusterScope.Info("Waiting on API server ELB hosted zone")
+			return reconcile.Result{RequeueAfter: 15 * time.Second}, nil
+		}
+
+		if err := route53.NewService(clusterScope).ReconcileControlPlaneEndpointDNS(); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile control plane endpoint DNS record for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
+		}
+
+		host = strings.TrimSuffix(dns.RecordName, ".")
+	}
+
 	awsCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
-		Host: awsCluster.Status.Network.APIServerELB.DNSName,
+		Host: host,
 		Port: clusterScope.APIServerPort(),
 	}
 
@@ -196,7 +257,17 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 	}
 
 	awsCluster.Status.Ready = true
-	return reconcile.Result{}, nil
+
+	return readyResult(clusterScope), nil
+}
+
+// readyResult returns the result of reconciling a ready AWSCluster. It requeues periodically
+// when security group drift remediation is enabled, so that rules changed out-of-band are reverted.
+func readyResult(clusterScope *scope.ClusterScope) reconcile.Result {
+	if interval := clusterScope.SecurityGroupDriftRemediationInterval(); interval > 0 {
+		return reconcile.Result{RequeueAfter: interval}
+	}
+	return reconcile.Result{}
 }
 
 func (r *AWSClusterReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestAWSClusterReconciler_ReadyResult(t *testing.T) {
+	tests := []struct {
+		name                 string
+		driftRemediation     *infrav1.SecurityGroupDriftRemediationSpec
+		expectedRequeueAfter time.Duration
+	}{
+		{
+			name:                 "doesn't requeue without drift remediation",
+			expectedRequeueAfter: 0,
+		},
+		{
+			name: "requeues after the drift remediation interval",
+			driftRemediation: &infrav1.SecurityGroupDriftRemediationSpec{
+				Interval: &metav1.Duration{Duration: time.Minute},
+			},
+			expectedRequeueAfter: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							SecurityGroupDriftRemediation: tt.driftRemediation,
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			if result := readyResult(clusterScope); result.RequeueAfter != tt.expectedRequeueAfter {
+				t.Errorf("expected RequeueAfter %v, got %v", tt.expectedRequeueAfter, result.RequeueAfter)
+			}
+		})
+	}
+}
@@ -40,10 +40,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/endpoints"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/s3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/secretsmanager"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
 )
@@ -55,6 +57,13 @@ type AWSMachineReconciler struct {
 	Recorder                     record.EventRecorder
 	ec2ServiceFactory            func(*scope.ClusterScope) services.EC2MachineInterface
 	secretsManagerServiceFactory func(*scope.ClusterScope) services.SecretsManagerInterface
+	objectStoreServiceFactory    func(*scope.ClusterScope) services.ObjectStoreInterface
+
+	// IMDSv2RequiredByDefault makes new machines without instance metadata options require session tokens.
+	IMDSv2RequiredByDefault bool
+
+	// Endpoints configures how the endpoints of the AWS services are resolved.
+	Endpoints endpoints.Options
 }
 
 func (r *AWSMachineReconciler) getEC2Service(scope *scope.ClusterScope) services.EC2MachineInterface {
@@ -73,6 +82,14 @@ func (r *AWSMachineReconciler) getSecretsManagerService(scope *scope.ClusterScop
 	return secretsmanager.NewService(scope)
 }
 
+func (r *AWSMachineReconciler) getObjectStoreService(scope *scope.ClusterScope) services.ObjectStoreInterface {
+	if r.objectStoreServiceFactory != nil {
+		return r.objectStoreServiceFactory(scope)
+	}
+
+	return s3.NewService(scope)
+}
+
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
@@ -138,6 +155,7 @@ func (r *AWSMachineReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reter
 		Logger:     logger,
 		Cluster:    cluster,
 		AWSCluster: awsCluster,
+		Endpoints:  r.Endpoints,
 	})
 	if err != nil {
 		return ctrl.Result{}, err
@@ -151,6 +169,8 @@ func (r *AWSMachineReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reter
 		Machine:    machine,
 		AWSCluster: awsCluster,
 		AWSMachine: awsMachine,
+
+		IMDSv2RequiredByDefault: r.IMDSv2RequiredByDefault,
 	})
 	if err != nil {
 		return ctrl.Result{}, errors.Errorf("failed to create scope: %+v", err)
@@ -250,6 +270,17 @@ func (r *AWSMachineReconciler) SetupWithManager(mgr ctrl.Manager, options contro
 	)
 }
 
+// releaseElasticIP releases the Elastic IP associated with the machine, if any.
+func (r *AWSMachineReconciler) releaseElasticIP(machineScope *scope.MachineScope, ec2Service services.EC2MachineInterface) error {
+	if !machineScope.AWSMachine.Spec.AssociateElasticIP {
+		return nil
+	}
+	if err := ec2Service.ReleaseElasticIP(machineScope); err != nil {
+		return errors.Wrap(err, "failed to release Elastic IP")
+	}
+	return nil
+}
+
 func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (ctrl.Result, error) {
 	machineScope.Info("Handling deleted AWSMachine")
 
@@ -260,6 +291,13 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		return ctrl.Result{}, err
 	}
 
+	if machineScope.UseIgnition() {
+		if err := r.getObjectStoreService(clusterScope).Delete(machineScope); err != nil {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedDeleteBootstrapDataObject", "Failed to delete bootstrap data from S3: %v", err)
+			return ctrl.Result{}, err
+		}
+	}
+
 	instance, err := r.findInstance(machineScope, ec2Service)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -275,12 +313,19 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		// 4. Scale controller deployment to 1
 		machineScope.V(2).Info("Unable to locate EC2 instance by ID or tags")
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "NoInstanceFound", "Unable to find matching EC2 instance")
+		if err := r.releaseElasticIP(machineScope, ec2Service); err != nil {
+			return ctrl.Result{}, err
+		}
 		controllerutil.RemoveFinalizer(machineScope.AWSMachine, infrav1.MachineFinalizer)
 		return ctrl.Result{}, nil
 	}
 
 	machineScope.V(3).Info("EC2 instance found matching deleted AWSMachine", "instance-id", instance.ID)
 
+	if err := r.reconcileLBDetachment(machineScope, clusterScope, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Check the instance state. If it's already shutting down or terminated,
 	// do nothing. Otherwise attempt to delete it.
 	// This decision is based on the ec2-instance-lifecycle graph at
@@ -319,6 +364,12 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulTerminate", "Terminated instance %q", instance.ID)
 	}
 
+	// The Elastic IP is released regardless of the instance state so that a
+	// failed release is retried once the instance has already terminated.
+	if err := r.releaseElasticIP(machineScope, ec2Service); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Instance is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(machineScope.AWSMachine, infrav1.MachineFinalizer)
 
@@ -389,7 +440,7 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 	ec2svc := r.getEC2Service(clusterScope)
 
 	// Get or create the instance.
-	instance, err := r.getOrCreate(machineScope, ec2svc, secretSvc)
+	instance, err := r.getOrCreate(machineScope, ec2svc, secretSvc, r.getObjectStoreService(clusterScope))
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -447,7 +498,7 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 
 	// tasks that can take place during all known instance states
 	if machineScope.InstanceIsInKnownState() {
-		_, err = r.ensureTags(ec2svc, machineScope.AWSMachine, machineScope.GetInstanceID(), machineScope.AdditionalTags())
+		_, err = r.ensureTags(ec2svc, machineScope.AWSMachine, instance, machineScope.AdditionalTags())
 		if err != nil {
 			return ctrl.Result{}, errors.Errorf("failed to ensure tags: %+v", err)
 		}
@@ -466,13 +517,25 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 			return ctrl.Result{}, err
 		}
 
+		additionalSecurityGroups, err := ec2svc.GetAdditionalSecurityGroupsIDs(machineScope)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
 		// Ensure that the security groups are correct.
-		_, err = r.ensureSecurityGroups(ec2svc, machineScope, machineScope.AWSMachine.Spec.AdditionalSecurityGroups, existingSecurityGroups)
+		_, err = r.ensureSecurityGroups(ec2svc, machineScope, additionalSecurityGroups, existingSecurityGroups)
 		if err != nil {
 			return ctrl.Result{}, errors.Errorf("failed to apply security groups: %+v", err)
 		}
 	}
 
+	// Elastic IPs can only be associated with running instances.
+	if machineScope.AWSMachine.Spec.AssociateElasticIP && instance.State == infrav1.InstanceStateRunning {
+		if err := ec2svc.ReconcileElasticIP(machineScope, instance); err != nil {
+			return ctrl.Result{}, errors.Errorf("failed to reconcile Elastic IP: %+v", err)
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -503,7 +566,7 @@ func (r *AWSMachineReconciler) deleteEncryptedBootstrapDataSecret(machineScope *
 	return nil
 }
 
-func (r *AWSMachineReconciler) getOrCreate(scope *scope.MachineScope, ec2svc services.EC2MachineInterface, secretSvc services.SecretsManagerInterface) (*infrav1.Instance, error) {
+func (r *AWSMachineReconciler) getOrCreate(scope *scope.MachineScope, ec2svc services.EC2MachineInterface, secretSvc services.SecretsManagerInterface, objectStoreSvc services.ObjectStoreInterface) (*infrav1.Instance, error) {
 	instance, err := r.findInstance(scope, ec2svc)
 	if err != nil {
 		return nil, err
@@ -522,7 +585,34 @@ func (r *AWSMachineReconciler) getOrCreate(scope *scope.MachineScope, ec2svc ser
 		return nil, err
 	}
 
-	if scope.UseSecretsManager() {
+	userData, err = userdata.MergeParts(userData, scope.AWSMachine.Spec.CloudInit.UserDataParts)
+	if err != nil {
+		r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeWarning, "FailedMergeUserDataParts", err.Error())
+		return nil, err
+	}
+
+	if scope.AWSMachine.Spec.Bottlerocket != nil {
+		userData, err = r.bottlerocketUserData(scope, userData)
+		if err != nil {
+			r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeWarning, "FailedGenerateBottlerocketUserData", err.Error())
+			return nil, err
+		}
+	}
+
+	if scope.UseIgnition() {
+		objectURL, err := objectStoreSvc.Create(scope, userData)
+		if err != nil {
+			r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeWarning, "FailedCreateBootstrapDataObject", err.Error())
+			return nil, err
+		}
+		userData, err = userdata.NewIgnition(&userdata.IgnitionInput{
+			Version: scope.IgnitionVersion(),
+			Source:  objectURL,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if scope.UseSecretsManager() {
 		compressedUserData, err := userdata.GzipBytes(userData)
 		if err != nil {
 			return nil, err
@@ -558,6 +648,35 @@ func (r *AWSMachineReconciler) getOrCreate(scope *scope.MachineScope, ec2svc ser
 	return instance, nil
 }
 
+// bottlerocketUserData renders the kubeadm bootstrap data of a worker machine into Bottlerocket settings.
+func (r *AWSMachineReconciler) bottlerocketUserData(scope *scope.MachineScope, bootstrapData []byte) ([]byte, error) {
+	if scope.IsControlPlane() {
+		return nil, errors.New("bottlerocket is only supported on worker machines")
+	}
+
+	input, err := userdata.NewBottlerocketInputFromKubeadm(bootstrapData)
+	if err != nil {
+		return nil, err
+	}
+
+	caCert, err := scope.GetClusterCACertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	input.ClusterName = scope.Cluster.Name
+	input.ClusterCACert = string(caCert)
+	input.AdminContainer = scope.AWSMachine.Spec.Bottlerocket.AdminContainer
+	input.ControlContainer = scope.AWSMachine.Spec.Bottlerocket.ControlContainer
+
+	userData, err := userdata.NewBottlerocket(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(userData), nil
+}
+
 func (r *AWSMachineReconciler) reconcileLBAttachment(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope, i *infrav1.Instance) error {
 	if !machineScope.IsControlPlane() {
 		return nil
@@ -572,6 +691,20 @@ func (r *AWSMachineReconciler) reconcileLBAttachment(machineScope *scope.Machine
 	return nil
 }
 
+func (r *AWSMachineReconciler) reconcileLBDetachment(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope, i *infrav1.Instance) error {
+	if !machineScope.IsControlPlane() {
+		return nil
+	}
+
+	elbsvc := elb.NewService(clusterScope)
+	if err := elbsvc.DeregisterInstanceFromAPIServerELB(i); err != nil {
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedDetachControlPlaneELB",
+			"Failed to deregister control plane instance %q from load balancer: %v", i.ID, err)
+		return errors.Wrapf(err, "could not deregister control plane instance %q from load balancer", i.ID)
+	}
+	return nil
+}
+
 // AWSClusterToAWSMachines is a handler.ToRequestsFunc to be used to enqeue requests for reconciliation
 // of AWSMachines.
 func (r *AWSMachineReconciler) AWSClusterToAWSMachines(o handler.MapObject) []ctrl.Request {
@@ -46,13 +46,14 @@ import (
 
 var _ = Describe("AWSMachineReconciler", func() {
 	var (
-		reconciler AWSMachineReconciler
-		cs         *scope.ClusterScope
-		ms         *scope.MachineScope
-		mockCtrl   *gomock.Controller
-		ec2Svc     *mock_services.MockEC2MachineInterface
-		secretSvc  *mock_services.MockSecretsManagerInterface
-		recorder   *record.FakeRecorder
+		reconciler     AWSMachineReconciler
+		cs             *scope.ClusterScope
+		ms             *scope.MachineScope
+		mockCtrl       *gomock.Controller
+		ec2Svc         *mock_services.MockEC2MachineInterface
+		secretSvc      *mock_services.MockSecretsManagerInterface
+		objectStoreSvc *mock_services.MockObjectStoreInterface
+		recorder       *record.FakeRecorder
 	)
 
 	BeforeEach(func() {
@@ -116,6 +117,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		ec2Svc = mock_services.NewMockEC2MachineInterface(mockCtrl)
 		secretSvc = mock_services.NewMockSecretsManagerInterface(mockCtrl)
+		objectStoreSvc = mock_services.NewMockObjectStoreInterface(mockCtrl)
 
 		// If your test hangs for 9 minutes, increase the value here to the number of events during a reconciliation loop
 		recorder = record.NewFakeRecorder(2)
@@ -127,6 +129,9 @@ var _ = Describe("AWSMachineReconciler", func() {
 			secretsManagerServiceFactory: func(*scope.ClusterScope) services.SecretsManagerInterface {
 				return secretSvc
 			},
+			objectStoreServiceFactory: func(*scope.ClusterScope) services.ObjectStoreInterface {
+				return objectStoreSvc
+			},
 			Recorder: recorder,
 		}
 
@@ -234,6 +239,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 			Context("instance security group errors", func() {
 				BeforeEach(func() {
 					ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).Return(nil, errors.New("stop here"))
+					ec2Svc.EXPECT().UpdateInstanceResourceTags(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 				})
 
 				It("should set attributes after creating an instance", func() {
@@ -287,6 +293,13 @@ var _ = Describe("AWSMachineReconciler", func() {
 					ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).
 						Return(map[string][]string{"eid": {}}, nil)
 					ec2Svc.EXPECT().GetCoreSecurityGroups(gomock.Any()).Return([]string{}, nil)
+					ec2Svc.EXPECT().GetAdditionalSecurityGroupsIDs(gomock.Any()).DoAndReturn(func(scope *scope.MachineScope) ([]string, error) {
+						var ids []string
+						for _, sg := range scope.AWSMachine.Spec.AdditionalSecurityGroups {
+							ids = append(ids, *sg.ID)
+						}
+						return ids, nil
+					})
 				})
 
 				It("should reconcile security groups", func() {
@@ -297,12 +310,14 @@ var _ = Describe("AWSMachineReconciler", func() {
 					}
 					// ms.AWSMachine.Spec.AdditionalSecurityGroups = []infrav1
 					ec2Svc.EXPECT().UpdateInstanceSecurityGroups(instance.ID, []string{"sg-2345"})
+					ec2Svc.EXPECT().UpdateInstanceResourceTags(instance, map[string]string{}, map[string]string{}).Return(nil)
 
 					_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
 				})
 
 				It("should not tag anything if there's not tags", func() {
 					ec2Svc.EXPECT().UpdateInstanceSecurityGroups(gomock.Any(), gomock.Any()).Times(0)
+					ec2Svc.EXPECT().UpdateInstanceResourceTags(instance, map[string]string{}, map[string]string{}).Return(nil)
 					if _, err := reconciler.reconcileNormal(context.Background(), ms, cs); err != nil {
 						_ = fmt.Errorf("reconcileNormal reutrned an error during test")
 					}
@@ -313,8 +328,8 @@ var _ = Describe("AWSMachineReconciler", func() {
 					ms.AWSMachine.Spec.AdditionalTags = infrav1.Tags{"kind": "alicorn"}
 					ms.AWSCluster.Spec.AdditionalTags = infrav1.Tags{"colour": "lavender"}
 
-					ec2Svc.EXPECT().UpdateResourceTags(
-						PointsTo("myMachine"),
+					ec2Svc.EXPECT().UpdateInstanceResourceTags(
+						instance,
 						map[string]string{
 							"kind":   "alicorn",
 							"colour": "lavender",
@@ -335,6 +350,8 @@ var _ = Describe("AWSMachineReconciler", func() {
 					ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).
 						Return(map[string][]string{"eid": {}}, nil).Times(1)
 					ec2Svc.EXPECT().GetCoreSecurityGroups(gomock.Any()).Return([]string{}, nil).Times(1)
+					ec2Svc.EXPECT().GetAdditionalSecurityGroupsIDs(gomock.Any()).Return(nil, nil).Times(1)
+					ec2Svc.EXPECT().UpdateInstanceResourceTags(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 				})
 
 				It("should set instance to stopping and unready", func() {
@@ -368,6 +385,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 					buf = new(bytes.Buffer)
 					klog.SetOutput(buf)
 					secretSvc.EXPECT().Delete(gomock.Any()).Return(nil).Times(1)
+					ec2Svc.EXPECT().UpdateInstanceResourceTags(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 				})
 
 				It("should warn if an instance is shutting-down", func() {
@@ -393,6 +411,9 @@ var _ = Describe("AWSMachineReconciler", func() {
 	Context("secrets management lifecycle", func() {
 		var instance *infrav1.Instance
 		secretPrefix := "test/secret"
+		BeforeEach(func() {
+			ec2Svc.EXPECT().UpdateInstanceResourceTags(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		})
 		When("creating EC2 instances", func() {
 			BeforeEach(func() {
 				ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(nil, nil).AnyTimes()
@@ -434,6 +455,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 					Return(map[string][]string{"eid": {}}, nil).Times(1)
 				secretSvc.EXPECT().Delete(gomock.Any()).Return(nil).Times(1)
 				ec2Svc.EXPECT().GetCoreSecurityGroups(gomock.Any()).Return([]string{}, nil).Times(1)
+				ec2Svc.EXPECT().GetAdditionalSecurityGroupsIDs(gomock.Any()).Return(nil, nil).Times(1)
 				_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
 			})
 
@@ -476,6 +498,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 				ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).
 					Return(map[string][]string{"eid": {}}, nil).Times(1)
 				ec2Svc.EXPECT().GetCoreSecurityGroups(gomock.Any()).Return([]string{}, nil).Times(1)
+				ec2Svc.EXPECT().GetAdditionalSecurityGroupsIDs(gomock.Any()).Return(nil, nil).Times(1)
 				secretSvc.EXPECT().Delete(gomock.Any()).Return(nil).MaxTimes(0)
 				_, _ = reconciler.reconcileNormal(context.Background(), ms, cs)
 			})
@@ -519,6 +542,7 @@ var _ = Describe("AWSMachineReconciler", func() {
 				ec2Svc.EXPECT().CreateInstance(gomock.Any(), gomock.Any()).Return(instance, nil).AnyTimes()
 				ec2Svc.EXPECT().GetInstanceSecurityGroups(gomock.Any()).Return(map[string][]string{"eid": {}}, nil).Times(1)
 				ec2Svc.EXPECT().GetCoreSecurityGroups(gomock.Any()).Return([]string{}, nil).Times(1)
+				ec2Svc.EXPECT().GetAdditionalSecurityGroupsIDs(gomock.Any()).Return(nil, nil).Times(1)
 				_, err = reconciler.reconcileNormal(context.Background(), ms, cs)
 				Expect(err).To(BeNil())
 				Expect(ms.GetSecretPrefix()).To(Equal(secretPrefix))
@@ -586,6 +610,41 @@ var _ = Describe("AWSMachineReconciler", func() {
 			Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
 		})
 
+		It("should release the Elastic IP when no machine exists", func() {
+			ms.AWSMachine.Spec.AssociateElasticIP = true
+			ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(nil, nil)
+			ec2Svc.EXPECT().ReleaseElasticIP(gomock.Any()).Return(nil)
+
+			_, err := reconciler.reconcileDelete(ms, cs)
+			Expect(err).To(BeNil())
+			Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+		})
+
+		It("should release the Elastic IP of instances in terminated state", func() {
+			ms.AWSMachine.Spec.AssociateElasticIP = true
+			ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(&infrav1.Instance{
+				State: infrav1.InstanceStateTerminated,
+			}, nil)
+			ec2Svc.EXPECT().ReleaseElasticIP(gomock.Any()).Return(nil)
+
+			_, err := reconciler.reconcileDelete(ms, cs)
+			Expect(err).To(BeNil())
+			Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+		})
+
+		It("should keep the finalizer when the Elastic IP can't be released", func() {
+			expected := errors.New("can't reach AWS to release Elastic IP")
+			ms.AWSMachine.Spec.AssociateElasticIP = true
+			ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(&infrav1.Instance{
+				State: infrav1.InstanceStateTerminated,
+			}, nil)
+			ec2Svc.EXPECT().ReleaseElasticIP(gomock.Any()).Return(expected)
+
+			_, err := reconciler.reconcileDelete(ms, cs)
+			Expect(errors.Cause(err)).To(MatchError(expected))
+			Expect(ms.AWSMachine.Finalizers).To(ContainElement(infrav1.MachineFinalizer))
+		})
+
 		Context("Instance not shutting down yet", func() {
 			id := "aws:////myid"
 
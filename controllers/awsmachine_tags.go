@@ -35,23 +35,25 @@ const (
 // Returns bool, error
 // Bool indicates if changes were made or not, allowing the caller to decide
 // if the machine should be updated.
-func (r *AWSMachineReconciler) ensureTags(svc service.EC2MachineInterface, machine *infrav1.AWSMachine, instanceID *string, additionalTags map[string]string) (bool, error) {
+func (r *AWSMachineReconciler) ensureTags(svc service.EC2MachineInterface, machine *infrav1.AWSMachine, instance *infrav1.Instance, additionalTags map[string]string) (bool, error) {
 	annotation, err := r.machineAnnotationJSON(machine, TagsLastAppliedAnnotation)
 	if err != nil {
 		return false, err
 	}
 
-	// Check if the instance tags were changed. If they were, update them.
-	// It would be possible here to only send new/updated tags, but for the
-	// moment we send everything, even if only a single tag was created or
-	// upated.
-	changed, created, deleted, newAnnotation := r.tagsChanged(annotation, additionalTags)
-	if changed {
-		err = svc.UpdateResourceTags(instanceID, created, deleted)
-		if err != nil {
-			return false, err
-		}
+	// Check if the tags were changed since they were last applied, the annotation
+	// is the only record of the tags which need to be deleted.
+	changed, _, deleted, newAnnotation := r.tagsChanged(annotation, additionalTags)
 
+	// The instance, its volumes and network interfaces are compared with the
+	// desired tags on every reconcile, so that resources attached after launch
+	// or tags changed outside of the controller are brought back in line.
+	err = svc.UpdateInstanceResourceTags(instance, additionalTags, deleted)
+	if err != nil {
+		return false, err
+	}
+
+	if changed {
 		// We also need to update the annotation if anything changed.
 		err = r.updateMachineAnnotationJSON(machine, TagsLastAppliedAnnotation, newAnnotation)
 		if err != nil {
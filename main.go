@@ -32,6 +32,7 @@ import (
 	infrav1alpha2 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha2"
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/controllers"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/endpoints"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -69,6 +70,9 @@ func main() {
 		syncPeriod              time.Duration
 		webhookPort             int
 		healthAddr              string
+		imdsv2RequiredByDefault bool
+		awsEndpoints            endpoints.Options
+		awsServiceEndpoints     string
 	)
 
 	flag.StringVar(
@@ -136,10 +140,41 @@ func main() {
 		"The address the health endpoint binds to.",
 	)
 
+	flag.BoolVar(&imdsv2RequiredByDefault,
+		"feature-imdsv2-required",
+		false,
+		"Feature gate: launch new AWSMachines without instance metadata options with session tokens (IMDSv2) required.",
+	)
+
+	flag.BoolVar(&awsEndpoints.UseFIPS,
+		"aws-fips-endpoints",
+		false,
+		"Use the FIPS 140-2 validated endpoints of AWS services. Services without a FIPS endpoint known to the SDK must be set with --aws-endpoints.",
+	)
+
+	flag.BoolVar(&awsEndpoints.UseDualStack,
+		"aws-dualstack-endpoints",
+		false,
+		"Use the dual-stack (IPv4 and IPv6) endpoints of AWS services that support them.",
+	)
+
+	flag.StringVar(&awsServiceEndpoints,
+		"aws-endpoints",
+		"",
+		"Comma-separated list of AWS service endpoint overrides (e.g. ec2=https://localstack:4566,elasticloadbalancing=https://localstack:4566).",
+	)
+
 	flag.Parse()
 
 	ctrl.SetLogger(klogr.New())
 
+	serviceEndpoints, err := endpoints.ParseServiceEndpoints(awsServiceEndpoints)
+	if err != nil {
+		setupLog.Error(err, "unable to parse AWS service endpoints")
+		os.Exit(1)
+	}
+	awsEndpoints.ServiceEndpoints = serviceEndpoints
+
 	if watchNamespace != "" {
 		setupLog.Info("Watching cluster-api objects only in namespace for reconciliation", "namespace", watchNamespace)
 	}
@@ -182,6 +217,9 @@ func main() {
 			Client:   mgr.GetClient(),
 			Log:      ctrl.Log.WithName("controllers").WithName("AWSMachine"),
 			Recorder: mgr.GetEventRecorderFor("awsmachine-controller"),
+
+			IMDSv2RequiredByDefault: imdsv2RequiredByDefault,
+			Endpoints:               awsEndpoints,
 		}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: awsMachineConcurrency}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AWSMachine")
 			os.Exit(1)
@@ -190,6 +228,8 @@ func main() {
 			Client:   mgr.GetClient(),
 			Log:      ctrl.Log.WithName("controllers").WithName("AWSCluster"),
 			Recorder: mgr.GetEventRecorderFor("awscluster-controller"),
+
+			Endpoints: awsEndpoints,
 		}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: awsClusterConcurrency}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AWSCluster")
 			os.Exit(1)
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package endpoints resolves the AWS partition specific parts of ARNs, service principals and
+// endpoints, so that clusters can be created outside of the standard aws partition, e.g. in
+// AWS GovCloud (US) or the AWS China regions.
+package endpoints
+
+import (
+	awsendpoints "github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+const (
+	// DefaultPartition is the standard AWS partition.
+	DefaultPartition = awsendpoints.AwsPartitionID
+
+	// defaultDNSSuffix is the DNS suffix of the standard AWS partition.
+	defaultDNSSuffix = "amazonaws.com"
+)
+
+// PartitionForRegion returns the id of the partition the region belongs to, e.g. aws-us-gov for
+// us-gov-west-1 or aws-cn for cn-north-1. Unknown regions default to the aws partition.
+func PartitionForRegion(region string) string {
+	p, ok := awsendpoints.PartitionForRegion(awsendpoints.DefaultPartitions(), region)
+	if !ok {
+		return DefaultPartition
+	}
+	return p.ID()
+}
+
+// DNSSuffix returns the DNS suffix of the partition, e.g. amazonaws.com.cn for aws-cn.
+// Unknown partitions default to amazonaws.com.
+func DNSSuffix(partition string) string {
+	for _, p := range awsendpoints.DefaultPartitions() {
+		if p.ID() == partition {
+			return p.DNSSuffix()
+		}
+	}
+	return defaultDNSSuffix
+}
+
+// ServicePrincipal returns the IAM service principal of an AWS service in the partition,
+// e.g. ec2.amazonaws.com.cn for the ec2 service in aws-cn.
+func ServicePrincipal(service, partition string) string {
+	return service + "." + DNSSuffix(partition)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"testing"
+)
+
+func TestPartitionForRegion(t *testing.T) {
+	testCases := []struct {
+		region    string
+		partition string
+		principal string
+	}{
+		{
+			region:    "us-east-1",
+			partition: "aws",
+			principal: "ec2.amazonaws.com",
+		},
+		{
+			region:    "us-gov-west-1",
+			partition: "aws-us-gov",
+			principal: "ec2.amazonaws.com",
+		},
+		{
+			region:    "cn-north-1",
+			partition: "aws-cn",
+			principal: "ec2.amazonaws.com.cn",
+		},
+		{
+			region:    "unknown-region-1",
+			partition: "aws",
+			principal: "ec2.amazonaws.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.region, func(t *testing.T) {
+			partition := PartitionForRegion(tc.region)
+			if partition != tc.partition {
+				t.Fatalf("expected partition %q, got %q", tc.partition, partition)
+			}
+
+			if principal := ServicePrincipal("ec2", partition); principal != tc.principal {
+				t.Fatalf("expected service principal %q, got %q", tc.principal, principal)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	awsendpoints "github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/pkg/errors"
+)
+
+// Options configures how the endpoints of AWS services are resolved.
+type Options struct {
+	// UseFIPS uses the FIPS 140-2 validated endpoints of services known to the SDK. Services
+	// without a known FIPS endpoint fail to resolve, their endpoint has to be set in ServiceEndpoints.
+	UseFIPS bool
+
+	// UseDualStack prefers the dual-stack (IPv4 and IPv6) endpoints of services that the SDK
+	// knows to support them.
+	UseDualStack bool
+
+	// ServiceEndpoints overrides the endpoint URLs of services, by the endpoint id of the service
+	// in the SDK, e.g. ec2, elasticloadbalancing, iam, s3, secretsmanager, ssm, sts or tagging.
+	// Overrides take precedence over UseFIPS and UseDualStack.
+	ServiceEndpoints map[string]string
+}
+
+// String returns a string representation of the options, used to cache sessions by options.
+func (o Options) String() string {
+	services := make([]string, 0, len(o.ServiceEndpoints))
+	for service, endpoint := range o.ServiceEndpoints {
+		services = append(services, service+"="+endpoint)
+	}
+	sort.Strings(services)
+
+	return fmt.Sprintf("fips=%t,dualstack=%t,endpoints=%s", o.UseFIPS, o.UseDualStack, strings.Join(services, ","))
+}
+
+// IsDefault returns true if the options resolve the default endpoints of the SDK.
+func (o Options) IsDefault() bool {
+	return !o.UseFIPS && !o.UseDualStack && len(o.ServiceEndpoints) == 0
+}
+
+// EndpointFor implements the endpoints.Resolver interface of the SDK.
+func (o Options) EndpointFor(service, region string, opts ...func(*awsendpoints.Options)) (awsendpoints.ResolvedEndpoint, error) {
+	if endpoint, ok := o.ServiceEndpoints[service]; ok {
+		// Keep the signing region and name of the default endpoint, e.g. us-east-1 for iam.
+		resolved, err := awsendpoints.DefaultResolver().EndpointFor(service, region, opts...)
+		if err != nil {
+			resolved = awsendpoints.ResolvedEndpoint{SigningRegion: region}
+		}
+		resolved.URL = endpoint
+		return resolved, nil
+	}
+
+	if o.UseDualStack {
+		opts = append(opts, awsendpoints.UseDualStackOption)
+	}
+
+	if o.UseFIPS {
+		if resolved, ok := knownFIPSEndpoint(service, region, opts...); ok {
+			return resolved, nil
+		}
+		return awsendpoints.ResolvedEndpoint{}, errors.Errorf("no FIPS endpoint of service %q is known in region %q, set it with the service endpoint overrides", service, region)
+	}
+
+	return awsendpoints.DefaultResolver().EndpointFor(service, region, opts...)
+}
+
+// knownFIPSEndpoint returns the FIPS endpoint of the service in the region if the SDK knows it.
+// The SDK models FIPS endpoints as pseudo regions named either fips-<region> or <region>-fips.
+func knownFIPSEndpoint(service, region string, opts ...func(*awsendpoints.Options)) (awsendpoints.ResolvedEndpoint, bool) {
+	opts = append(opts, awsendpoints.StrictMatchingOption)
+	for _, fipsRegion := range []string{"fips-" + region, region + "-fips"} {
+		resolved, err := awsendpoints.DefaultResolver().EndpointFor(service, fipsRegion, opts...)
+		if err == nil {
+			return resolved, true
+		}
+	}
+	return awsendpoints.ResolvedEndpoint{}, false
+}
+
+// ParseServiceEndpoints parses a comma-separated list of service endpoint overrides,
+// e.g. ec2=https://localstack:4566,elasticloadbalancing=https://localstack:4566.
+func ParseServiceEndpoints(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	serviceEndpoints := map[string]string{}
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid service endpoint %q, expected <service>=<url>", item)
+		}
+
+		service, endpoint := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, errors.Errorf("invalid URL %q of service %q, expected an absolute URL", endpoint, service)
+		}
+		if _, ok := serviceEndpoints[service]; ok {
+			return nil, errors.Errorf("duplicate endpoint of service %q", service)
+		}
+
+		serviceEndpoints[service] = endpoint
+	}
+
+	return serviceEndpoints, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEndpointFor(t *testing.T) {
+	testCases := []struct {
+		name      string
+		options   Options
+		service   string
+		region    string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "default endpoint",
+			service:  "ec2",
+			region:   "us-east-1",
+			expected: "https://ec2.us-east-1.amazonaws.com",
+		},
+		{
+			name:      "fips endpoints unknown to the SDK fail to resolve",
+			options:   Options{UseFIPS: true},
+			service:   "ec2",
+			region:    "us-east-1",
+			expectErr: true,
+		},
+		{
+			name:     "fips endpoint known to the SDK",
+			options:  Options{UseFIPS: true},
+			service:  "sts",
+			region:   "us-east-2",
+			expected: "https://sts-fips.us-east-2.amazonaws.com",
+		},
+		{
+			name:      "global endpoints without a fips endpoint fail to resolve",
+			options:   Options{UseFIPS: true},
+			service:   "route53",
+			region:    "us-east-1",
+			expectErr: true,
+		},
+		{
+			name:     "dual-stack endpoint",
+			options:  Options{UseDualStack: true},
+			service:  "s3",
+			region:   "us-west-2",
+			expected: "https://s3.dualstack.us-west-2.amazonaws.com",
+		},
+		{
+			name:     "fips endpoint known to the SDK in a govcloud region",
+			options:  Options{UseFIPS: true},
+			service:  "s3",
+			region:   "us-gov-west-1",
+			expected: "https://s3-fips-us-gov-west-1.amazonaws.com",
+		},
+		{
+			name: "endpoint override",
+			options: Options{
+				UseFIPS:          true,
+				ServiceEndpoints: map[string]string{"ec2": "http://localstack:4566"},
+			},
+			service:  "ec2",
+			region:   "us-east-1",
+			expected: "http://localstack:4566",
+		},
+		{
+			name:     "services without dual-stack support keep their endpoint",
+			options:  Options{UseDualStack: true},
+			service:  "ec2",
+			region:   "us-west-2",
+			expected: "https://ec2.us-west-2.amazonaws.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved, err := tc.options.EndpointFor(tc.service, tc.region)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got endpoint %q", resolved.URL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if resolved.URL != tc.expected {
+				t.Fatalf("expected endpoint %q, got %q", tc.expected, resolved.URL)
+			}
+		})
+	}
+}
+
+func TestParseServiceEndpoints(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:  "overrides",
+			value: "ec2=https://localstack:4566,elasticloadbalancing=https://localstack:4566",
+			expected: map[string]string{
+				"ec2":                  "https://localstack:4566",
+				"elasticloadbalancing": "https://localstack:4566",
+			},
+		},
+		{
+			name:      "missing URL",
+			value:     "ec2",
+			expectErr: true,
+		},
+		{
+			name:      "relative URL",
+			value:     "ec2=localstack",
+			expectErr: true,
+		},
+		{
+			name:      "duplicate service",
+			value:     "ec2=https://a:4566,ec2=https://b:4566",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceEndpoints, err := ParseServiceEndpoints(tc.value)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(serviceEndpoints, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, serviceEndpoints)
+			}
+		})
+	}
+}
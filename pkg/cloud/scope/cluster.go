@@ -19,18 +19,28 @@ package scope
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/klogr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/endpoints"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-aws/version"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
@@ -45,6 +55,9 @@ type ClusterScopeParams struct {
 	Logger     logr.Logger
 	Cluster    *clusterv1.Cluster
 	AWSCluster *infrav1.AWSCluster
+
+	// Endpoints configures how the endpoints of the AWS services are resolved.
+	Endpoints endpoints.Options
 }
 
 // NewClusterScope creates a new Scope from the supplied parameters.
@@ -61,7 +74,7 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 		params.Logger = klogr.New()
 	}
 
-	session, err := sessionForRegion(params.AWSCluster.Spec.Region)
+	session, err := sessionForRegion(params.AWSCluster.Spec.Region, params.Endpoints)
 	if err != nil {
 		return nil, errors.Errorf("failed to create aws session: %v", err)
 	}
@@ -92,6 +105,34 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 		params.AWSClients.ResourceTagging = resourceTagging
 	}
 
+	if params.AWSClients.Route53 == nil {
+		route53Client := route53.New(session)
+		route53Client.Handlers.Build.PushFrontNamed(userAgentHandler)
+		route53Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(params.AWSCluster))
+		params.AWSClients.Route53 = route53Client
+	}
+
+	if params.AWSClients.IAM == nil {
+		iamClient := iam.New(session)
+		iamClient.Handlers.Build.PushFrontNamed(userAgentHandler)
+		iamClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(params.AWSCluster))
+		params.AWSClients.IAM = iamClient
+	}
+
+	if params.AWSClients.SSM == nil {
+		ssmClient := ssm.New(session)
+		ssmClient.Handlers.Build.PushFrontNamed(userAgentHandler)
+		ssmClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(params.AWSCluster))
+		params.AWSClients.SSM = ssmClient
+	}
+
+	if params.AWSClients.S3 == nil {
+		s3Client := s3.New(session)
+		s3Client.Handlers.Build.PushFrontNamed(userAgentHandler)
+		s3Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(params.AWSCluster))
+		params.AWSClients.S3 = s3Client
+	}
+
 	if params.AWSClients.SecretsManager == nil {
 		sClient := secretsmanager.New(session)
 		sClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(params.AWSCluster))
@@ -139,6 +180,11 @@ func (s *ClusterScope) Network() *infrav1.Network {
 	return &s.AWSCluster.Status.Network
 }
 
+// NetworkSpec returns the cluster network spec.
+func (s *ClusterScope) NetworkSpec() *infrav1.NetworkSpec {
+	return &s.AWSCluster.Spec.NetworkSpec
+}
+
 // VPC returns the cluster VPC.
 func (s *ClusterScope) VPC() *infrav1.VPCSpec {
 	return &s.AWSCluster.Spec.NetworkSpec.VPC
@@ -169,13 +215,22 @@ func (s *ClusterScope) Region() string {
 	return s.AWSCluster.Spec.Region
 }
 
+// Partition returns the AWS partition of the cluster region, e.g. aws, aws-us-gov or aws-cn.
+func (s *ClusterScope) Partition() string {
+	return endpoints.PartitionForRegion(s.Region())
+}
+
 // ControlPlaneLoadBalancer returns the AWSLoadBalancerSpec
 func (s *ClusterScope) ControlPlaneLoadBalancer() *infrav1.AWSLoadBalancerSpec {
 	return s.AWSCluster.Spec.ControlPlaneLoadBalancer
 }
 
 // ControlPlaneLoadBalancerScheme returns the Classic ELB scheme (public or internal facing)
+// Fully private clusters always use an internal load balancer.
 func (s *ClusterScope) ControlPlaneLoadBalancerScheme() infrav1.ClassicELBScheme {
+	if s.AWSCluster.Spec.NetworkSpec.FullyPrivate {
+		return infrav1.ClassicELBSchemeInternal
+	}
 	if s.ControlPlaneLoadBalancer() != nil && s.ControlPlaneLoadBalancer().Scheme != nil {
 		return *s.ControlPlaneLoadBalancer().Scheme
 	}
@@ -188,6 +243,62 @@ func (s *ClusterScope) ControlPlaneConfigMapName() string {
 	return fmt.Sprintf("%s-controlplane", s.Cluster.UID)
 }
 
+// SSHKeyName returns the name of the EC2 key pair generated for the cluster. Key pair names are
+// global to the region, so the name is qualified with the namespace of the cluster.
+func (s *ClusterScope) SSHKeyName() string {
+	return fmt.Sprintf("%s.%s", s.Namespace(), s.SSHKeySecretName())
+}
+
+// SSHKeySecretName returns the name of the secret holding the private key of the generated key pair.
+func (s *ClusterScope) SSHKeySecretName() string {
+	return fmt.Sprintf("%s-ssh-key", s.Cluster.Name)
+}
+
+// HasSSHKeySecret returns true if the secret holding the private key of the generated key pair exists.
+func (s *ClusterScope) HasSSHKeySecret() (bool, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: s.Namespace(), Name: s.SSHKeySecretName()}
+	if err := s.client.Get(context.TODO(), key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to retrieve SSH key secret for AWSCluster %s/%s", s.AWSCluster.Namespace, s.AWSCluster.Name)
+	}
+
+	return true, nil
+}
+
+// CreateSSHKeySecret stores the private key of the generated key pair in a secret owned by the AWSCluster.
+func (s *ClusterScope) CreateSSHKeySecret(privateKey []byte) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace(),
+			Name:      s.SSHKeySecretName(),
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: s.Name(),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: infrav1.GroupVersion.String(),
+					Kind:       "AWSCluster",
+					Name:       s.AWSCluster.Name,
+					UID:        s.AWSCluster.UID,
+				},
+			},
+		},
+		Type: corev1.SecretTypeSSHAuth,
+		Data: map[string][]byte{
+			corev1.SSHAuthPrivateKey: privateKey,
+		},
+	}
+
+	if err := s.client.Create(context.TODO(), secret); err != nil {
+		return errors.Wrapf(err, "failed to create SSH key secret for AWSCluster %s/%s", s.AWSCluster.Namespace, s.AWSCluster.Name)
+	}
+
+	return nil
+}
+
 // ListOptionsLabelSelector returns a ListOptions with a label selector for clusterName.
 func (s *ClusterScope) ListOptionsLabelSelector() client.ListOption {
 	return client.MatchingLabels(map[string]string{
@@ -216,12 +327,41 @@ func (s *ClusterScope) AdditionalTags() infrav1.Tags {
 
 // APIServerPort returns the APIServerPort to use when creating the load balancer.
 func (s *ClusterScope) APIServerPort() int32 {
+	if s.AWSCluster.Spec.APIServerPort != nil {
+		return *s.AWSCluster.Spec.APIServerPort
+	}
 	if s.Cluster.Spec.ClusterNetwork != nil && s.Cluster.Spec.ClusterNetwork.APIServerPort != nil {
 		return *s.Cluster.Spec.ClusterNetwork.APIServerPort
 	}
 	return 6443
 }
 
+// APIServerInstancePort returns the port the API server listens on in the control plane instances.
+// Unlike the load balancer port, it only differs from the default when the AWSCluster sets it explicitly.
+func (s *ClusterScope) APIServerInstancePort() int32 {
+	if s.AWSCluster.Spec.APIServerPort != nil {
+		return *s.AWSCluster.Spec.APIServerPort
+	}
+	return 6443
+}
+
+// defaultSecurityGroupDriftRemediationInterval is how often security group rules are checked for drift
+// when drift remediation is enabled without an interval.
+const defaultSecurityGroupDriftRemediationInterval = 5 * time.Minute
+
+// SecurityGroupDriftRemediationInterval returns how often security group rules are checked for drift,
+// or zero when drift remediation is disabled.
+func (s *ClusterScope) SecurityGroupDriftRemediationInterval() time.Duration {
+	spec := s.AWSCluster.Spec.NetworkSpec.SecurityGroupDriftRemediation
+	if spec == nil {
+		return 0
+	}
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		return spec.Interval.Duration
+	}
+	return defaultSecurityGroupDriftRemediationInterval
+}
+
 // SetFailureDomain sets the infrastructure provider failure domain key to the spec given as input.
 func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainSpec) {
 	if s.AWSCluster.Status.FailureDomains == nil {
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+)
+
+func TestSecurityGroupDriftRemediationInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *infrav1.SecurityGroupDriftRemediationSpec
+		expected time.Duration
+	}{
+		{
+			name:     "disabled",
+			expected: 0,
+		},
+		{
+			name:     "enabled without an interval, defaults it",
+			spec:     &infrav1.SecurityGroupDriftRemediationSpec{},
+			expected: defaultSecurityGroupDriftRemediationInterval,
+		},
+		{
+			name:     "enabled with a zero interval, defaults it",
+			spec:     &infrav1.SecurityGroupDriftRemediationSpec{Interval: &metav1.Duration{}},
+			expected: defaultSecurityGroupDriftRemediationInterval,
+		},
+		{
+			name:     "enabled with an interval",
+			spec:     &infrav1.SecurityGroupDriftRemediationSpec{Interval: &metav1.Duration{Duration: 30 * time.Second}},
+			expected: 30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			awsCluster := newAWSCluster("my-cluster")
+			awsCluster.Spec.NetworkSpec.SecurityGroupDriftRemediation = tt.spec
+
+			s := &ClusterScope{AWSCluster: awsCluster}
+			if interval := s.SecurityGroupDriftRemediationInterval(); interval != tt.expected {
+				t.Errorf("expected interval %v, got %v", tt.expected, interval)
+			}
+		})
+	}
+}
@@ -32,6 +32,7 @@ import (
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -44,6 +45,9 @@ type MachineScopeParams struct {
 	Machine    *clusterv1.Machine
 	AWSCluster *infrav1.AWSCluster
 	AWSMachine *infrav1.AWSMachine
+
+	// IMDSv2RequiredByDefault makes machines without instance metadata options require session tokens.
+	IMDSv2RequiredByDefault bool
 }
 
 // NewMachineScope creates a new MachineScope from the supplied parameters.
@@ -82,6 +86,8 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 		Machine:    params.Machine,
 		AWSCluster: params.AWSCluster,
 		AWSMachine: params.AWSMachine,
+
+		imdsv2RequiredByDefault: params.IMDSv2RequiredByDefault,
 	}, nil
 }
 
@@ -95,6 +101,8 @@ type MachineScope struct {
 	Machine    *clusterv1.Machine
 	AWSCluster *infrav1.AWSCluster
 	AWSMachine *infrav1.AWSMachine
+
+	imdsv2RequiredByDefault bool
 }
 
 // Name returns the AWSMachine name.
@@ -187,11 +195,38 @@ func (m *MachineScope) UseSecretsManager() bool {
 }
 
 // UserDataIsCompressed returns the computed value of whether or not
-// userdata should be compressed using gzip.
+// userdata should be compressed using gzip. Ignition doesn't support compressed userdata.
 func (m *MachineScope) UserDataIsUncompressed() bool {
+	if m.UseIgnition() {
+		return true
+	}
 	return m.AWSMachine.Spec.UncompressedUserData != nil && *m.AWSMachine.Spec.UncompressedUserData
 }
 
+// UseIgnition returns true if the bootstrap data is an Ignition config stored in the S3 bucket of the cluster.
+func (m *MachineScope) UseIgnition() bool {
+	return m.AWSMachine.Spec.Ignition != nil
+}
+
+// IgnitionVersion returns the Ignition config specification version of the userdata.
+func (m *MachineScope) IgnitionVersion() string {
+	if m.AWSMachine.Spec.Ignition == nil || m.AWSMachine.Spec.Ignition.Version == "" {
+		return infrav1.DefaultIgnitionVersion
+	}
+	return m.AWSMachine.Spec.Ignition.Version
+}
+
+// InstanceMetadataOptions returns the instance metadata service options of the
+// instance, requiring session tokens by default when IMDSv2RequiredByDefault is set.
+func (m *MachineScope) InstanceMetadataOptions() *infrav1.InstanceMetadataOptions {
+	if m.AWSMachine.Spec.InstanceMetadataOptions != nil || !m.imdsv2RequiredByDefault {
+		return m.AWSMachine.Spec.InstanceMetadataOptions
+	}
+	return &infrav1.InstanceMetadataOptions{
+		HTTPTokens: "required",
+	}
+}
+
 // GetSecretPrefix returns the prefix for the secrets belonging
 // to the AWSMachine in AWS Secrets Manager
 func (m *MachineScope) GetSecretPrefix() string {
@@ -256,6 +291,21 @@ func (m *MachineScope) GetRawBootstrapData() ([]byte, error) {
 	return value, nil
 }
 
+// GetClusterCACertificate returns the PEM encoded certificate of the cluster CA.
+func (m *MachineScope) GetClusterCACertificate() ([]byte, error) {
+	caSecret, err := secret.Get(context.TODO(), m.client, types.NamespacedName{Namespace: m.Namespace(), Name: m.Cluster.Name}, secret.ClusterCA)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve cluster CA secret for AWSMachine %s/%s", m.Namespace(), m.Name())
+	}
+
+	value, ok := caSecret.Data[secret.TLSCrtDataName]
+	if !ok {
+		return nil, errors.Errorf("error retrieving cluster CA: secret %s key is missing", secret.TLSCrtDataName)
+	}
+
+	return value, nil
+}
+
 // PatchObject persists the machine spec and status.
 func (m *MachineScope) PatchObject() error {
 	return m.patchHelper.Patch(context.TODO(), m.AWSMachine)
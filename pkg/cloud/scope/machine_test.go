@@ -201,3 +201,26 @@ func TestSetSecretARN(t *testing.T) {
 		t.Fatalf("prefix does not equal %s: %s", prefix, val)
 	}
 }
+
+func TestInstanceMetadataOptions(t *testing.T) {
+	scope, err := setupMachineScope()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scope.InstanceMetadataOptions() != nil {
+		t.Fatalf("InstanceMetadataOptions should be nil")
+	}
+
+	scope.imdsv2RequiredByDefault = true
+	if opts := scope.InstanceMetadataOptions(); opts == nil || opts.HTTPTokens != "required" {
+		t.Fatalf("InstanceMetadataOptions should require session tokens, got %v", opts)
+	}
+
+	scope.AWSMachine.Spec.InstanceMetadataOptions = &infrav1.InstanceMetadataOptions{
+		HTTPTokens: "optional",
+	}
+	if opts := scope.InstanceMetadataOptions(); opts.HTTPTokens != "optional" {
+		t.Fatalf("InstanceMetadataOptions should use the AWSMachine options, got %v", opts)
+	}
+}
@@ -21,23 +21,30 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/endpoints"
 )
 
 var (
 	sessionCache sync.Map
 )
 
-func sessionForRegion(region string) (*session.Session, error) {
-	s, ok := sessionCache.Load(region)
+func sessionForRegion(region string, opts endpoints.Options) (*session.Session, error) {
+	key := region + "/" + opts.String()
+	s, ok := sessionCache.Load(key)
 	if ok {
 		return s.(*session.Session), nil
 	}
 
-	ns, err := session.NewSession(aws.NewConfig().WithRegion(region))
+	config := aws.NewConfig().WithRegion(region)
+	if !opts.IsDefault() {
+		config = config.WithEndpointResolver(opts)
+	}
+
+	ns, err := session.NewSession(config)
 	if err != nil {
 		return nil, err
 	}
 
-	sessionCache.Store(region, ns)
+	sessionCache.Store(key, ns)
 	return ns, nil
 }
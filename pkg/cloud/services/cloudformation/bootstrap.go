@@ -26,7 +26,9 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/klog"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/endpoints"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
 )
 
@@ -40,14 +42,16 @@ const (
 var ManagedIAMPolicyNames = [...]string{ControllersPolicy, ControlPlanePolicy, NodePolicy}
 
 // BootstrapTemplate is an AWS CloudFormation template to bootstrap
-// IAM policies, users and roles for use by Cluster API Provider AWS
-func BootstrapTemplate(accountID, partition string, extraControlPlanePolicies, extraNodePolicies []string) *cloudformation.Template {
+// IAM policies, users and roles for use by Cluster API Provider AWS.
+// When set, permissionsBoundary is the ARN of the managed policy used as
+// permissions boundary of the created users and roles.
+func BootstrapTemplate(accountID, partition, permissionsBoundary string, extraControlPlanePolicies, extraNodePolicies []string) *cloudformation.Template {
 	template := cloudformation.NewTemplate()
 
 	template.Resources[ControllersPolicy] = &cfn_iam.ManagedPolicy{
 		ManagedPolicyName: iam.NewManagedName("controllers"),
 		Description:       `For the Kubernetes Cluster API Provider AWS Controllers`,
-		PolicyDocument:    controllersPolicy(accountID, partition),
+		PolicyDocument:    controllersPolicy(accountID, partition, permissionsBoundary, append(append([]string{}, extraControlPlanePolicies...), extraNodePolicies...)),
 		Groups: []string{
 			cloudformation.Ref("AWSIAMGroupBootstrapper"),
 		},
@@ -77,7 +81,8 @@ func BootstrapTemplate(accountID, partition string, extraControlPlanePolicies, e
 	}
 
 	template.Resources["AWSIAMUserBootstrapper"] = &cfn_iam.User{
-		UserName: iam.NewManagedName("bootstrapper"),
+		UserName:            iam.NewManagedName("bootstrapper"),
+		PermissionsBoundary: permissionsBoundary,
 		Groups: []string{
 			cloudformation.Ref("AWSIAMGroupBootstrapper"),
 		},
@@ -89,19 +94,22 @@ func BootstrapTemplate(accountID, partition string, extraControlPlanePolicies, e
 
 	template.Resources["AWSIAMRoleControlPlane"] = &cfn_iam.Role{
 		RoleName:                 iam.NewManagedName("control-plane"),
-		AssumeRolePolicyDocument: ec2AssumeRolePolicy(),
+		AssumeRolePolicyDocument: ec2AssumeRolePolicy(partition),
 		ManagedPolicyArns:        extraControlPlanePolicies,
+		PermissionsBoundary:      permissionsBoundary,
 	}
 
 	template.Resources["AWSIAMRoleControllers"] = &cfn_iam.Role{
 		RoleName:                 iam.NewManagedName("controllers"),
-		AssumeRolePolicyDocument: ec2AssumeRolePolicy(),
+		AssumeRolePolicyDocument: ec2AssumeRolePolicy(partition),
+		PermissionsBoundary:      permissionsBoundary,
 	}
 
 	template.Resources["AWSIAMRoleNodes"] = &cfn_iam.Role{
 		RoleName:                 iam.NewManagedName("nodes"),
-		AssumeRolePolicyDocument: ec2AssumeRolePolicy(),
+		AssumeRolePolicyDocument: ec2AssumeRolePolicy(partition),
 		ManagedPolicyArns:        extraNodePolicies,
+		PermissionsBoundary:      permissionsBoundary,
 	}
 
 	template.Resources["AWSIAMInstanceProfileControlPlane"] = &cfn_iam.InstanceProfile{
@@ -128,20 +136,23 @@ func BootstrapTemplate(accountID, partition string, extraControlPlanePolicies, e
 	return template
 }
 
-func ec2AssumeRolePolicy() *iam.PolicyDocument {
+func ec2AssumeRolePolicy(partition string) *iam.PolicyDocument {
 	return &iam.PolicyDocument{
 		Version: iam.CurrentVersion,
 		Statement: []iam.StatementEntry{
 			{
 				Effect:    "Allow",
-				Principal: iam.Principals{"Service": iam.PrincipalID{"ec2.amazonaws.com"}},
+				Principal: iam.Principals{"Service": iam.PrincipalID{endpoints.ServicePrincipal("ec2", partition)}},
 				Action:    iam.Actions{"sts:AssumeRole"},
 			},
 		},
 	}
 }
 
-func controllersPolicy(accountID, partition string) *iam.PolicyDocument {
+// controllersPolicy returns the policy of the controllers. The controllers can only attach the managed
+// control plane and node policies, the Session Manager policy and the given extra policies to the roles
+// they create. When permissionsBoundary is set, the roles they create must use it as permissions boundary.
+func controllersPolicy(accountID, partition, permissionsBoundary string, extraPolicies []string) *iam.PolicyDocument {
 	return &iam.PolicyDocument{
 		Version: iam.CurrentVersion,
 		Statement: []iam.StatementEntry{
@@ -149,62 +160,113 @@ func controllersPolicy(accountID, partition string) *iam.PolicyDocument {
 				Effect:   iam.EffectAllow,
 				Resource: iam.Resources{"*"},
 				Action: iam.Actions{
+					"ec2:AcceptVpcPeeringConnection",
 					"ec2:AllocateAddress",
+					"ec2:AssociateAddress",
+					"ec2:AssociateDhcpOptions",
 					"ec2:AssociateRouteTable",
+					"ec2:AssociateTransitGatewayRouteTable",
 					"ec2:AttachInternetGateway",
+					"ec2:AuthorizeSecurityGroupEgress",
 					"ec2:AuthorizeSecurityGroupIngress",
+					"ec2:CreateDhcpOptions",
+					"ec2:CreateFlowLogs",
 					"ec2:CreateInternetGateway",
+					"ec2:CreateKeyPair",
 					"ec2:CreateNatGateway",
+					"ec2:CreateNetworkAcl",
+					"ec2:CreateNetworkAclEntry",
+					"ec2:CreatePlacementGroup",
 					"ec2:CreateRoute",
 					"ec2:CreateRouteTable",
 					"ec2:CreateSecurityGroup",
 					"ec2:CreateSubnet",
 					"ec2:CreateTags",
+					"ec2:CreateTransitGatewayVpcAttachment",
 					"ec2:CreateVpc",
+					"ec2:CreateVpcEndpoint",
+					"ec2:CreateVpcPeeringConnection",
 					"ec2:ModifyVpcAttribute",
+					"ec2:DeleteDhcpOptions",
+					"ec2:DeleteFlowLogs",
 					"ec2:DeleteInternetGateway",
+					"ec2:DeleteKeyPair",
 					"ec2:DeleteNatGateway",
+					"ec2:DeleteNetworkAcl",
+					"ec2:DeleteNetworkAclEntry",
+					"ec2:DeletePlacementGroup",
+					"ec2:DeleteRoute",
 					"ec2:DeleteRouteTable",
 					"ec2:DeleteSecurityGroup",
 					"ec2:DeleteSubnet",
 					"ec2:DeleteTags",
+					"ec2:DeleteTransitGatewayVpcAttachment",
 					"ec2:DeleteVpc",
+					"ec2:DeleteVpcEndpoints",
+					"ec2:DeleteVpcPeeringConnection",
 					"ec2:DescribeAccountAttributes",
 					"ec2:DescribeAddresses",
 					"ec2:DescribeAvailabilityZones",
+					"ec2:DescribeDhcpOptions",
+					"ec2:DescribeFlowLogs",
 					"ec2:DescribeInstances",
+					"ec2:DescribeInstanceTypes",
 					"ec2:DescribeInternetGateways",
 					"ec2:DescribeImages",
+					"ec2:DescribeKeyPairs",
 					"ec2:DescribeNatGateways",
+					"ec2:DescribeNetworkAcls",
 					"ec2:DescribeNetworkInterfaces",
 					"ec2:DescribeNetworkInterfaceAttribute",
+					"ec2:DescribePlacementGroups",
 					"ec2:DescribeRouteTables",
 					"ec2:DescribeSecurityGroups",
 					"ec2:DescribeSubnets",
+					"ec2:DescribeTags",
+					"ec2:DescribeTransitGatewayAttachments",
+					"ec2:DescribeTransitGatewayVpcAttachments",
 					"ec2:DescribeVpcs",
 					"ec2:DescribeVpcAttribute",
+					"ec2:DescribeVpcEndpoints",
+					"ec2:DescribeVpcEndpointServices",
+					"ec2:DescribeVpcPeeringConnections",
 					"ec2:DescribeVolumes",
 					"ec2:DetachInternetGateway",
 					"ec2:DisassociateRouteTable",
 					"ec2:DisassociateAddress",
+					"ec2:EnableTransitGatewayRouteTablePropagation",
+					"ec2:GetTransitGatewayRouteTablePropagations",
 					"ec2:ModifyInstanceAttribute",
 					"ec2:ModifyNetworkInterfaceAttribute",
 					"ec2:ModifySubnetAttribute",
+					"ec2:ModifyVpcEndpoint",
 					"ec2:ReleaseAddress",
+					"ec2:ReplaceNetworkAclAssociation",
+					"ec2:ReplaceNetworkAclEntry",
+					"ec2:ReplaceRoute",
+					"ec2:RevokeSecurityGroupEgress",
 					"ec2:RevokeSecurityGroupIngress",
 					"ec2:RunInstances",
 					"ec2:TerminateInstances",
 					"tag:GetResources",
 					"elasticloadbalancing:AddTags",
+					"elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+					"elasticloadbalancing:AttachLoadBalancerToSubnets",
 					"elasticloadbalancing:CreateLoadBalancer",
+					"elasticloadbalancing:CreateLoadBalancerListeners",
+					"elasticloadbalancing:CreateLoadBalancerPolicy",
 					"elasticloadbalancing:ConfigureHealthCheck",
 					"elasticloadbalancing:DeleteLoadBalancer",
+					"elasticloadbalancing:DeleteLoadBalancerListeners",
+					"elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
+					"elasticloadbalancing:DetachLoadBalancerFromSubnets",
 					"elasticloadbalancing:DescribeLoadBalancers",
 					"elasticloadbalancing:DescribeLoadBalancerAttributes",
 					"elasticloadbalancing:DescribeTags",
 					"elasticloadbalancing:ModifyLoadBalancerAttributes",
 					"elasticloadbalancing:RegisterInstancesWithLoadBalancer",
 					"elasticloadbalancing:RemoveTags",
+					"elasticloadbalancing:SetLoadBalancerPoliciesForBackendServer",
 				},
 			},
 			{
@@ -221,18 +283,72 @@ func controllersPolicy(accountID, partition string) *iam.PolicyDocument {
 					"StringLike": map[string]string{"iam:AWSServiceName": "elasticloadbalancing.amazonaws.com"},
 				},
 			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{
+					fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, accountID, iam.NewManagedName("*")),
+					fmt.Sprintf("arn:%s:iam::%s:role%s*", partition, accountID, iam.ManagedPath),
+				},
+				Action: iam.Actions{
+					"iam:PassRole",
+				},
+			},
 			{
 				Effect: iam.EffectAllow,
 				Resource: iam.Resources{fmt.Sprintf(
-					"arn:%s:iam::%s:role/%s",
+					"arn:%s:iam::%s:role%s*",
 					partition,
 					accountID,
-					iam.NewManagedName("*"),
+					infrav1.FlowLogsRolePath,
 				)},
 				Action: iam.Actions{
 					"iam:PassRole",
 				},
+				Condition: iam.Conditions{
+					"StringEquals": map[string]string{"iam:PassedToService": "vpc-flow-logs.amazonaws.com"},
+				},
+			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{
+					fmt.Sprintf("arn:%s:iam::%s:role/*", partition, accountID),
+					fmt.Sprintf("arn:%s:iam::%s:instance-profile/*", partition, accountID),
+				},
+				Action: iam.Actions{
+					"iam:GetInstanceProfile",
+					"iam:GetRole",
+					"iam:ListAttachedRolePolicies",
+				},
+			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{
+					fmt.Sprintf("arn:%s:iam::%s:role%s*", partition, accountID, iam.ManagedPath),
+					fmt.Sprintf("arn:%s:iam::%s:instance-profile%s*", partition, accountID, iam.ManagedPath),
+				},
+				Action: iam.Actions{
+					"iam:AddRoleToInstanceProfile",
+					"iam:CreateInstanceProfile",
+					"iam:DeleteInstanceProfile",
+					"iam:DeleteRole",
+					"iam:DetachRolePolicy",
+					"iam:RemoveRoleFromInstanceProfile",
+					"iam:TagRole",
+				},
+			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{
+					fmt.Sprintf("arn:%s:iam::%s:role%s*", partition, accountID, iam.ManagedPath),
+				},
+				Action: iam.Actions{
+					"iam:AttachRolePolicy",
+				},
+				Condition: iam.Conditions{
+					"ArnEquals": map[string][]string{"iam:PolicyARN": attachablePolicyARNs(accountID, partition, extraPolicies)},
+				},
 			},
+			managedRolesBoundaryPolicy(accountID, partition, permissionsBoundary),
 			{
 				Effect: iam.EffectAllow,
 				Resource: iam.Resources{fmt.Sprintf(
@@ -245,8 +361,82 @@ func controllersPolicy(accountID, partition string) *iam.PolicyDocument {
 					"secretsmanager:TagResource",
 				},
 			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{fmt.Sprintf(
+					"arn:%s:route53:::hostedzone/*",
+					partition,
+				)},
+				Action: iam.Actions{
+					"route53:ChangeResourceRecordSets",
+					"route53:ListResourceRecordSets",
+				},
+			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{fmt.Sprintf(
+					"arn:%s:s3:::%s*",
+					partition,
+					infrav1.S3BucketNamePrefix,
+				)},
+				Action: iam.Actions{
+					"s3:CreateBucket",
+					"s3:DeleteBucket",
+					"s3:DeleteObject",
+					"s3:GetBucketTagging",
+					"s3:ListBucket",
+					"s3:PutBucketPolicy",
+					"s3:PutBucketPublicAccessBlock",
+					"s3:PutBucketTagging",
+					"s3:PutObject",
+				},
+			},
+			{
+				Effect: iam.EffectAllow,
+				Resource: iam.Resources{
+					fmt.Sprintf("arn:%s:ssm:*::parameter/aws/service/bottlerocket/*", partition),
+					fmt.Sprintf("arn:%s:ssm:*::parameter/aws/service/eks/optimized-ami/*", partition),
+					fmt.Sprintf("arn:%s:ssm:*:%s:parameter%s*", partition, accountID, infrav1.ImageLookupSSMParameterPrefix),
+				},
+				Action: iam.Actions{
+					"ssm:GetParameter",
+				},
+			},
+		},
+	}
+}
+
+// attachablePolicyARNs returns the ARNs of the policies the controllers can attach to the roles they create.
+func attachablePolicyARNs(accountID, partition string, extraPolicies []string) []string {
+	arns := []string{
+		fmt.Sprintf("arn:%s:iam::%s:policy/%s", partition, accountID, iam.NewManagedName("control-plane")),
+		fmt.Sprintf("arn:%s:iam::%s:policy/%s", partition, accountID, iam.NewManagedName("nodes")),
+		fmt.Sprintf("arn:%s:iam::aws:policy/AmazonSSMManagedInstanceCore", partition),
+	}
+	return append(arns, extraPolicies...)
+}
+
+// managedRolesBoundaryPolicy allows the controllers to create roles and set their permissions boundary,
+// restricted to the given permissions boundary if one is configured.
+func managedRolesBoundaryPolicy(accountID, partition, permissionsBoundary string) iam.StatementEntry {
+	statement := iam.StatementEntry{
+		Effect: iam.EffectAllow,
+		Resource: iam.Resources{
+			fmt.Sprintf("arn:%s:iam::%s:role%s*", partition, accountID, iam.ManagedPath),
+		},
+		Action: iam.Actions{
+			"iam:CreateRole",
+			"iam:PutRolePermissionsBoundary",
 		},
 	}
+
+	if permissionsBoundary != "" {
+		statement.Condition = iam.Conditions{
+			"StringEquals": map[string]string{"iam:PermissionsBoundary": permissionsBoundary},
+		}
+	}
+
+	return statement
 }
 
 func bootstrapSecretPolicy(partition string) iam.StatementEntry {
@@ -385,7 +575,7 @@ func cloudProviderNodeAwsPolicy() *iam.PolicyDocument {
 func getPolicyDocFromPolicyName(policyName, accountID, partition string) (*iam.PolicyDocument, error) {
 	switch policyName {
 	case ControllersPolicy:
-		return controllersPolicy(accountID, partition), nil
+		return controllersPolicy(accountID, partition, "", nil), nil
 	case ControlPlanePolicy:
 		return cloudProviderControlPlaneAwsPolicy(), nil
 	case NodePolicy:
@@ -417,9 +607,9 @@ func (s *Service) GenerateManagedIAMPolicyDocuments(policyDocDir, accountID, par
 }
 
 // ReconcileBootstrapStack creates or updates bootstrap CloudFormation
-func (s *Service) ReconcileBootstrapStack(stackName, accountID, partition string, extraControlPlanePolicies, extraNodePolicies []string) error {
+func (s *Service) ReconcileBootstrapStack(stackName, accountID, partition, permissionsBoundary string, extraControlPlanePolicies, extraNodePolicies []string) error {
 
-	template := BootstrapTemplate(accountID, partition, extraControlPlanePolicies, extraNodePolicies)
+	template := BootstrapTemplate(accountID, partition, permissionsBoundary, extraControlPlanePolicies, extraNodePolicies)
 	yaml, err := template.YAML()
 	processedYaml := string(yaml)
 	if err != nil {
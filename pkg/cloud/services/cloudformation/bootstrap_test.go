@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
+)
+
+const (
+	testAccountID = "123456789012"
+	testPartition = "aws"
+)
+
+func TestControllersPolicyActions(t *testing.T) {
+	policy := controllersPolicy(testAccountID, testPartition, "", nil)
+
+	testCases := []struct {
+		action   string
+		resource string
+	}{
+		{
+			action:   "elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+			resource: "*",
+		},
+		{
+			action:   "ssm:GetParameter",
+			resource: "arn:aws:ssm:*:123456789012:parameter/cluster-api-provider-aws/*",
+		},
+		{
+			action:   "s3:CreateBucket",
+			resource: "arn:aws:s3:::cluster-api-provider-aws-*",
+		},
+		{
+			action:   "s3:ListBucket",
+			resource: "arn:aws:s3:::cluster-api-provider-aws-*",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.action, func(t *testing.T) {
+			if !allowsAction(policy, tc.action, tc.resource) {
+				t.Fatalf("expected the controllers policy to allow %q on %q", tc.action, tc.resource)
+			}
+		})
+	}
+}
+
+func TestControllersPolicyScopesS3(t *testing.T) {
+	policy := controllersPolicy(testAccountID, testPartition, "", nil)
+
+	for _, statement := range policy.Statement {
+		for _, a := range statement.Action {
+			if !strings.HasPrefix(a, "s3:") {
+				continue
+			}
+			for _, r := range statement.Resource {
+				if r != "arn:aws:s3:::cluster-api-provider-aws-*" {
+					t.Fatalf("expected %q to be scoped to the managed bucket prefix, got resource %q", a, r)
+				}
+			}
+		}
+	}
+}
+
+// allowsAction returns true if a statement of the policy allows the action on exactly the given resource.
+func allowsAction(policy *iam.PolicyDocument, action, resource string) bool {
+	for _, statement := range policy.Statement {
+		if statement.Effect != iam.EffectAllow {
+			continue
+		}
+		for _, a := range statement.Action {
+			if a != action {
+				continue
+			}
+			for _, r := range statement.Resource {
+				if r == resource {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func TestControllersPolicyScopesSSMParameters(t *testing.T) {
+	policy := controllersPolicy(testAccountID, testPartition, "", nil)
+
+	for _, statement := range policy.Statement {
+		for _, a := range statement.Action {
+			if !strings.HasPrefix(a, "ssm:") {
+				continue
+			}
+			for _, r := range statement.Resource {
+				if !strings.HasPrefix(r, "arn:aws:ssm:*::parameter/aws/service/") && r != "arn:aws:ssm:*:123456789012:parameter/cluster-api-provider-aws/*" {
+					t.Fatalf("expected %q to be scoped to public or managed parameters, got resource %q", a, r)
+				}
+			}
+		}
+	}
+}
+
+func TestControllersPolicyScopesFlowLogsPassRole(t *testing.T) {
+	policy := controllersPolicy(testAccountID, testPartition, "", nil)
+
+	for _, statement := range policy.Statement {
+		condition, ok := statement.Condition["StringEquals"].(map[string]string)
+		if !ok || condition["iam:PassedToService"] != "vpc-flow-logs.amazonaws.com" {
+			continue
+		}
+		for _, r := range statement.Resource {
+			if r != "arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/*" {
+				t.Fatalf("expected roles passed to flow logs to be scoped to the flow logs role path, got resource %q", r)
+			}
+		}
+	}
+	if !allowsAction(policy, "iam:PassRole", "arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/*") {
+		t.Fatal("expected the controllers policy to allow passing flow logs roles")
+	}
+}
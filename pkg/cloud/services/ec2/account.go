@@ -41,3 +41,42 @@ func (s *Service) getAvailableZones() ([]string, error) {
 	sort.Strings(zones)
 	return zones, nil
 }
+
+// getSubnetZones returns the availability zones default subnets are spread across,
+// restricted to the configured zones and capped by the zone usage limit.
+func (s *Service) getSubnetZones() ([]string, error) {
+	zones, err := s.getAvailableZones()
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed := s.scope.NetworkSpec().AvailabilityZones; len(allowed) > 0 {
+		selected := make([]string, 0, len(allowed))
+		for _, zone := range zones {
+			for _, name := range allowed {
+				if zone == name {
+					selected = append(selected, zone)
+					break
+				}
+			}
+		}
+		if len(selected) == 0 {
+			return nil, errors.Errorf("none of the availability zones %v are available", allowed)
+		}
+		zones = selected
+	}
+
+	if len(zones) == 0 {
+		return nil, errors.New("no availability zones available")
+	}
+
+	limit := 1
+	if s.scope.NetworkSpec().AvailabilityZoneUsageLimit != nil {
+		limit = *s.scope.NetworkSpec().AvailabilityZoneUsageLimit
+	}
+	if limit > 0 && len(zones) > limit {
+		zones = zones[:limit]
+	}
+
+	return zones, nil
+}
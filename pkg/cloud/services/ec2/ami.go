@@ -24,7 +24,10 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
 )
 
 const (
@@ -44,19 +47,39 @@ const (
 	// 4. a `-` followed by any additional characters
 	amiNameFormat = "capa-ami-%s-?%s-*"
 
+	// bottlerocketAMIParameterFormat is the SSM parameter holding the latest Bottlerocket AMI
+	// for a Kubernetes minor version, for example: 1.17, and an architecture.
+	bottlerocketAMIParameterFormat = "/aws/service/bottlerocket/aws-k8s-%s/%s/latest/image_id"
+
+	// eksGPUAMIParameterFormat is the SSM parameter holding the recommended Amazon EKS optimized
+	// accelerated AMI for a Kubernetes minor version.
+	eksGPUAMIParameterFormat = "/aws/service/eks/optimized-ami/%s/amazon-linux-2-gpu/recommended/image_id"
+
+	// bastionAMIName is the name of the Ubuntu 18.04 images looked up for the bastion host in
+	// partitions the bastion AMIs below aren't available in.
+	bastionAMIName = "ubuntu/images/hvm-ssd/ubuntu-bionic-18.04-amd64-server-*"
+
 	// Amazon's AMI timestamp format
 	createDateTimestampFormat = "2006-01-02T15:04:05.000Z"
 )
 
+var (
+	// canonicalOwnerIDs are the accounts Canonical publishes Ubuntu images from, by partition.
+	canonicalOwnerIDs = map[string]string{
+		"aws-us-gov": "513442679011",
+		"aws-cn":     "837727238323",
+	}
+)
+
 func amiName(baseOS, kubernetesVersion string) string {
 	// strip the v (if present) to be able to match images with or without a v prefix
 	return fmt.Sprintf(amiNameFormat, baseOS, strings.TrimPrefix(kubernetesVersion, "v"))
 }
 
 // defaultAMILookup returns the default AMI based on region
-func (s *Service) defaultAMILookup(ownerID, baseOS, kubernetesVersion string) (string, error) {
-	if ownerID == "" {
-		ownerID = defaultMachineAMIOwnerID
+func (s *Service) defaultAMILookup(ownerIDs []string, baseOS, architecture, kubernetesVersion string, filters []infrav1.Filter) (string, error) {
+	if len(ownerIDs) == 0 {
+		ownerIDs = []string{defaultMachineAMIOwnerID}
 	}
 	if baseOS == "" {
 		baseOS = defaultMachineAMILookupBaseOS
@@ -65,7 +88,7 @@ func (s *Service) defaultAMILookup(ownerID, baseOS, kubernetesVersion string) (s
 		Filters: []*ec2.Filter{
 			{
 				Name:   aws.String("owner-id"),
-				Values: []*string{aws.String(ownerID)},
+				Values: aws.StringSlice(ownerIDs),
 			},
 			{
 				Name:   aws.String("name"),
@@ -73,7 +96,7 @@ func (s *Service) defaultAMILookup(ownerID, baseOS, kubernetesVersion string) (s
 			},
 			{
 				Name:   aws.String("architecture"),
-				Values: []*string{aws.String("x86_64")},
+				Values: []*string{aws.String(architecture)},
 			},
 			{
 				Name:   aws.String("state"),
@@ -85,6 +108,7 @@ func (s *Service) defaultAMILookup(ownerID, baseOS, kubernetesVersion string) (s
 			},
 		},
 	}
+	describeImageInput.Filters = append(describeImageInput.Filters, converters.FiltersToSDK(filters)...)
 
 	out, err := s.scope.EC2.DescribeImages(describeImageInput)
 	if err != nil {
@@ -102,6 +126,98 @@ func (s *Service) defaultAMILookup(ownerID, baseOS, kubernetesVersion string) (s
 	return aws.StringValue(latestImage.ImageId), nil
 }
 
+// bottlerocketAMILookup returns the latest Bottlerocket AMI for the architecture and Kubernetes minor version.
+func (s *Service) bottlerocketAMILookup(architecture, kubernetesVersion string) (string, error) {
+	minorVersion, err := kubernetesMinorVersion(kubernetesVersion)
+	if err != nil {
+		return "", err
+	}
+
+	return s.ssmParameterAMILookup(fmt.Sprintf(bottlerocketAMIParameterFormat, minorVersion, architecture))
+}
+
+// ssmParameterAMILookup returns the AMI ID stored in an SSM parameter.
+func (s *Service) ssmParameterAMILookup(name string) (string, error) {
+	out, err := s.scope.SSM.GetParameter(&ssm.GetParameterInput{
+		Name: aws.String(name),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get ami from SSM parameter %q", name)
+	}
+	if out.Parameter == nil || aws.StringValue(out.Parameter.Value) == "" {
+		return "", errors.Errorf("SSM parameter %q has no value", name)
+	}
+
+	s.scope.V(2).Info("Found and using an AMI from SSM parameter", "parameter", name, "ami-id", aws.StringValue(out.Parameter.Value))
+	return aws.StringValue(out.Parameter.Value), nil
+}
+
+// kubernetesMinorVersion returns the minor version of a Kubernetes version, for example: 1.17 for v1.17.3.
+func kubernetesMinorVersion(kubernetesVersion string) (string, error) {
+	version := strings.Split(strings.TrimPrefix(kubernetesVersion, "v"), ".")
+	if len(version) < 2 {
+		return "", errors.Errorf("invalid kubernetes version %q", kubernetesVersion)
+	}
+
+	return version[0] + "." + version[1], nil
+}
+
+// describeInstanceType returns the information of an instance type, e.g. its architectures and GPUs.
+func (s *Service) describeInstanceType(instanceType string) (*ec2.InstanceTypeInfo, error) {
+	out, err := s.scope.EC2.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{aws.String(instanceType)},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe instance type %q", instanceType)
+	}
+	if len(out.InstanceTypes) == 0 || out.InstanceTypes[0].ProcessorInfo == nil {
+		return nil, errors.Errorf("found no instance type %q", instanceType)
+	}
+
+	return out.InstanceTypes[0], nil
+}
+
+// instanceTypeArchitecture returns the architecture of the AMIs an instance type can run,
+// x86_64 or arm64 for Graviton instance types.
+func instanceTypeArchitecture(info *ec2.InstanceTypeInfo) (string, error) {
+	architectures := aws.StringValueSlice(info.ProcessorInfo.SupportedArchitectures)
+	for _, architecture := range []string{ec2.ArchitectureTypeX8664, ec2.ArchitectureTypeArm64} {
+		for _, supported := range architectures {
+			if supported == architecture {
+				return architecture, nil
+			}
+		}
+	}
+
+	return "", errors.Errorf("instance type %q has no supported architecture: %v", aws.StringValue(info.InstanceType), architectures)
+}
+
+// instanceTypeHasGPUs returns true if an instance type has GPUs.
+func instanceTypeHasGPUs(info *ec2.InstanceTypeInfo) bool {
+	return info.GpuInfo != nil && len(info.GpuInfo.Gpus) > 0
+}
+
+// gpuAMILookup returns the latest GPU-optimized AMI of a family for the Kubernetes minor version.
+func (s *Service) gpuAMILookup(family, architecture, kubernetesVersion string) (string, error) {
+	if architecture != ec2.ArchitectureTypeX8664 {
+		return "", errors.Errorf("GPU-optimized AMIs of family %q aren't available for architecture %q", family, architecture)
+	}
+
+	var name string
+	switch family {
+	case infrav1.GPUAMIFamilyEKS:
+		minorVersion, err := kubernetesMinorVersion(kubernetesVersion)
+		if err != nil {
+			return "", err
+		}
+		name = fmt.Sprintf(eksGPUAMIParameterFormat, minorVersion)
+	default:
+		return "", errors.Errorf("unknown GPU-optimized AMI family %q", family)
+	}
+
+	return s.ssmParameterAMILookup(name)
+}
+
 type images []*ec2.Image
 
 // Len is the number of elements in the collection.
@@ -135,6 +251,48 @@ func getLatestImage(imgs []*ec2.Image) (*ec2.Image, error) {
 	return imgs[len(imgs)-1], nil
 }
 
+// bastionAMILookup returns the AMI of the bastion host. Ubuntu images are looked up in the AWS
+// GovCloud (US) and China partitions, the default bastion AMI of the region is used otherwise.
+func (s *Service) bastionAMILookup() (string, error) {
+	ownerID, ok := canonicalOwnerIDs[s.scope.Partition()]
+	if !ok {
+		return s.defaultBastionAMILookup(s.scope.Region()), nil
+	}
+
+	out, err := s.scope.EC2.DescribeImages(&ec2.DescribeImagesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("owner-id"),
+				Values: aws.StringSlice([]string{ownerID}),
+			},
+			{
+				Name:   aws.String("name"),
+				Values: aws.StringSlice([]string{bastionAMIName}),
+			},
+			{
+				Name:   aws.String("architecture"),
+				Values: aws.StringSlice([]string{ec2.ArchitectureValuesX8664}),
+			},
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{ec2.ImageStateAvailable}),
+			},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find bastion ami: %q", bastionAMIName)
+	}
+	if len(out.Images) == 0 {
+		return "", errors.Errorf("found no bastion AMIs with the name: %q", bastionAMIName)
+	}
+
+	latestImage, err := getLatestImage(out.Images)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(latestImage.ImageId), nil
+}
+
 func (s *Service) defaultBastionAMILookup(region string) string {
 	switch region {
 	case "ap-northeast-1":
@@ -23,9 +23,11 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/golang/mock/gomock"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ssmiface"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
 
@@ -79,7 +81,7 @@ func TestAMIs(t *testing.T) {
 			tc.expect(ec2Mock.EXPECT())
 
 			s := NewService(scope)
-			id, err := s.defaultAMILookup("", "base os-baseos version", "1.11.1")
+			id, err := s.defaultAMILookup(nil, "base os-baseos version", "x86_64", "1.11.1", nil)
 			if err != nil {
 				t.Fatalf("did not expect error calling a mock: %v", err)
 			}
@@ -140,10 +142,355 @@ func TestAMIsWithInvalidCreationDate(t *testing.T) {
 			tc.expect(ec2Mock.EXPECT())
 
 			s := NewService(scope)
-			_, err = s.defaultAMILookup("", "base os-baseos version", "1.11.1")
+			_, err = s.defaultAMILookup(nil, "base os-baseos version", "x86_64", "1.11.1", nil)
 			if err == nil {
 				t.Fatalf("expected an error but did not get one")
 			}
 		})
 	}
 }
+
+func TestBottlerocketAMILookup(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name              string
+		kubernetesVersion string
+		expect            func(m *mock_ssmiface.MockSSMAPIMockRecorder)
+		expectedID        string
+		expectErr         bool
+	}{
+		{
+			name:              "looks up the AMI of the kubernetes minor version",
+			kubernetesVersion: "v1.17.3",
+			expect: func(m *mock_ssmiface.MockSSMAPIMockRecorder) {
+				m.GetParameter(gomock.Eq(&ssm.GetParameterInput{
+					Name: aws.String("/aws/service/bottlerocket/aws-k8s-1.17/arm64/latest/image_id"),
+				})).
+					Return(&ssm.GetParameterOutput{
+						Parameter: &ssm.Parameter{
+							Value: aws.String("ami-bottlerocket"),
+						},
+					}, nil)
+			},
+			expectedID: "ami-bottlerocket",
+		},
+		{
+			name:              "fails with an invalid kubernetes version",
+			kubernetesVersion: "latest",
+			expect:            func(m *mock_ssmiface.MockSSMAPIMockRecorder) {},
+			expectErr:         true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ssmMock := mock_ssmiface.NewMockSSMAPI(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+				AWSClients: scope.AWSClients{
+					SSM: ssmMock,
+				},
+			})
+			if err != nil {
+				t.Fatalf("did not expect err: %v", err)
+			}
+
+			tc.expect(ssmMock.EXPECT())
+
+			s := NewService(scope)
+			id, err := s.bottlerocketAMILookup("arm64", tc.kubernetesVersion)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if id != tc.expectedID {
+				t.Fatalf("returned %q expected %q", id, tc.expectedID)
+			}
+		})
+	}
+}
+
+func TestInstanceTypeArchitecture(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name                 string
+		instanceType         string
+		architectures        []string
+		expectedArchitecture string
+	}{
+		{
+			name:                 "x86_64 instance type",
+			instanceType:         "m5.large",
+			architectures:        []string{"i386", "x86_64"},
+			expectedArchitecture: "x86_64",
+		},
+		{
+			name:                 "graviton instance type",
+			instanceType:         "m6g.large",
+			architectures:        []string{"arm64"},
+			expectedArchitecture: "arm64",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+			})
+			if err != nil {
+				t.Fatalf("did not expect err: %v", err)
+			}
+
+			ec2Mock.EXPECT().
+				DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+					InstanceTypes: []*string{aws.String(tc.instanceType)},
+				})).
+				Return(&ec2.DescribeInstanceTypesOutput{
+					InstanceTypes: []*ec2.InstanceTypeInfo{
+						{
+							ProcessorInfo: &ec2.ProcessorInfo{
+								SupportedArchitectures: aws.StringSlice(tc.architectures),
+							},
+						},
+					},
+				}, nil)
+
+			s := NewService(scope)
+			info, err := s.describeInstanceType(tc.instanceType)
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			architecture, err := instanceTypeArchitecture(info)
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if architecture != tc.expectedArchitecture {
+				t.Fatalf("returned %q expected %q", architecture, tc.expectedArchitecture)
+			}
+		})
+	}
+}
+
+func TestGPUAMILookup(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name         string
+		family       string
+		architecture string
+		parameter    string
+		expectErr    bool
+	}{
+		{
+			name:         "eks accelerated ami of the kubernetes minor version",
+			family:       infrav1.GPUAMIFamilyEKS,
+			architecture: "x86_64",
+			parameter:    "/aws/service/eks/optimized-ami/1.17/amazon-linux-2-gpu/recommended/image_id",
+		},
+		{
+			name:         "fails for unknown families",
+			family:       "ecs",
+			architecture: "x86_64",
+			expectErr:    true,
+		},
+		{
+			name:         "fails for arm64 instance types",
+			family:       infrav1.GPUAMIFamilyEKS,
+			architecture: "arm64",
+			expectErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ssmMock := mock_ssmiface.NewMockSSMAPI(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+				AWSClients: scope.AWSClients{
+					SSM: ssmMock,
+				},
+			})
+			if err != nil {
+				t.Fatalf("did not expect err: %v", err)
+			}
+
+			if tc.parameter != "" {
+				ssmMock.EXPECT().
+					GetParameter(gomock.Eq(&ssm.GetParameterInput{Name: aws.String(tc.parameter)})).
+					Return(&ssm.GetParameterOutput{
+						Parameter: &ssm.Parameter{Value: aws.String("ami-gpu")},
+					}, nil)
+			}
+
+			s := NewService(scope)
+			id, err := s.gpuAMILookup(tc.family, tc.architecture, "v1.17.3")
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if id != "ami-gpu" {
+				t.Fatalf("returned %q expected 'ami-gpu'", id)
+			}
+		})
+	}
+}
+
+func TestSSMParameterAMILookup(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name       string
+		value      *string
+		expectedID string
+		expectErr  bool
+	}{
+		{
+			name:       "returns the AMI ID of the parameter",
+			value:      aws.String("ami-golden"),
+			expectedID: "ami-golden",
+		},
+		{
+			name:      "fails when the parameter is empty",
+			value:     aws.String(""),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ssmMock := mock_ssmiface.NewMockSSMAPI(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+				AWSClients: scope.AWSClients{
+					SSM: ssmMock,
+				},
+			})
+			if err != nil {
+				t.Fatalf("did not expect err: %v", err)
+			}
+
+			ssmMock.EXPECT().
+				GetParameter(gomock.Eq(&ssm.GetParameterInput{Name: aws.String("/cluster-api-provider-aws/golden-images/latest")})).
+				Return(&ssm.GetParameterOutput{
+					Parameter: &ssm.Parameter{Value: tc.value},
+				}, nil)
+
+			s := NewService(scope)
+			id, err := s.ssmParameterAMILookup("/cluster-api-provider-aws/golden-images/latest")
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if id != tc.expectedID {
+				t.Fatalf("returned %q expected %q", id, tc.expectedID)
+			}
+		})
+	}
+}
+
+func TestBastionAMILookup(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name       string
+		region     string
+		expect     func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectedID string
+	}{
+		{
+			name:       "uses the default bastion AMI of the region",
+			region:     "us-east-1",
+			expect:     func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expectedID: "ami-41e0b93b",
+		},
+		{
+			name:   "looks up the Ubuntu AMI published in the AWS China partition",
+			region: "cn-north-1",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeImages(gomock.AssignableToTypeOf(&ec2.DescribeImagesInput{})).
+					DoAndReturn(func(input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
+						if owner := aws.StringValue(input.Filters[0].Values[0]); owner != "837727238323" {
+							t.Fatalf("expected images owned by 837727238323, got %q", owner)
+						}
+						return &ec2.DescribeImagesOutput{
+							Images: []*ec2.Image{
+								{
+									ImageId:      aws.String("ami-old"),
+									CreationDate: aws.String("2019-02-08T17:02:31.000Z"),
+								},
+								{
+									ImageId:      aws.String("ami-new"),
+									CreationDate: aws.String("2020-02-08T17:02:31.000Z"),
+								},
+							},
+						}, nil
+					})
+			},
+			expectedID: "ami-new",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{Region: tc.region},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+			})
+			if err != nil {
+				t.Fatalf("did not expect err: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			id, err := s.bastionAMILookup()
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if id != tc.expectedID {
+				t.Fatalf("returned %q expected %q", id, tc.expectedID)
+			}
+		})
+	}
+}
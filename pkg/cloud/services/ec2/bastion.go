@@ -54,15 +54,18 @@ func (s *Service) ReconcileBastion() error {
 	if len(subnets.FilterPrivate()) == 0 {
 		s.scope.V(2).Info("No private subnets available, skipping bastion host")
 		return nil
-	} else if len(subnets.FilterPublic()) == 0 {
+	} else if len(subnets.FilterPublic()) == 0 && !s.scope.NetworkSpec().FullyPrivate {
 		return errors.New("failed to reconcile bastion host, no public subnets are available")
 	}
 
-	spec := s.getDefaultBastion()
-
 	// Describe bastion instance, if any.
 	instance, err := s.describeBastionInstance()
 	if awserrors.IsNotFound(err) {
+		spec, err := s.getDefaultBastion()
+		if err != nil {
+			return err
+		}
+
 		instance, err = s.runInstance("bastion", spec)
 		if err != nil {
 			record.Warnf(s.scope.AWSCluster, "FailedCreateBastion", "Failed to create bastion instance: %v", err)
@@ -135,20 +138,32 @@ func (s *Service) describeBastionInstance() (*infrav1.Instance, error) {
 	return nil, awserrors.NewNotFound(errors.New("bastion host not found"))
 }
 
-func (s *Service) getDefaultBastion() *infrav1.Instance {
+func (s *Service) getDefaultBastion() (*infrav1.Instance, error) {
 	name := fmt.Sprintf("%s-bastion", s.scope.Name())
 	userData, _ := userdata.NewBastion(&userdata.BastionInput{})
 
-	// If SSHKeyName WAS NOT provided, use the defaultSSHKeyName
+	// If SSHKeyName WAS NOT provided, use the generated key pair or the defaultSSHKeyName
 	keyName := s.scope.AWSCluster.Spec.SSHKeyName
 	if keyName == nil {
-		keyName = aws.String(defaultSSHKeyName)
+		keyName = s.fallbackSSHKeyName()
+	}
+
+	// Fully private clusters have no public subnets, the bastion host is placed in a private subnet
+	// and gets no public IP.
+	subnets := s.scope.Subnets().FilterPublic()
+	if s.scope.NetworkSpec().FullyPrivate {
+		subnets = s.scope.Subnets().FilterPrivate()
+	}
+
+	imageID, err := s.bastionAMILookup()
+	if err != nil {
+		return nil, err
 	}
 
 	i := &infrav1.Instance{
 		Type:       "t2.micro",
-		SubnetID:   s.scope.Subnets().FilterPublic()[0].ID,
-		ImageID:    s.defaultBastionAMILookup(s.scope.AWSCluster.Spec.Region),
+		SubnetID:   subnets[0].ID,
+		ImageID:    imageID,
 		SSHKeyName: keyName,
 		UserData:   aws.String(base64.StdEncoding.EncodeToString([]byte(userData))),
 		SecurityGroupIDs: []string{
@@ -163,5 +178,5 @@ func (s *Service) getDefaultBastion() *infrav1.Instance {
 		}),
 	}
 
-	return i
+	return i, nil
 }
@@ -0,0 +1,299 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// dhcpOptionsRoleTagValue describes the value for the DHCP options set role
+	dhcpOptionsRoleTagValue = "dhcp-options"
+
+	amazonProvidedDNS = "AmazonProvidedDNS"
+
+	// defaultDHCPOptionsID associates the VPC with the default DHCP options set of the account.
+	defaultDHCPOptionsID = "default"
+)
+
+func (s *Service) reconcileDHCPOptions() error {
+	spec := s.scope.VPC().DHCPOptions
+	if spec == nil {
+		// Options sets created for a previous configuration are removed, and the VPC switched back to the
+		// default options set.
+		return s.deleteDHCPOptions()
+	}
+
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping DHCP options reconcile in unmanaged mode")
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling DHCP options")
+
+	desired := s.getDHCPConfigurations(spec)
+
+	existing, err := s.describeDHCPOptions()
+	if err != nil {
+		return err
+	}
+
+	var current *ec2.DhcpOptions
+	for _, opts := range existing {
+		if dhcpOptionsMatch(opts, desired) {
+			current = opts
+			break
+		}
+	}
+
+	if current == nil {
+		current, err = s.createDHCPOptions(desired)
+		if err != nil {
+			return err
+		}
+	}
+
+	associated, err := s.getVPCDHCPOptionsID()
+	if err != nil {
+		return err
+	}
+
+	if associated != aws.StringValue(current.DhcpOptionsId) {
+		if err := s.associateDHCPOptions(aws.StringValue(current.DhcpOptionsId)); err != nil {
+			return err
+		}
+	}
+
+	// Remove the options sets we created for a previous configuration, they are no longer associated with the VPC.
+	for _, opts := range existing {
+		if aws.StringValue(opts.DhcpOptionsId) == aws.StringValue(current.DhcpOptionsId) {
+			continue
+		}
+		if err := s.deleteDHCPOptionsSet(aws.StringValue(opts.DhcpOptionsId)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteDHCPOptions() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping DHCP options deletion in unmanaged mode")
+		return nil
+	}
+
+	existing, err := s.describeDHCPOptions()
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	// An options set can only be deleted once it's no longer associated with the VPC, switch the VPC
+	// back to the default options set if it still exists.
+	if s.scope.VPC().ID != "" {
+		associated, err := s.getVPCDHCPOptionsID()
+		if err != nil && !awserrors.IsNotFound(err) {
+			return err
+		}
+		for _, opts := range existing {
+			if aws.StringValue(opts.DhcpOptionsId) == associated {
+				if err := s.associateDHCPOptions(defaultDHCPOptionsID); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	for _, opts := range existing {
+		if err := s.deleteDHCPOptionsSet(aws.StringValue(opts.DhcpOptionsId)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) createDHCPOptions(configurations []*ec2.NewDhcpConfiguration) (*ec2.DhcpOptions, error) {
+	out, err := s.scope.EC2.CreateDhcpOptions(&ec2.CreateDhcpOptionsInput{
+		DhcpConfigurations: configurations,
+	})
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreateDHCPOptions", "Failed to create DHCP options set for managed VPC %q: %v", s.scope.VPC().ID, err)
+		return nil, errors.Wrapf(err, "failed to create DHCP options set for vpc %q", s.scope.VPC().ID)
+	}
+
+	id := aws.StringValue(out.DhcpOptions.DhcpOptionsId)
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreateDHCPOptions", "Created new DHCP options set %q", id)
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if err := tags.Apply(&tags.ApplyParams{
+			EC2Client:   s.scope.EC2,
+			BuildParams: s.getDHCPOptionsTagParams(id),
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.ResourceNotFound); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedTagDHCPOptions", "Failed to tag managed DHCP options set %q: %v", id, err)
+		return nil, errors.Wrapf(err, "failed to tag DHCP options set %q", id)
+	}
+
+	s.scope.V(2).Info("Created DHCP options set", "dhcp-options-id", id)
+	return out.DhcpOptions, nil
+}
+
+func (s *Service) associateDHCPOptions(id string) error {
+	if _, err := s.scope.EC2.AssociateDhcpOptions(&ec2.AssociateDhcpOptionsInput{
+		DhcpOptionsId: aws.String(id),
+		VpcId:         aws.String(s.scope.VPC().ID),
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedAssociateDHCPOptions", "Failed to associate DHCP options set %q with managed VPC %q: %v", id, s.scope.VPC().ID, err)
+		return errors.Wrapf(err, "failed to associate DHCP options set %q with vpc %q", id, s.scope.VPC().ID)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulAssociateDHCPOptions", "Associated DHCP options set %q with managed VPC %q", id, s.scope.VPC().ID)
+	return nil
+}
+
+func (s *Service) deleteDHCPOptionsSet(id string) error {
+	if _, err := s.scope.EC2.DeleteDhcpOptions(&ec2.DeleteDhcpOptionsInput{
+		DhcpOptionsId: aws.String(id),
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteDHCPOptions", "Failed to delete managed DHCP options set %q: %v", id, err)
+		return errors.Wrapf(err, "failed to delete DHCP options set %q", id)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteDHCPOptions", "Deleted managed DHCP options set %q", id)
+	s.scope.V(2).Info("Deleted DHCP options set", "dhcp-options-id", id)
+	return nil
+}
+
+func (s *Service) describeDHCPOptions() ([]*ec2.DhcpOptions, error) {
+	out, err := s.scope.EC2.DescribeDhcpOptions(&ec2.DescribeDhcpOptionsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ProviderOwned(s.scope.Name()),
+			filter.EC2.ProviderRole(dhcpOptionsRoleTagValue),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe DHCP options sets")
+	}
+
+	return out.DhcpOptions, nil
+}
+
+func (s *Service) getVPCDHCPOptionsID() (string, error) {
+	out, err := s.scope.EC2.DescribeVpcs(&ec2.DescribeVpcsInput{
+		VpcIds: []*string{aws.String(s.scope.VPC().ID)},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe vpc %q", s.scope.VPC().ID)
+	}
+	if len(out.Vpcs) == 0 {
+		return "", awserrors.NewNotFound(errors.Errorf("could not find vpc %q", s.scope.VPC().ID))
+	}
+
+	return aws.StringValue(out.Vpcs[0].DhcpOptionsId), nil
+}
+
+// getDHCPConfigurations returns the DHCP options set configuration for the spec, filling in the AWS defaults
+// so that instances keep resolving names when only some of the options are customized.
+func (s *Service) getDHCPConfigurations(spec *infrav1.DHCPOptions) []*ec2.NewDhcpConfiguration {
+	domainName := defaultDomainName(s.scope.Region())
+	if spec.DomainName != nil {
+		domainName = *spec.DomainName
+	}
+
+	domainNameServers := spec.DomainNameServers
+	if len(domainNameServers) == 0 {
+		domainNameServers = []string{amazonProvidedDNS}
+	}
+
+	configurations := []*ec2.NewDhcpConfiguration{
+		{
+			Key:    aws.String("domain-name"),
+			Values: aws.StringSlice([]string{domainName}),
+		},
+		{
+			Key:    aws.String("domain-name-servers"),
+			Values: aws.StringSlice(domainNameServers),
+		},
+	}
+
+	if len(spec.NTPServers) > 0 {
+		configurations = append(configurations, &ec2.NewDhcpConfiguration{
+			Key:    aws.String("ntp-servers"),
+			Values: aws.StringSlice(spec.NTPServers),
+		})
+	}
+
+	return configurations
+}
+
+func dhcpOptionsMatch(opts *ec2.DhcpOptions, desired []*ec2.NewDhcpConfiguration) bool {
+	current := make(map[string][]string, len(opts.DhcpConfigurations))
+	for _, c := range opts.DhcpConfigurations {
+		values := make([]string, 0, len(c.Values))
+		for _, v := range c.Values {
+			values = append(values, aws.StringValue(v.Value))
+		}
+		current[aws.StringValue(c.Key)] = values
+	}
+
+	expected := make(map[string][]string, len(desired))
+	for _, c := range desired {
+		expected[aws.StringValue(c.Key)] = aws.StringValueSlice(c.Values)
+	}
+
+	return reflect.DeepEqual(current, expected)
+}
+
+// defaultDomainName returns the domain name AWS assigns to instances in the region.
+func defaultDomainName(region string) string {
+	if region == "us-east-1" {
+		return "ec2.internal"
+	}
+	return fmt.Sprintf("%s.compute.internal", region)
+}
+
+func (s *Service) getDHCPOptionsTagParams(id string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-dhcp-options", s.scope.Name())
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(dhcpOptionsRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
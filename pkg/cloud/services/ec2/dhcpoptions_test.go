@@ -0,0 +1,322 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcileDHCPOptions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	desired := []*ec2.DhcpConfiguration{
+		{
+			Key:    aws.String("domain-name"),
+			Values: []*ec2.AttributeValue{{Value: aws.String("corp.example.com")}},
+		},
+		{
+			Key:    aws.String("domain-name-servers"),
+			Values: []*ec2.AttributeValue{{Value: aws.String(amazonProvidedDNS)}},
+		},
+		{
+			Key:    aws.String("ntp-servers"),
+			Values: []*ec2.AttributeValue{{Value: aws.String("10.0.0.10")}},
+		},
+	}
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name: "no options set, creates and associates one",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+					Return(&ec2.DescribeDhcpOptionsOutput{}, nil)
+				m.CreateDhcpOptions(gomock.AssignableToTypeOf(&ec2.CreateDhcpOptionsInput{})).
+					Return(&ec2.CreateDhcpOptionsOutput{
+						DhcpOptions: &ec2.DhcpOptions{
+							DhcpOptionsId:      aws.String("dopt-new"),
+							DhcpConfigurations: desired,
+						},
+					}, nil)
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+				m.DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-dhcp"), DhcpOptionsId: aws.String("dopt-default")}},
+					}, nil)
+				m.AssociateDhcpOptions(gomock.Eq(&ec2.AssociateDhcpOptionsInput{
+					DhcpOptionsId: aws.String("dopt-new"),
+					VpcId:         aws.String("vpc-dhcp"),
+				})).
+					Return(&ec2.AssociateDhcpOptionsOutput{}, nil)
+			},
+		},
+		{
+			name: "outdated options set, replaces it",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+					Return(&ec2.DescribeDhcpOptionsOutput{
+						DhcpOptions: []*ec2.DhcpOptions{
+							{
+								DhcpOptionsId:      aws.String("dopt-old"),
+								DhcpConfigurations: desired[:2],
+							},
+						},
+					}, nil)
+				m.CreateDhcpOptions(gomock.AssignableToTypeOf(&ec2.CreateDhcpOptionsInput{})).
+					Return(&ec2.CreateDhcpOptionsOutput{
+						DhcpOptions: &ec2.DhcpOptions{
+							DhcpOptionsId:      aws.String("dopt-new"),
+							DhcpConfigurations: desired,
+						},
+					}, nil)
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+				m.DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-dhcp"), DhcpOptionsId: aws.String("dopt-old")}},
+					}, nil)
+				m.AssociateDhcpOptions(gomock.AssignableToTypeOf(&ec2.AssociateDhcpOptionsInput{})).
+					Return(&ec2.AssociateDhcpOptionsOutput{}, nil)
+				m.DeleteDhcpOptions(gomock.Eq(&ec2.DeleteDhcpOptionsInput{
+					DhcpOptionsId: aws.String("dopt-old"),
+				})).
+					Return(&ec2.DeleteDhcpOptionsOutput{}, nil)
+			},
+		},
+		{
+			name: "associated options set is up to date",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+					Return(&ec2.DescribeDhcpOptionsOutput{
+						DhcpOptions: []*ec2.DhcpOptions{
+							{
+								DhcpOptionsId:      aws.String("dopt-current"),
+								DhcpConfigurations: desired,
+							},
+						},
+					}, nil)
+				m.DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-dhcp"), DhcpOptionsId: aws.String("dopt-current")}},
+					}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						Region: "us-west-2",
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{
+								ID: "vpc-dhcp",
+								Tags: infrav1.Tags{
+									infrav1.ClusterTagKey("test-cluster"): "owned",
+								},
+								DHCPOptions: &infrav1.DHCPOptions{
+									DomainName: aws.String("corp.example.com"),
+									NTPServers: []string{"10.0.0.10"},
+								},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			if err := s.reconcileDHCPOptions(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteDHCPOptions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	owned := &ec2.DescribeDhcpOptionsOutput{
+		DhcpOptions: []*ec2.DhcpOptions{{DhcpOptionsId: aws.String("dopt-owned")}},
+	}
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name: "no options set, does nothing",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+					Return(&ec2.DescribeDhcpOptionsOutput{}, nil)
+			},
+		},
+		{
+			name: "associated options set, switches the vpc to the default options set and deletes it",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+					Return(owned, nil)
+				m.DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-dhcp"), DhcpOptionsId: aws.String("dopt-owned")}},
+					}, nil)
+				m.AssociateDhcpOptions(gomock.Eq(&ec2.AssociateDhcpOptionsInput{
+					DhcpOptionsId: aws.String("default"),
+					VpcId:         aws.String("vpc-dhcp"),
+				})).
+					Return(&ec2.AssociateDhcpOptionsOutput{}, nil)
+				m.DeleteDhcpOptions(gomock.Eq(&ec2.DeleteDhcpOptionsInput{
+					DhcpOptionsId: aws.String("dopt-owned"),
+				})).
+					Return(&ec2.DeleteDhcpOptionsOutput{}, nil)
+			},
+		},
+		{
+			name: "vpc already deleted, deletes the options set",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+					Return(owned, nil)
+				m.DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+					Return(&ec2.DescribeVpcsOutput{}, nil)
+				m.DeleteDhcpOptions(gomock.Eq(&ec2.DeleteDhcpOptionsInput{
+					DhcpOptionsId: aws.String("dopt-owned"),
+				})).
+					Return(&ec2.DeleteDhcpOptionsOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{
+								ID: "vpc-dhcp",
+								Tags: infrav1.Tags{
+									infrav1.ClusterTagKey("test-cluster"): "owned",
+								},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			if err := s.deleteDHCPOptions(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestReconcileDHCPOptionsRemoved(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					VPC: infrav1.VPCSpec{
+						ID: "vpc-dhcp",
+						Tags: infrav1.Tags{
+							infrav1.ClusterTagKey("test-cluster"): "owned",
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	ec2Mock.EXPECT().DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+		Return(&ec2.DescribeDhcpOptionsOutput{
+			DhcpOptions: []*ec2.DhcpOptions{{DhcpOptionsId: aws.String("dopt-owned")}},
+		}, nil)
+	ec2Mock.EXPECT().DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+		Return(&ec2.DescribeVpcsOutput{
+			Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-dhcp"), DhcpOptionsId: aws.String("dopt-owned")}},
+		}, nil)
+	gomock.InOrder(
+		ec2Mock.EXPECT().AssociateDhcpOptions(gomock.Eq(&ec2.AssociateDhcpOptionsInput{
+			DhcpOptionsId: aws.String("default"),
+			VpcId:         aws.String("vpc-dhcp"),
+		})).
+			Return(&ec2.AssociateDhcpOptionsOutput{}, nil),
+		ec2Mock.EXPECT().DeleteDhcpOptions(gomock.Eq(&ec2.DeleteDhcpOptionsInput{
+			DhcpOptionsId: aws.String("dopt-owned"),
+		})).
+			Return(&ec2.DeleteDhcpOptionsOutput{}, nil),
+	)
+
+	s := NewService(scope)
+	if err := s.reconcileDHCPOptions(); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
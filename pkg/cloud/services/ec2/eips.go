@@ -25,6 +25,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
@@ -47,6 +48,10 @@ func (s *Service) getOrAllocateAddress(role string) (string, error) {
 }
 
 func (s *Service) allocateAddress(role string) (string, error) {
+	return s.allocateNamedAddress(fmt.Sprintf("%s-eip-%s", s.scope.Name(), role), role, s.scope.AdditionalTags())
+}
+
+func (s *Service) allocateNamedAddress(name, role string, additionalTags infrav1.Tags) (string, error) {
 	out, err := s.scope.EC2.AllocateAddress(&ec2.AllocateAddressInput{
 		Domain: aws.String("vpc"),
 	})
@@ -63,9 +68,9 @@ func (s *Service) allocateAddress(role string) (string, error) {
 				ClusterName: s.scope.Name(),
 				ResourceID:  *out.AllocationId,
 				Lifecycle:   infrav1.ResourceLifecycleOwned,
-				Name:        aws.String(fmt.Sprintf("%s-eip-%s", s.scope.Name(), role)),
+				Name:        aws.String(name),
 				Role:        aws.String(role),
-				Additional:  s.scope.AdditionalTags(),
+				Additional:  additionalTags,
 			},
 		}); err != nil {
 			return false, err
@@ -109,6 +114,22 @@ func (s *Service) disassociateAddress(ip *ec2.Address) error {
 	return nil
 }
 
+func (s *Service) releaseAddress(allocationID string) error {
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.scope.EC2.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: aws.String(allocationID)}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.AuthFailure, awserrors.InUseIPAddress); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedReleaseEIP", "Failed to release Elastic IP %q: %v", allocationID, err)
+		return errors.Wrapf(err, "failed to release ElasticIP %q", allocationID)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulReleaseEIP", "Released Elastic IP %q", allocationID)
+	s.scope.Info("released ElasticIP", "allocation-id", allocationID)
+	return nil
+}
+
 func (s *Service) releaseAddresses() error {
 	out, err := s.scope.EC2.DescribeAddresses(&ec2.DescribeAddressesInput{
 		Filters: []*ec2.Filter{filter.EC2.Cluster(s.scope.Name())},
@@ -152,3 +173,117 @@ func (s *Service) releaseAddresses() error {
 	}
 	return nil
 }
+
+// ReconcileElasticIP makes sure an Elastic IP is associated with the instance of the machine. The Elastic IP
+// is taken from the Elastic IP pool of the machine, or allocated for the machine when it has no pool.
+func (s *Service) ReconcileElasticIP(scope *scope.MachineScope, instance *infrav1.Instance) error {
+	out, err := s.scope.EC2.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-id"),
+				Values: aws.StringSlice([]string{instance.ID}),
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe Elastic IPs of instance %q", instance.ID)
+	}
+
+	if len(out.Addresses) > 0 {
+		return nil
+	}
+
+	allocationID, err := s.getOrAllocateMachineAddress(scope)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.scope.EC2.AssociateAddress(&ec2.AssociateAddressInput{
+		AllocationId: aws.String(allocationID),
+		InstanceId:   aws.String(instance.ID),
+	}); err != nil {
+		record.Warnf(scope.AWSMachine, "FailedAssociateEIP", "Failed to associate Elastic IP %q: %v", allocationID, err)
+		return errors.Wrapf(err, "failed to associate Elastic IP %q with instance %q", allocationID, instance.ID)
+	}
+
+	record.Eventf(scope.AWSMachine, "SuccessfulAssociateEIP", "Associated Elastic IP %q with instance %q", allocationID, instance.ID)
+	return nil
+}
+
+// ReleaseElasticIP releases the Elastic IP allocated for the machine. Elastic IPs taken from the
+// Elastic IP pool of the machine are left alone.
+func (s *Service) ReleaseElasticIP(scope *scope.MachineScope) error {
+	if len(scope.AWSMachine.Spec.ElasticIPPool) > 0 {
+		return nil
+	}
+
+	out, err := s.describeMachineAddresses(scope)
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe Elastic IPs of machine %q", scope.Name())
+	}
+
+	for i := range out.Addresses {
+		ip := out.Addresses[i]
+		if ip.AssociationId != nil {
+			if err := s.disassociateAddress(ip); err != nil {
+				return err
+			}
+		}
+
+		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+			if _, err := s.scope.EC2.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: ip.AllocationId}); err != nil {
+				return false, err
+			}
+			return true, nil
+		}, awserrors.AuthFailure, awserrors.InUseIPAddress); err != nil {
+			record.Warnf(scope.AWSMachine, "FailedReleaseEIP", "Failed to release Elastic IP %q: %v", *ip.AllocationId, err)
+			return errors.Wrapf(err, "failed to release ElasticIP %q", *ip.AllocationId)
+		}
+
+		record.Eventf(scope.AWSMachine, "SuccessfulReleaseEIP", "Released Elastic IP %q", *ip.AllocationId)
+	}
+
+	return nil
+}
+
+func (s *Service) getOrAllocateMachineAddress(scope *scope.MachineScope) (string, error) {
+	if pool := scope.AWSMachine.Spec.ElasticIPPool; len(pool) > 0 {
+		out, err := s.scope.EC2.DescribeAddresses(&ec2.DescribeAddressesInput{
+			AllocationIds: aws.StringSlice(pool),
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to describe Elastic IP pool of machine %q", scope.Name())
+		}
+
+		for _, address := range out.Addresses {
+			if address.AssociationId == nil {
+				return aws.StringValue(address.AllocationId), nil
+			}
+		}
+
+		return "", errors.Errorf("no unassociated Elastic IP left in the Elastic IP pool of machine %q", scope.Name())
+	}
+
+	out, err := s.describeMachineAddresses(scope)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe Elastic IPs of machine %q", scope.Name())
+	}
+
+	for _, address := range out.Addresses {
+		if address.AssociationId == nil {
+			return aws.StringValue(address.AllocationId), nil
+		}
+	}
+
+	return s.allocateNamedAddress(scope.Name(), scope.Role(), scope.AdditionalTags())
+}
+
+func (s *Service) describeMachineAddresses(scope *scope.MachineScope) (*ec2.DescribeAddressesOutput, error) {
+	return s.scope.EC2.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.Cluster(s.scope.Name()),
+			filter.EC2.ProviderRole(scope.Role()),
+			filter.EC2.Name(scope.Name()),
+		},
+	})
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileElasticIP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	instanceAddressesInput := &ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-id"),
+				Values: aws.StringSlice([]string{"i-1"}),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		pool      []string
+		expect    func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name: "does nothing when the instance has an elastic IP",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.Eq(instanceAddressesInput)).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{
+								AllocationId:  aws.String("eipalloc-1"),
+								AssociationId: aws.String("eipassoc-1"),
+							},
+						},
+					}, nil)
+			},
+		},
+		{
+			name: "associates an unassociated elastic IP from the pool",
+			pool: []string{"eipalloc-1", "eipalloc-2"},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				gomock.InOrder(
+					m.DescribeAddresses(gomock.Eq(instanceAddressesInput)).
+						Return(&ec2.DescribeAddressesOutput{}, nil),
+					m.DescribeAddresses(gomock.Eq(&ec2.DescribeAddressesInput{
+						AllocationIds: aws.StringSlice([]string{"eipalloc-1", "eipalloc-2"}),
+					})).
+						Return(&ec2.DescribeAddressesOutput{
+							Addresses: []*ec2.Address{
+								{
+									AllocationId:  aws.String("eipalloc-1"),
+									AssociationId: aws.String("eipassoc-1"),
+								},
+								{
+									AllocationId: aws.String("eipalloc-2"),
+								},
+							},
+						}, nil),
+					m.AssociateAddress(gomock.Eq(&ec2.AssociateAddressInput{
+						AllocationId: aws.String("eipalloc-2"),
+						InstanceId:   aws.String("i-1"),
+					})).
+						Return(&ec2.AssociateAddressOutput{}, nil),
+				)
+			},
+		},
+		{
+			name: "fails when all elastic IPs of the pool are associated",
+			pool: []string{"eipalloc-1"},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				gomock.InOrder(
+					m.DescribeAddresses(gomock.Eq(instanceAddressesInput)).
+						Return(&ec2.DescribeAddressesOutput{}, nil),
+					m.DescribeAddresses(gomock.Any()).
+						Return(&ec2.DescribeAddressesOutput{
+							Addresses: []*ec2.Address{
+								{
+									AllocationId:  aws.String("eipalloc-1"),
+									AssociationId: aws.String("eipassoc-1"),
+								},
+							},
+						}, nil),
+				)
+			},
+			expectErr: true,
+		},
+		{
+			name: "allocates and associates an elastic IP for the machine",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				gomock.InOrder(
+					m.DescribeAddresses(gomock.Eq(instanceAddressesInput)).
+						Return(&ec2.DescribeAddressesOutput{}, nil),
+					m.DescribeAddresses(gomock.Any()).
+						Return(&ec2.DescribeAddressesOutput{}, nil),
+					m.AllocateAddress(gomock.Eq(&ec2.AllocateAddressInput{
+						Domain: aws.String("vpc"),
+					})).
+						Return(&ec2.AllocateAddressOutput{
+							AllocationId: aws.String("eipalloc-3"),
+						}, nil),
+					m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+						Return(&ec2.CreateTagsOutput{}, nil),
+					m.AssociateAddress(gomock.Eq(&ec2.AssociateAddressInput{
+						AllocationId: aws.String("eipalloc-3"),
+						InstanceId:   aws.String("i-1"),
+					})).
+						Return(&ec2.AssociateAddressOutput{}, nil),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+			}
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "ingress-0"},
+			}
+			awsCluster := &infrav1.AWSCluster{}
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "ingress-0"},
+				Spec: infrav1.AWSMachineSpec{
+					AssociateElasticIP: true,
+					ElasticIPPool:      tc.pool,
+				},
+			}
+
+			client := fake.NewFakeClient(cluster, machine)
+
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:     client,
+				Cluster:    cluster,
+				Machine:    machine,
+				AWSCluster: awsCluster,
+				AWSMachine: awsMachine,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				Cluster:    cluster,
+				AWSCluster: awsCluster,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			err = s.ReconcileElasticIP(machineScope, &infrav1.Instance{ID: "i-1"})
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
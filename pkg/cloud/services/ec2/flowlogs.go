@@ -0,0 +1,257 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// flowLogRoleTagValue describes the value for the flow log role
+	flowLogRoleTagValue = "flow-log"
+)
+
+func (s *Service) reconcileFlowLogs() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping flow logs reconcile in unmanaged mode")
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling VPC flow logs")
+
+	var input *ec2.CreateFlowLogsInput
+	if spec := s.scope.VPC().FlowLogs; spec != nil {
+		var err error
+		if input, err = s.getFlowLogsInput(spec); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.describeVPCFlowLogs()
+	if err != nil {
+		return err
+	}
+
+	if input == nil {
+		// Flow logs removed from the spec are deleted.
+		return s.deleteFlowLogs(existing)
+	}
+
+	found := false
+	stale := []*ec2.FlowLog{}
+	for _, fl := range existing {
+		if flowLogMatches(fl, input) {
+			s.scope.V(4).Info("Found existing flow log", "flow-log-id", aws.StringValue(fl.FlowLogId))
+			found = true
+			continue
+		}
+		stale = append(stale, fl)
+	}
+
+	// Flow logs can't be modified, a flow log that doesn't match the spec anymore is replaced.
+	if err := s.deleteFlowLogs(stale); err != nil {
+		return err
+	}
+
+	if found {
+		return nil
+	}
+
+	out, err := s.scope.EC2.CreateFlowLogs(input)
+	if err == nil && len(out.Unsuccessful) > 0 {
+		err = errors.New(out.Unsuccessful[0].Error.String())
+	}
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreateFlowLog", "Failed to create flow log for managed VPC %q: %v", s.scope.VPC().ID, err)
+		return errors.Wrapf(err, "failed to create flow log for vpc %q", s.scope.VPC().ID)
+	}
+
+	for _, id := range out.FlowLogIds {
+		flowLogID := aws.StringValue(id)
+		record.Eventf(s.scope.AWSCluster, "SuccessfulCreateFlowLog", "Created new flow log %q for managed VPC %q", flowLogID, s.scope.VPC().ID)
+
+		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+			if err := tags.Apply(&tags.ApplyParams{
+				EC2Client:   s.scope.EC2,
+				BuildParams: s.getFlowLogTagParams(flowLogID),
+			}); err != nil {
+				return false, err
+			}
+			return true, nil
+		}, awserrors.ResourceNotFound); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedTagFlowLog", "Failed to tag managed flow log %q: %v", flowLogID, err)
+			return errors.Wrapf(err, "failed to tag flow log %q", flowLogID)
+		}
+
+		s.scope.V(2).Info("Created flow log", "flow-log-id", flowLogID, "vpc-id", s.scope.VPC().ID)
+	}
+
+	return nil
+}
+
+// deleteFlowLogs deletes the flow logs owned by the cluster, other flow logs are left untouched.
+func (s *Service) deleteFlowLogs(flowLogs []*ec2.FlowLog) error {
+	ids, err := s.ownedFlowLogIDs(flowLogs)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	out, err := s.scope.EC2.DeleteFlowLogs(&ec2.DeleteFlowLogsInput{
+		FlowLogIds: aws.StringSlice(ids),
+	})
+	if err == nil && len(out.Unsuccessful) > 0 {
+		err = errors.New(out.Unsuccessful[0].Error.String())
+	}
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteFlowLog", "Failed to delete flow logs of managed VPC %q: %v", s.scope.VPC().ID, err)
+		return errors.Wrapf(err, "failed to delete flow logs of vpc %q", s.scope.VPC().ID)
+	}
+
+	for _, id := range ids {
+		record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteFlowLog", "Deleted flow log %q of managed VPC %q", id, s.scope.VPC().ID)
+		s.scope.V(2).Info("Deleted flow log", "flow-log-id", id, "vpc-id", s.scope.VPC().ID)
+	}
+
+	return nil
+}
+
+// ownedFlowLogIDs returns the ids of the flow logs owned by the cluster. Flow logs are described without
+// their tags, which are therefore looked up separately.
+func (s *Service) ownedFlowLogIDs(flowLogs []*ec2.FlowLog) ([]string, error) {
+	if len(flowLogs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]*string, 0, len(flowLogs))
+	for _, fl := range flowLogs {
+		ids = append(ids, fl.FlowLogId)
+	}
+
+	out, err := s.scope.EC2.DescribeTags(&ec2.DescribeTagsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("resource-id"),
+				Values: ids,
+			},
+			{
+				Name:   aws.String("key"),
+				Values: aws.StringSlice([]string{infrav1.ClusterTagKey(s.scope.Name())}),
+			},
+			{
+				Name:   aws.String("value"),
+				Values: aws.StringSlice([]string{string(infrav1.ResourceLifecycleOwned)}),
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe tags of flow logs for vpc %q", s.scope.VPC().ID)
+	}
+
+	owned := make([]string, 0, len(out.Tags))
+	for _, tag := range out.Tags {
+		owned = append(owned, aws.StringValue(tag.ResourceId))
+	}
+
+	return owned, nil
+}
+
+func (s *Service) getFlowLogsInput(spec *infrav1.FlowLogsSpec) (*ec2.CreateFlowLogsInput, error) {
+	input := &ec2.CreateFlowLogsInput{
+		ResourceIds:  aws.StringSlice([]string{s.scope.VPC().ID}),
+		ResourceType: aws.String(ec2.FlowLogsResourceTypeVpc),
+		TrafficType:  aws.String(ec2.TrafficTypeAll),
+	}
+
+	if spec.TrafficType != "" {
+		input.TrafficType = aws.String(spec.TrafficType)
+	}
+
+	switch spec.DestinationType {
+	case "", infrav1.FlowLogsDestinationCloudWatchLogs:
+		input.LogDestinationType = aws.String(ec2.LogDestinationTypeCloudWatchLogs)
+		input.LogGroupName = aws.String(spec.LogGroupName)
+		input.DeliverLogsPermissionArn = aws.String(spec.DeliverLogsPermissionARN)
+	case infrav1.FlowLogsDestinationS3:
+		input.LogDestinationType = aws.String(ec2.LogDestinationTypeS3)
+		input.LogDestination = aws.String(spec.S3BucketARN)
+	default:
+		return nil, errors.Errorf("unknown flow logs destination type %q", spec.DestinationType)
+	}
+
+	return input, nil
+}
+
+func flowLogMatches(fl *ec2.FlowLog, input *ec2.CreateFlowLogsInput) bool {
+	if aws.StringValue(fl.LogDestinationType) != aws.StringValue(input.LogDestinationType) ||
+		aws.StringValue(fl.TrafficType) != aws.StringValue(input.TrafficType) {
+		return false
+	}
+
+	if aws.StringValue(input.LogDestinationType) == ec2.LogDestinationTypeS3 {
+		return aws.StringValue(fl.LogDestination) == aws.StringValue(input.LogDestination)
+	}
+
+	return aws.StringValue(fl.LogGroupName) == aws.StringValue(input.LogGroupName) &&
+		aws.StringValue(fl.DeliverLogsPermissionArn) == aws.StringValue(input.DeliverLogsPermissionArn)
+}
+
+func (s *Service) describeVPCFlowLogs() ([]*ec2.FlowLog, error) {
+	input := &ec2.DescribeFlowLogsInput{
+		Filter: []*ec2.Filter{
+			{
+				Name:   aws.String("resource-id"),
+				Values: aws.StringSlice([]string{s.scope.VPC().ID}),
+			},
+		},
+	}
+
+	flowLogs := []*ec2.FlowLog{}
+	if err := s.scope.EC2.DescribeFlowLogsPages(input, func(out *ec2.DescribeFlowLogsOutput, last bool) bool {
+		flowLogs = append(flowLogs, out.FlowLogs...)
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to describe flow logs for vpc %q", s.scope.VPC().ID)
+	}
+
+	return flowLogs, nil
+}
+
+func (s *Service) getFlowLogTagParams(id string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-flow-log", s.scope.Name())
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(flowLogRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcileFlowLogs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	managedTags := infrav1.Tags{
+		infrav1.ClusterTagKey("test-cluster"): "owned",
+	}
+
+	describeFlowLogs := func(m *mock_ec2iface.MockEC2APIMockRecorder, flowLogs ...*ec2.FlowLog) {
+		m.DescribeFlowLogsPages(gomock.Eq(&ec2.DescribeFlowLogsInput{
+			Filter: []*ec2.Filter{
+				{
+					Name:   aws.String("resource-id"),
+					Values: aws.StringSlice([]string{"vpc-flow-logs"}),
+				},
+			},
+		}), gomock.Any()).
+			DoAndReturn(func(_ *ec2.DescribeFlowLogsInput, fn func(*ec2.DescribeFlowLogsOutput, bool) bool) error {
+				fn(&ec2.DescribeFlowLogsOutput{FlowLogs: flowLogs}, true)
+				return nil
+			})
+	}
+
+	describeOwnedFlowLogs := func(m *mock_ec2iface.MockEC2APIMockRecorder, ids []string, owned ...string) {
+		tags := []*ec2.TagDescription{}
+		for _, id := range owned {
+			tags = append(tags, &ec2.TagDescription{
+				Key:        aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+				ResourceId: aws.String(id),
+				Value:      aws.String("owned"),
+			})
+		}
+
+		m.DescribeTags(gomock.Eq(&ec2.DescribeTagsInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("resource-id"),
+					Values: aws.StringSlice(ids),
+				},
+				{
+					Name:   aws.String("key"),
+					Values: aws.StringSlice([]string{"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"}),
+				},
+				{
+					Name:   aws.String("value"),
+					Values: aws.StringSlice([]string{"owned"}),
+				},
+			},
+		})).
+			Return(&ec2.DescribeTagsOutput{Tags: tags}, nil)
+	}
+
+	testCases := []struct {
+		name      string
+		input     infrav1.VPCSpec
+		expect    func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name: "unmanaged vpc",
+			input: infrav1.VPCSpec{
+				ID: "vpc-flow-logs",
+				FlowLogs: &infrav1.FlowLogsSpec{
+					LogGroupName:             "flow-logs",
+					DeliverLogsPermissionARN: "arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/delivery",
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+		{
+			name: "creates a flow log published to cloudwatch logs",
+			input: infrav1.VPCSpec{
+				ID:   "vpc-flow-logs",
+				Tags: managedTags,
+				FlowLogs: &infrav1.FlowLogsSpec{
+					LogGroupName:             "flow-logs",
+					DeliverLogsPermissionARN: "arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/delivery",
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeFlowLogs(m)
+
+				m.CreateFlowLogs(gomock.Eq(&ec2.CreateFlowLogsInput{
+					ResourceIds:              aws.StringSlice([]string{"vpc-flow-logs"}),
+					ResourceType:             aws.String("VPC"),
+					TrafficType:              aws.String("ALL"),
+					LogDestinationType:       aws.String("cloud-watch-logs"),
+					LogGroupName:             aws.String("flow-logs"),
+					DeliverLogsPermissionArn: aws.String("arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/delivery"),
+				})).
+					Return(&ec2.CreateFlowLogsOutput{FlowLogIds: aws.StringSlice([]string{"fl-new"})}, nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+		},
+		{
+			name: "creates a flow log published to s3",
+			input: infrav1.VPCSpec{
+				ID:   "vpc-flow-logs",
+				Tags: managedTags,
+				FlowLogs: &infrav1.FlowLogsSpec{
+					DestinationType: infrav1.FlowLogsDestinationS3,
+					S3BucketARN:     "arn:aws:s3:::flow-logs",
+					TrafficType:     "REJECT",
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeFlowLogs(m)
+
+				m.CreateFlowLogs(gomock.Eq(&ec2.CreateFlowLogsInput{
+					ResourceIds:        aws.StringSlice([]string{"vpc-flow-logs"}),
+					ResourceType:       aws.String("VPC"),
+					TrafficType:        aws.String("REJECT"),
+					LogDestinationType: aws.String("s3"),
+					LogDestination:     aws.String("arn:aws:s3:::flow-logs"),
+				})).
+					Return(&ec2.CreateFlowLogsOutput{FlowLogIds: aws.StringSlice([]string{"fl-new"})}, nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+		},
+		{
+			name: "keeps an existing flow log",
+			input: infrav1.VPCSpec{
+				ID:   "vpc-flow-logs",
+				Tags: managedTags,
+				FlowLogs: &infrav1.FlowLogsSpec{
+					LogGroupName:             "flow-logs",
+					DeliverLogsPermissionARN: "arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/delivery",
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeFlowLogs(m, &ec2.FlowLog{
+					FlowLogId:                aws.String("fl-existing"),
+					LogDestinationType:       aws.String("cloud-watch-logs"),
+					LogGroupName:             aws.String("flow-logs"),
+					DeliverLogsPermissionArn: aws.String("arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/delivery"),
+					TrafficType:              aws.String("ALL"),
+				})
+			},
+		},
+		{
+			name: "replaces an owned flow log delivered with a different role",
+			input: infrav1.VPCSpec{
+				ID:   "vpc-flow-logs",
+				Tags: managedTags,
+				FlowLogs: &infrav1.FlowLogsSpec{
+					LogGroupName:             "flow-logs",
+					DeliverLogsPermissionARN: "arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/delivery",
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeFlowLogs(m, &ec2.FlowLog{
+					FlowLogId:                aws.String("fl-owned"),
+					LogDestinationType:       aws.String("cloud-watch-logs"),
+					LogGroupName:             aws.String("flow-logs"),
+					DeliverLogsPermissionArn: aws.String("arn:aws:iam::123456789012:role/cluster-api-provider-aws/flow-logs/previous"),
+					TrafficType:              aws.String("ALL"),
+				})
+				describeOwnedFlowLogs(m, []string{"fl-owned"}, "fl-owned")
+
+				m.DeleteFlowLogs(gomock.Eq(&ec2.DeleteFlowLogsInput{
+					FlowLogIds: aws.StringSlice([]string{"fl-owned"}),
+				})).
+					Return(&ec2.DeleteFlowLogsOutput{}, nil)
+
+				m.CreateFlowLogs(gomock.AssignableToTypeOf(&ec2.CreateFlowLogsInput{})).
+					Return(&ec2.CreateFlowLogsOutput{FlowLogIds: aws.StringSlice([]string{"fl-new"})}, nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+		},
+		{
+			name: "replaces an owned flow log that doesn't match the spec",
+			input: infrav1.VPCSpec{
+				ID:   "vpc-flow-logs",
+				Tags: managedTags,
+				FlowLogs: &infrav1.FlowLogsSpec{
+					DestinationType: infrav1.FlowLogsDestinationS3,
+					S3BucketARN:     "arn:aws:s3:::flow-logs",
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeFlowLogs(m,
+					&ec2.FlowLog{
+						FlowLogId:          aws.String("fl-owned"),
+						LogDestinationType: aws.String("cloud-watch-logs"),
+						LogGroupName:       aws.String("flow-logs"),
+						TrafficType:        aws.String("ALL"),
+					},
+					&ec2.FlowLog{
+						FlowLogId:          aws.String("fl-other"),
+						LogDestinationType: aws.String("cloud-watch-logs"),
+						LogGroupName:       aws.String("other"),
+						TrafficType:        aws.String("ALL"),
+					},
+				)
+				describeOwnedFlowLogs(m, []string{"fl-owned", "fl-other"}, "fl-owned")
+
+				m.DeleteFlowLogs(gomock.Eq(&ec2.DeleteFlowLogsInput{
+					FlowLogIds: aws.StringSlice([]string{"fl-owned"}),
+				})).
+					Return(&ec2.DeleteFlowLogsOutput{}, nil)
+
+				m.CreateFlowLogs(gomock.AssignableToTypeOf(&ec2.CreateFlowLogsInput{})).
+					Return(&ec2.CreateFlowLogsOutput{FlowLogIds: aws.StringSlice([]string{"fl-new"})}, nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+		},
+		{
+			name: "deletes owned flow logs removed from the spec",
+			input: infrav1.VPCSpec{
+				ID:   "vpc-flow-logs",
+				Tags: managedTags,
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeFlowLogs(m, &ec2.FlowLog{
+					FlowLogId:          aws.String("fl-owned"),
+					LogDestinationType: aws.String("cloud-watch-logs"),
+					LogGroupName:       aws.String("flow-logs"),
+					TrafficType:        aws.String("ALL"),
+				})
+				describeOwnedFlowLogs(m, []string{"fl-owned"}, "fl-owned")
+
+				m.DeleteFlowLogs(gomock.Eq(&ec2.DeleteFlowLogsInput{
+					FlowLogIds: aws.StringSlice([]string{"fl-owned"}),
+				})).
+					Return(&ec2.DeleteFlowLogsOutput{}, nil)
+			},
+		},
+		{
+			name: "fails on an invalid destination type",
+			input: infrav1.VPCSpec{
+				ID:   "vpc-flow-logs",
+				Tags: managedTags,
+				FlowLogs: &infrav1.FlowLogsSpec{
+					DestinationType: infrav1.FlowLogsDestinationType("kinesis"),
+				},
+			},
+			expect:    func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: tc.input,
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			err = s.reconcileFlowLogs()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -37,6 +37,11 @@ func (s *Service) reconcileInternetGateways() error {
 		return nil
 	}
 
+	if s.scope.NetworkSpec().FullyPrivate {
+		s.scope.V(4).Info("Skipping internet gateways reconcile in fully private mode")
+		return nil
+	}
+
 	s.scope.V(2).Info("Reconciling internet gateways")
 
 	igs, err := s.describeVpcInternetGateways()
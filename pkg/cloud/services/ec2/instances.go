@@ -34,6 +34,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -105,10 +106,13 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 	s.scope.V(2).Info("Creating an instance for a machine")
 
 	input := &infrav1.Instance{
-		Type:              scope.AWSMachine.Spec.InstanceType,
-		IAMProfile:        scope.AWSMachine.Spec.IAMInstanceProfile,
-		RootVolume:        scope.AWSMachine.Spec.RootVolume,
-		NetworkInterfaces: scope.AWSMachine.Spec.NetworkInterfaces,
+		Type:                  scope.AWSMachine.Spec.InstanceType,
+		IAMProfile:            scope.AWSMachine.Spec.IAMInstanceProfile,
+		RootVolume:            scope.AWSMachine.Spec.RootVolume,
+		NonRootVolumes:        scope.AWSMachine.Spec.NonRootVolumes,
+		EphemeralVolumes:      scope.AWSMachine.Spec.EphemeralVolumes,
+		NetworkInterfaces:     scope.AWSMachine.Spec.NetworkInterfaces,
+		NetworkInterfaceSpecs: scope.AWSMachine.Spec.NetworkInterfaceSpecs,
 	}
 
 	// Make sure to use the MachineScope here to get the merger of AWSCluster and AWSMachine tags
@@ -124,10 +128,30 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 		Additional:  additionalTags,
 	})
 
+	// Machines without an instance profile use the one managed by the cluster for their role, if any.
+	if input.IAMProfile == "" {
+		role := iam.InstanceProfileRoleNodes
+		if scope.IsControlPlane() {
+			role = iam.InstanceProfileRoleControlPlane
+		}
+		if iam.ManagedInstanceProfileSpec(s.scope.AWSCluster.Spec.IAMInstanceProfiles, role) != nil {
+			name, err := iam.ManagedInstanceProfileName(s.scope.Namespace(), s.scope.Name(), s.scope.Region(), role)
+			if err != nil {
+				return nil, err
+			}
+			input.IAMProfile = name
+		}
+	}
+
 	var err error
 	// Pick image from the machine configuration, or use a default one.
 	if scope.AWSMachine.Spec.AMI.ID != nil {
 		input.ImageID = *scope.AWSMachine.Spec.AMI.ID
+	} else if scope.AWSMachine.Spec.ImageLookupSSMParameter != "" {
+		input.ImageID, err = s.ssmParameterAMILookup(scope.AWSMachine.Spec.ImageLookupSSMParameter)
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		if scope.Machine.Spec.Version == nil {
 			err := errors.New("Either AWSMachine's spec.ami.id or Machine's spec.version must be defined")
@@ -136,20 +160,56 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 			return nil, err
 		}
 
-		imageLookupOrg := scope.AWSMachine.Spec.ImageLookupOrg
-		if imageLookupOrg == "" {
-			imageLookupOrg = scope.AWSCluster.Spec.ImageLookupOrg
-		}
-
-		imageLookupBaseOS := scope.AWSMachine.Spec.ImageLookupBaseOS
-		if imageLookupBaseOS == "" {
-			imageLookupBaseOS = scope.AWSCluster.Spec.ImageLookupBaseOS
+		instanceType, err := s.describeInstanceType(scope.AWSMachine.Spec.InstanceType)
+		if err != nil {
+			return nil, err
 		}
 
-		input.ImageID, err = s.defaultAMILookup(imageLookupOrg, imageLookupBaseOS, *scope.Machine.Spec.Version)
+		architecture, err := instanceTypeArchitecture(instanceType)
 		if err != nil {
 			return nil, err
 		}
+
+		switch {
+		case scope.AWSMachine.Spec.Bottlerocket != nil:
+			input.ImageID, err = s.bottlerocketAMILookup(architecture, *scope.Machine.Spec.Version)
+			if err != nil {
+				return nil, err
+			}
+		case scope.AWSMachine.Spec.GPUAMIFamily != "" && instanceTypeHasGPUs(instanceType):
+			input.ImageID, err = s.gpuAMILookup(scope.AWSMachine.Spec.GPUAMIFamily, architecture, *scope.Machine.Spec.Version)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			imageLookupOrg := scope.AWSMachine.Spec.ImageLookupOrg
+			if imageLookupOrg == "" {
+				imageLookupOrg = scope.AWSCluster.Spec.ImageLookupOrg
+			}
+
+			imageLookupBaseOS := scope.AWSMachine.Spec.ImageLookupBaseOS
+			if imageLookupBaseOS == "" {
+				imageLookupBaseOS = scope.AWSCluster.Spec.ImageLookupBaseOS
+			}
+
+			imageLookupOwners := scope.AWSMachine.Spec.ImageLookupOwners
+			if len(imageLookupOwners) == 0 {
+				imageLookupOwners = scope.AWSCluster.Spec.ImageLookupOwners
+			}
+			if imageLookupOrg != "" {
+				imageLookupOwners = append([]string{imageLookupOrg}, imageLookupOwners...)
+			}
+
+			imageLookupFilters := scope.AWSMachine.Spec.ImageLookupFilters
+			if len(imageLookupFilters) == 0 {
+				imageLookupFilters = scope.AWSCluster.Spec.ImageLookupFilters
+			}
+
+			input.ImageID, err = s.defaultAMILookup(imageLookupOwners, imageLookupBaseOS, architecture, *scope.Machine.Spec.Version, imageLookupFilters)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Prefer AWSMachine.Spec.FailureDomain for now while migrating to the use of
@@ -159,6 +219,9 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 		failureDomain = scope.Machine.Spec.FailureDomain
 	}
 
+	// Machines placed on an Outpost only pick subnets on that Outpost, other machines only pick subnets in the region.
+	outpostARN := aws.StringValue(scope.AWSMachine.Spec.OutpostARN)
+
 	// Pick subnet from the machine configuration, or based on the availability zone specified,
 	// or default to the first private subnet available.
 	// TODO(vincepri): Move subnet picking logic to its own function/method.
@@ -167,7 +230,7 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 		input.SubnetID = *scope.AWSMachine.Spec.Subnet.ID
 
 	case failureDomain != nil:
-		subnets := s.scope.Subnets().FilterPrivate().FilterByZone(*failureDomain)
+		subnets := s.scope.Subnets().FilterPrivate().FilterByOutpost(outpostARN).FilterByZone(*failureDomain)
 		if len(subnets) == 0 {
 			record.Warnf(scope.AWSMachine, "FailedCreate",
 				"Failed to create instance: no subnets available in availability zone %q", *failureDomain)
@@ -185,8 +248,13 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 		// with control plane machines.
 
 	case input.SubnetID == "":
-		sns := s.scope.Subnets().FilterPrivate()
+		sns := s.scope.Subnets().FilterPrivate().FilterByOutpost(outpostARN)
 		if len(sns) == 0 {
+			if outpostARN != "" {
+				return nil, awserrors.NewFailedDependency(
+					errors.Errorf("failed to run machine %q, no subnets available on outpost %q", scope.Name(), outpostARN),
+				)
+			}
 			return nil, awserrors.NewFailedDependency(
 				errors.Errorf("failed to run machine %q, no subnets available", scope.Name()),
 			)
@@ -199,6 +267,27 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 			errors.New("failed to run controlplane, APIServer ELB not available"),
 		)
 	}
+
+	if pg := scope.AWSMachine.Spec.PlacementGroup; pg != nil {
+		if err := s.reconcilePlacementGroup(pg); err != nil {
+			return nil, err
+		}
+		input.PlacementGroupName = pg.Name
+		input.PartitionNumber = pg.PartitionNumber
+	}
+
+	input.CPUOptions = scope.AWSMachine.Spec.CPUOptions
+	input.InstanceMetadataOptions = scope.InstanceMetadataOptions()
+	input.UnlimitedCPUCredits = scope.AWSMachine.Spec.UnlimitedCPUCredits
+	input.CapacityReservationID = scope.AWSMachine.Spec.CapacityReservationID
+	input.CapacityReservationPreference = scope.AWSMachine.Spec.CapacityReservationPreference
+
+	if p := scope.AWSMachine.Spec.Placement; p != nil {
+		input.HostID = p.HostID
+		input.HostResourceGroupARN = p.HostResourceGroupARN
+		input.HostAffinity = p.Affinity
+		input.Tenancy = p.Tenancy
+	}
 	if !scope.UserDataIsUncompressed() {
 		userData, err = userdata.GzipBytes(userData)
 		if err != nil {
@@ -216,13 +305,14 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 	input.SecurityGroupIDs = append(input.SecurityGroupIDs, ids...)
 
 	// If SSHKeyName WAS NOT provided in the AWSMachine Spec, fallback to the value provided in the AWSCluster Spec.
-	// If a value was not provided in the AWSCluster Spec, then use the defaultSSHKeyName
+	// If a value was not provided in the AWSCluster Spec, then use the generated key pair or the defaultSSHKeyName.
+	// An empty name launches the instance without an SSH key.
 	input.SSHKeyName = scope.AWSMachine.Spec.SSHKeyName
 	if input.SSHKeyName == nil {
 		if scope.AWSCluster.Spec.SSHKeyName != nil {
 			input.SSHKeyName = scope.AWSCluster.Spec.SSHKeyName
 		} else {
-			input.SSHKeyName = aws.String(defaultSSHKeyName)
+			input.SSHKeyName = s.fallbackSSHKeyName()
 		}
 	}
 
@@ -278,6 +368,35 @@ func (s *Service) GetCoreSecurityGroups(scope *scope.MachineScope) ([]string, er
 	return ids, nil
 }
 
+// GetAdditionalSecurityGroupsIDs returns the IDs of the additional security groups of the machine.
+// Security groups referenced by filters are looked up in the VPC of the cluster, so machine templates
+// can refer to them by tags across accounts and regions.
+func (s *Service) GetAdditionalSecurityGroupsIDs(scope *scope.MachineScope) ([]string, error) {
+	var ids []string
+	for _, sg := range scope.AWSMachine.Spec.AdditionalSecurityGroups {
+		switch {
+		case sg.ID != nil:
+			ids = append(ids, *sg.ID)
+		case len(sg.Filters) > 0:
+			out, err := s.scope.EC2.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+				Filters: append(converters.FiltersToSDK(sg.Filters), filter.EC2.VPC(s.scope.VPC().ID)),
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to describe additional security groups")
+			}
+
+			if len(out.SecurityGroups) == 0 {
+				return nil, errors.Errorf("failed to find additional security groups matching filters %v", sg.Filters)
+			}
+
+			for _, group := range out.SecurityGroups {
+				ids = append(ids, aws.StringValue(group.GroupId))
+			}
+		}
+	}
+	return ids, nil
+}
+
 // TerminateInstance terminates an EC2 instance.
 // Returns nil on success, error in all other cases.
 func (s *Service) TerminateInstance(instanceID string) error {
@@ -319,13 +438,16 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 	input := &ec2.RunInstancesInput{
 		InstanceType: aws.String(i.Type),
 		ImageId:      aws.String(i.ImageID),
-		KeyName:      i.SSHKeyName,
 		EbsOptimized: i.EBSOptimized,
 		MaxCount:     aws.Int64(1),
 		MinCount:     aws.Int64(1),
 		UserData:     i.UserData,
 	}
 
+	if aws.StringValue(i.SSHKeyName) != "" {
+		input.KeyName = i.SSHKeyName
+	}
+
 	s.scope.V(2).Info("userData size", "bytes", len(*i.UserData), "role", role)
 
 	if len(i.NetworkInterfaces) > 0 {
@@ -339,6 +461,8 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 		}
 
 		input.NetworkInterfaces = netInterfaces
+	} else if len(i.NetworkInterfaceSpecs) > 0 {
+		input.NetworkInterfaces = getInstanceNetworkInterfaceSpecs(i)
 	} else {
 		input.SubnetId = aws.String(i.SubnetID)
 
@@ -347,6 +471,40 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 		}
 	}
 
+	input.Placement = getInstancePlacement(i)
+
+	if i.CPUOptions != nil {
+		input.CpuOptions = &ec2.CpuOptionsRequest{
+			CoreCount:      aws.Int64(i.CPUOptions.CoreCount),
+			ThreadsPerCore: aws.Int64(i.CPUOptions.ThreadsPerCore),
+		}
+	}
+
+	if i.UnlimitedCPUCredits != nil {
+		cpuCredits := "standard"
+		if *i.UnlimitedCPUCredits {
+			cpuCredits = "unlimited"
+		}
+		input.CreditSpecification = &ec2.CreditSpecificationRequest{
+			CpuCredits: aws.String(cpuCredits),
+		}
+	}
+
+	input.CapacityReservationSpecification = getInstanceCapacityReservationSpecification(i)
+
+	if o := i.InstanceMetadataOptions; o != nil {
+		input.MetadataOptions = &ec2.InstanceMetadataOptionsRequest{}
+		if o.HTTPEndpoint != "" {
+			input.MetadataOptions.HttpEndpoint = aws.String(o.HTTPEndpoint)
+		}
+		if o.HTTPTokens != "" {
+			input.MetadataOptions.HttpTokens = aws.String(o.HTTPTokens)
+		}
+		if o.HTTPPutResponseHopLimit != 0 {
+			input.MetadataOptions.HttpPutResponseHopLimit = aws.Int64(o.HTTPPutResponseHopLimit)
+		}
+	}
+
 	if i.IAMProfile != "" {
 		input.IamInstanceProfile = &ec2.IamInstanceProfileSpecification{
 			Name: aws.String(i.IAMProfile),
@@ -368,43 +526,43 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 			return nil, errors.Errorf("root volume size (%d) must be greater than or equal to snapshot size (%d)", i.RootVolume.Size, *snapshotSize)
 		}
 
-		ebsRootDevice := &ec2.EbsBlockDevice{
-			DeleteOnTermination: aws.Bool(true),
-			VolumeSize:          aws.Int64(i.RootVolume.Size),
-			Encrypted:           aws.Bool(i.RootVolume.Encrypted),
-		}
-
-		if i.RootVolume.IOPS != 0 {
-			ebsRootDevice.Iops = aws.Int64(i.RootVolume.IOPS)
-		}
-
-		if i.RootVolume.EncryptionKey != "" {
-			ebsRootDevice.Encrypted = aws.Bool(true)
-			ebsRootDevice.KmsKeyId = aws.String(i.RootVolume.EncryptionKey)
-		}
-
-		if i.RootVolume.Type != "" {
-			ebsRootDevice.VolumeType = aws.String(i.RootVolume.Type)
-		}
-
 		input.BlockDeviceMappings = []*ec2.BlockDeviceMapping{
 			{
 				DeviceName: rootDeviceName,
-				Ebs:        ebsRootDevice,
+				Ebs:        getEBSBlockDevice(i.RootVolume),
 			},
 		}
 	}
 
+	for idx := range i.NonRootVolumes {
+		volume := &i.NonRootVolumes[idx]
+		input.BlockDeviceMappings = append(input.BlockDeviceMappings, &ec2.BlockDeviceMapping{
+			DeviceName: aws.String(volume.DeviceName),
+			Ebs:        getEBSBlockDevice(volume),
+		})
+	}
+
+	for _, volume := range i.EphemeralVolumes {
+		input.BlockDeviceMappings = append(input.BlockDeviceMappings, &ec2.BlockDeviceMapping{
+			DeviceName:  aws.String(volume.DeviceName),
+			VirtualName: aws.String(volume.VirtualName),
+		})
+	}
+
 	if len(i.Tags) > 0 {
-		spec := &ec2.TagSpecification{ResourceType: aws.String(ec2.ResourceTypeInstance)}
-		for key, value := range i.Tags {
-			spec.Tags = append(spec.Tags, &ec2.Tag{
-				Key:   aws.String(key),
-				Value: aws.String(value),
-			})
+		// Volumes and network interfaces created with the instance carry its tags as well,
+		// existing network interfaces are tagged by the machine controller.
+		resourceTypes := []string{ec2.ResourceTypeInstance, ec2.ResourceTypeVolume}
+		if len(i.NetworkInterfaces) == 0 {
+			resourceTypes = append(resourceTypes, ec2.ResourceTypeNetworkInterface)
 		}
 
-		input.TagSpecifications = append(input.TagSpecifications, spec)
+		for _, resourceType := range resourceTypes {
+			input.TagSpecifications = append(input.TagSpecifications, &ec2.TagSpecification{
+				ResourceType: aws.String(resourceType),
+				Tags:         converters.MapToTags(i.Tags),
+			})
+		}
 	}
 
 	out, err := s.scope.EC2.RunInstances(input)
@@ -529,6 +687,60 @@ func (s *Service) UpdateResourceTags(resourceID *string, create map[string]strin
 	return nil
 }
 
+// UpdateInstanceResourceTags updates the tags of an instance and of the volumes and network interfaces attached to it.
+// Tags in create are added to the resources missing them or carrying a different value, tags in remove are
+// deleted from the resources carrying them.
+func (s *Service) UpdateInstanceResourceTags(instance *infrav1.Instance, create map[string]string, remove map[string]string) error {
+	instanceID := instance.ID
+	resources := map[string]infrav1.Tags{
+		instanceID: instance.Tags,
+	}
+
+	volumes, err := s.scope.EC2.DescribeVolumes(&ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("attachment.instance-id"),
+				Values: []*string{aws.String(instanceID)},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get volumes for instance %q", instanceID)
+	}
+	for _, v := range volumes.Volumes {
+		resources[aws.StringValue(v.VolumeId)] = converters.TagsToMap(v.Tags)
+	}
+
+	enis, err := s.getInstanceENIs(instanceID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get ENIs for instance %q", instanceID)
+	}
+	for _, eni := range enis {
+		resources[aws.StringValue(eni.NetworkInterfaceId)] = converters.TagsToMap(eni.TagSet)
+	}
+
+	for id, tags := range resources {
+		created := infrav1.Tags(create).Difference(tags)
+
+		deleted := map[string]string{}
+		for key, value := range remove {
+			if _, ok := tags[key]; ok {
+				deleted[key] = value
+			}
+		}
+
+		if len(created) == 0 && len(deleted) == 0 {
+			continue
+		}
+
+		if err := s.UpdateResourceTags(aws.String(id), created, deleted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *Service) getInstanceENIs(instanceID string) ([]*ec2.NetworkInterface, error) {
 	input := &ec2.DescribeNetworkInterfacesInput{
 		Filters: []*ec2.Filter{
@@ -605,7 +817,37 @@ func (s *Service) SDKToInstance(v *ec2.Instance) (*infrav1.Instance, error) {
 	if v.IamInstanceProfile != nil && v.IamInstanceProfile.Arn != nil {
 		split := strings.Split(aws.StringValue(v.IamInstanceProfile.Arn), "instance-profile/")
 		if len(split) > 1 && split[1] != "" {
-			i.IAMProfile = split[1]
+			// The ARN of instance profiles created with a path includes it before the name.
+			i.IAMProfile = split[1][strings.LastIndex(split[1], "/")+1:]
+		}
+	}
+
+	if v.Placement != nil {
+		i.PlacementGroupName = aws.StringValue(v.Placement.GroupName)
+		i.PartitionNumber = v.Placement.PartitionNumber
+		i.HostID = v.Placement.HostId
+		i.HostResourceGroupARN = v.Placement.HostResourceGroupArn
+		i.HostAffinity = v.Placement.Affinity
+		i.Tenancy = v.Placement.Tenancy
+	}
+
+	if v.CpuOptions != nil {
+		i.CPUOptions = &infrav1.CPUOptions{
+			CoreCount:      aws.Int64Value(v.CpuOptions.CoreCount),
+			ThreadsPerCore: aws.Int64Value(v.CpuOptions.ThreadsPerCore),
+		}
+	}
+
+	i.CapacityReservationID = v.CapacityReservationId
+	if v.CapacityReservationSpecification != nil {
+		i.CapacityReservationPreference = aws.StringValue(v.CapacityReservationSpecification.CapacityReservationPreference)
+	}
+
+	if v.MetadataOptions != nil {
+		i.InstanceMetadataOptions = &infrav1.InstanceMetadataOptions{
+			HTTPEndpoint:            aws.StringValue(v.MetadataOptions.HttpEndpoint),
+			HTTPTokens:              aws.StringValue(v.MetadataOptions.HttpTokens),
+			HTTPPutResponseHopLimit: aws.Int64Value(v.MetadataOptions.HttpPutResponseHopLimit),
 		}
 	}
 
@@ -622,6 +864,110 @@ func (s *Service) SDKToInstance(v *ec2.Instance) (*infrav1.Instance, error) {
 	return i, nil
 }
 
+// getEBSBlockDevice returns the EBS block device for a volume, deleted when the instance terminates.
+func getEBSBlockDevice(v *infrav1.Volume) *ec2.EbsBlockDevice {
+	ebsDevice := &ec2.EbsBlockDevice{
+		DeleteOnTermination: aws.Bool(true),
+		VolumeSize:          aws.Int64(v.Size),
+		Encrypted:           aws.Bool(v.Encrypted),
+	}
+
+	if v.IOPS != 0 {
+		ebsDevice.Iops = aws.Int64(v.IOPS)
+	}
+
+	if v.EncryptionKey != "" {
+		ebsDevice.Encrypted = aws.Bool(true)
+		ebsDevice.KmsKeyId = aws.String(v.EncryptionKey)
+	}
+
+	if v.Type != "" {
+		ebsDevice.VolumeType = aws.String(v.Type)
+	}
+
+	return ebsDevice
+}
+
+// getInstanceNetworkInterfaceSpecs returns the network interfaces to create when launching the instance.
+// Network interfaces without a subnet or security groups use those of the instance, since EC2 doesn't
+// accept them on the instance when network interfaces are specified.
+func getInstanceNetworkInterfaceSpecs(i *infrav1.Instance) []*ec2.InstanceNetworkInterfaceSpecification {
+	netInterfaces := make([]*ec2.InstanceNetworkInterfaceSpecification, 0, len(i.NetworkInterfaceSpecs))
+
+	for _, spec := range i.NetworkInterfaceSpecs {
+		netInterface := &ec2.InstanceNetworkInterfaceSpecification{
+			DeviceIndex:         aws.Int64(spec.DeviceIndex),
+			SubnetId:            aws.String(i.SubnetID),
+			DeleteOnTermination: aws.Bool(true),
+		}
+
+		if spec.SubnetID != nil {
+			netInterface.SubnetId = spec.SubnetID
+		}
+
+		if len(spec.SecurityGroupIDs) > 0 {
+			netInterface.Groups = aws.StringSlice(spec.SecurityGroupIDs)
+		} else if len(i.SecurityGroupIDs) > 0 {
+			netInterface.Groups = aws.StringSlice(i.SecurityGroupIDs)
+		}
+
+		if spec.Description != "" {
+			netInterface.Description = aws.String(spec.Description)
+		}
+
+		if spec.InterfaceType != "" {
+			netInterface.InterfaceType = aws.String(spec.InterfaceType)
+		}
+
+		netInterfaces = append(netInterfaces, netInterface)
+	}
+
+	return netInterfaces
+}
+
+// getInstanceCapacityReservationSpecification returns the Capacity Reservation the instance is launched in,
+// or its preference for open Capacity Reservations. It returns nil when neither is set.
+func getInstanceCapacityReservationSpecification(i *infrav1.Instance) *ec2.CapacityReservationSpecification {
+	switch {
+	case i.CapacityReservationID != nil:
+		return &ec2.CapacityReservationSpecification{
+			CapacityReservationTarget: &ec2.CapacityReservationTarget{
+				CapacityReservationId: i.CapacityReservationID,
+			},
+		}
+	case i.CapacityReservationPreference != "":
+		return &ec2.CapacityReservationSpecification{
+			CapacityReservationPreference: aws.String(i.CapacityReservationPreference),
+		}
+	default:
+		return nil
+	}
+}
+
+// getInstancePlacement returns the placement of the instance, or nil if the instance doesn't need one.
+func getInstancePlacement(i *infrav1.Instance) *ec2.Placement {
+	if i.PlacementGroupName == "" && i.HostID == nil && i.HostResourceGroupARN == nil && i.Tenancy == nil {
+		return nil
+	}
+
+	placement := &ec2.Placement{
+		PartitionNumber:      i.PartitionNumber,
+		HostId:               i.HostID,
+		HostResourceGroupArn: i.HostResourceGroupARN,
+		Affinity:             i.HostAffinity,
+		Tenancy:              i.Tenancy,
+	}
+	if i.PlacementGroupName != "" {
+		placement.GroupName = aws.String(i.PlacementGroupName)
+	}
+	// Instances can only be launched on Dedicated Hosts with the host tenancy.
+	if placement.Tenancy == nil && (i.HostID != nil || i.HostResourceGroupARN != nil) {
+		placement.Tenancy = aws.String(ec2.TenancyHost)
+	}
+
+	return placement
+}
+
 func (s *Service) getInstanceAddresses(instance *ec2.Instance) []corev1.NodeAddress {
 	addresses := []corev1.NodeAddress{}
 	for _, eni := range instance.NetworkInterfaces {
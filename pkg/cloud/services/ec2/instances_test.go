@@ -17,6 +17,7 @@ limitations under the License.
 package ec2
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -544,6 +545,19 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+						InstanceTypes: []*string{aws.String("m5.large")},
+					})).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
 				// verify that the ImageLookupOrg is used when finding AMIs
 				m.
 					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
@@ -667,6 +681,19 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+						InstanceTypes: []*string{aws.String("m5.large")},
+					})).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
 				// verify that the ImageLookupOrg is used when finding AMIs
 				m.
 					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
@@ -791,6 +818,19 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+						InstanceTypes: []*string{aws.String("m5.large")},
+					})).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
 				// verify that the ImageLookupOrg is used when finding AMIs
 				m.
 					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
@@ -863,83 +903,892 @@ func TestCreateInstance(t *testing.T) {
 				}
 			},
 		},
-	}
-
-	for _, tc := range testcases {
-		t.Run(tc.name, func(t *testing.T) {
-			mockCtrl := gomock.NewController(t)
-			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
-			elbMock := mock_elbiface.NewMockELBAPI(mockCtrl)
-
-			cluster := &clusterv1.Cluster{
+		{
+			name: "with ImageLookupOwners and ImageLookupFilters",
+			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "test1",
+					Labels: map[string]string{"set": "node"},
 				},
-				Spec: clusterv1.ClusterSpec{
-					ClusterNetwork: &clusterv1.ClusterNetwork{
-						ServiceDomain: "cluster.local",
-						Services: &clusterv1.NetworkRanges{
-							CIDRBlocks: []string{"192.168.0.0/16"},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+					Version: pointer.StringPtr("v1.16.1"),
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				InstanceType:   "m5.large",
+				ImageLookupOrg: "machine-level-image-lookup-org",
+				ImageLookupFilters: []infrav1.Filter{
+					{Name: "tag:approved", Values: []string{"true"}},
+				},
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							&infrav1.SubnetSpec{
+								IsPublic: false,
+							},
 						},
-						Pods: &clusterv1.NetworkRanges{
-							CIDRBlocks: []string{"192.168.0.0/16"},
+					},
+					ImageLookupOrg:    "cluster-level-image-lookup-org",
+					ImageLookupOwners: []string{"123456789012"},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
 						},
 					},
 				},
-			}
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+						InstanceTypes: []*string{aws.String("m5.large")},
+					})).
+					Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{
+							{
+								ProcessorInfo: &ec2.ProcessorInfo{
+									SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+								},
+							},
+						},
+					}, nil)
+				// verify that the owners and filters are used when finding AMIs
+				m.
+					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+						Filters: []*ec2.Filter{
+							{
+								Name:   aws.String("owner-id"),
+								Values: aws.StringSlice([]string{"machine-level-image-lookup-org", "123456789012"}),
+							},
+							{
+								Name:   aws.String("name"),
+								Values: []*string{aws.String(amiName("ubuntu-18.04", "v1.16.1"))},
+							},
+							{
+								Name:   aws.String("architecture"),
+								Values: []*string{aws.String("x86_64")},
+							},
+							{
+								Name:   aws.String("state"),
+								Values: []*string{aws.String("available")},
+							},
+							{
+								Name:   aws.String("virtualization-type"),
+								Values: []*string{aws.String("hvm")},
+							},
+							{
+								Name:   aws.String("tag:approved"),
+								Values: []*string{aws.String("true")},
+							},
+						},
+					})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								CreationDate: aws.String("2006-01-02T15:04:05.000Z"),
+							},
+						},
+					}, nil)
+				m. // TODO: Restore these parameters, but with the tags as well
+					RunInstances(gomock.Any()).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								IamInstanceProfile: &ec2.IamInstanceProfile{
+									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
 
-			awsCluster := tc.awsCluster
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
 
-			machine := &tc.machine
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
 
-			awsMachine := &infrav1.AWSMachine{
+		{
+			name: "with cpu options",
+			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "aws-test1",
-					OwnerReferences: []metav1.OwnerReference{
-						{
-							APIVersion: clusterv1.GroupVersion.String(),
-							Kind:       "Machine",
-							Name:       "test1",
-						},
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
 					},
 				},
-			}
-
-			client := fake.NewFakeClient(secret, cluster, machine)
-
-			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
-				Client: client,
-				AWSClients: scope.AWSClients{
-					EC2: ec2Mock,
-					ELB: elbMock,
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
 				},
-				Cluster:    cluster,
-				Machine:    machine,
-				AWSMachine: awsMachine,
-				AWSCluster: awsCluster,
-			})
-			if err != nil {
-				t.Fatalf("Failed to create test context: %v", err)
-			}
-			machineScope.AWSMachine.Spec = *tc.machineConfig
-			tc.expect(ec2Mock.EXPECT())
-
-			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
-				Client: client,
-				AWSClients: scope.AWSClients{
-					EC2: ec2Mock,
-					ELB: elbMock,
+				InstanceType: "m5.large",
+				CPUOptions: &infrav1.CPUOptions{
+					CoreCount:      2,
+					ThreadsPerCore: 1,
 				},
-				Cluster:    cluster,
-				AWSCluster: tc.awsCluster,
-			})
-			if err != nil {
-				t.Fatalf("Failed to create test context: %v", err)
-			}
-
-			s := NewService(clusterScope)
-			instance, err := s.CreateInstance(machineScope, []byte("userData"))
-			tc.check(instance, err)
-		})
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							&infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name: aws.String("ami-1"),
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+						expected := &ec2.CpuOptionsRequest{
+							CoreCount:      aws.Int64(2),
+							ThreadsPerCore: aws.Int64(1),
+						}
+						if !reflect.DeepEqual(expected, input.CpuOptions) {
+							t.Fatalf("expected cpu options %v, got %v", expected, input.CpuOptions)
+						}
+						return &ec2.Reservation{
+							Instances: []*ec2.Instance{
+								{
+									State: &ec2.InstanceState{
+										Name: aws.String(ec2.InstanceStateNamePending),
+									},
+									IamInstanceProfile: &ec2.IamInstanceProfile{
+										Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+									},
+									InstanceId:     aws.String("two"),
+									InstanceType:   aws.String("m5.large"),
+									SubnetId:       aws.String("subnet-1"),
+									ImageId:        aws.String("ami-1"),
+									RootDeviceName: aws.String("device-1"),
+									BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+										{
+											DeviceName: aws.String("device-1"),
+											Ebs: &ec2.EbsInstanceBlockDevice{
+												VolumeId: aws.String("volume-1"),
+											},
+										},
+									},
+									CpuOptions: &ec2.CpuOptions{
+										CoreCount:      aws.Int64(2),
+										ThreadsPerCore: aws.Int64(1),
+									},
+								},
+							},
+						}, nil
+					})
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if instance.CPUOptions == nil || instance.CPUOptions.ThreadsPerCore != 1 {
+					t.Errorf("expected instance with one thread per core, got %v", instance.CPUOptions)
+				}
+			},
+		},
+		{
+			name: "with non-root volumes",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				NonRootVolumes: []infrav1.Volume{
+					{
+						DeviceName:    "/dev/sdb",
+						Size:          100,
+						Type:          "io1",
+						IOPS:          1000,
+						EncryptionKey: "alias/etcd",
+					},
+				},
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							&infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name: aws.String("ami-1"),
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+						expected := []*ec2.BlockDeviceMapping{
+							{
+								DeviceName: aws.String("/dev/sdb"),
+								Ebs: &ec2.EbsBlockDevice{
+									DeleteOnTermination: aws.Bool(true),
+									VolumeSize:          aws.Int64(100),
+									VolumeType:          aws.String("io1"),
+									Iops:                aws.Int64(1000),
+									Encrypted:           aws.Bool(true),
+									KmsKeyId:            aws.String("alias/etcd"),
+								},
+							},
+						}
+						if !reflect.DeepEqual(expected, input.BlockDeviceMappings) {
+							t.Fatalf("expected block device mappings %v, got %v", expected, input.BlockDeviceMappings)
+						}
+						return &ec2.Reservation{
+							Instances: []*ec2.Instance{
+								{
+									State: &ec2.InstanceState{
+										Name: aws.String(ec2.InstanceStateNamePending),
+									},
+									IamInstanceProfile: &ec2.IamInstanceProfile{
+										Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+									},
+									InstanceId:     aws.String("two"),
+									InstanceType:   aws.String("m5.large"),
+									SubnetId:       aws.String("subnet-1"),
+									ImageId:        aws.String("ami-1"),
+									RootDeviceName: aws.String("device-1"),
+									BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+										{
+											DeviceName: aws.String("device-1"),
+											Ebs: &ec2.EbsInstanceBlockDevice{
+												VolumeId: aws.String("volume-1"),
+											},
+										},
+									},
+								},
+							},
+						}, nil
+					})
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "with instance metadata options",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AWSResourceReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				InstanceMetadataOptions: &infrav1.InstanceMetadataOptions{
+					HTTPTokens:              "required",
+					HTTPPutResponseHopLimit: 2,
+				},
+			},
+			awsCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							&infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name: aws.String("ami-1"),
+							},
+						},
+					}, nil)
+				m.
+					RunInstances(gomock.Any()).
+					DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+						expected := &ec2.InstanceMetadataOptionsRequest{
+							HttpTokens:              aws.String("required"),
+							HttpPutResponseHopLimit: aws.Int64(2),
+						}
+						if !reflect.DeepEqual(expected, input.MetadataOptions) {
+							t.Fatalf("expected metadata options %v, got %v", expected, input.MetadataOptions)
+						}
+						return &ec2.Reservation{
+							Instances: []*ec2.Instance{
+								{
+									State: &ec2.InstanceState{
+										Name: aws.String(ec2.InstanceStateNamePending),
+									},
+									IamInstanceProfile: &ec2.IamInstanceProfile{
+										Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+									},
+									InstanceId:     aws.String("two"),
+									InstanceType:   aws.String("m5.large"),
+									SubnetId:       aws.String("subnet-1"),
+									ImageId:        aws.String("ami-1"),
+									RootDeviceName: aws.String("device-1"),
+									BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+										{
+											DeviceName: aws.String("device-1"),
+											Ebs: &ec2.EbsInstanceBlockDevice{
+												VolumeId: aws.String("volume-1"),
+											},
+										},
+									},
+									MetadataOptions: &ec2.InstanceMetadataOptionsResponse{
+										HttpEndpoint:            aws.String("enabled"),
+										HttpTokens:              aws.String("required"),
+										HttpPutResponseHopLimit: aws.Int64(2),
+									},
+								},
+							},
+						}, nil
+					})
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if instance.InstanceMetadataOptions == nil || instance.InstanceMetadataOptions.HTTPTokens != "required" {
+					t.Errorf("expected instance requiring session tokens, got %v", instance.InstanceMetadataOptions)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			elbMock := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test1",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ClusterNetwork: &clusterv1.ClusterNetwork{
+						ServiceDomain: "cluster.local",
+						Services: &clusterv1.NetworkRanges{
+							CIDRBlocks: []string{"192.168.0.0/16"},
+						},
+						Pods: &clusterv1.NetworkRanges{
+							CIDRBlocks: []string{"192.168.0.0/16"},
+						},
+					},
+				},
+			}
+
+			awsCluster := tc.awsCluster
+
+			machine := &tc.machine
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "aws-test1",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: clusterv1.GroupVersion.String(),
+							Kind:       "Machine",
+							Name:       "test1",
+						},
+					},
+				},
+			}
+
+			client := fake.NewFakeClient(secret, cluster, machine)
+
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client: client,
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+					ELB: elbMock,
+				},
+				Cluster:    cluster,
+				Machine:    machine,
+				AWSMachine: awsMachine,
+				AWSCluster: awsCluster,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+			machineScope.AWSMachine.Spec = *tc.machineConfig
+			tc.expect(ec2Mock.EXPECT())
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+					ELB: elbMock,
+				},
+				Cluster:    cluster,
+				AWSCluster: tc.awsCluster,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			s := NewService(clusterScope)
+			instance, err := s.CreateInstance(machineScope, []byte("userData"))
+			tc.check(instance, err)
+		})
+	}
+}
+
+func TestGetInstancePlacement(t *testing.T) {
+	testCases := []struct {
+		name     string
+		instance *infrav1.Instance
+		expected *ec2.Placement
+	}{
+		{
+			name:     "no placement",
+			instance: &infrav1.Instance{},
+			expected: nil,
+		},
+		{
+			name: "placement group",
+			instance: &infrav1.Instance{
+				PlacementGroupName: "hpc",
+				PartitionNumber:    aws.Int64(2),
+			},
+			expected: &ec2.Placement{
+				GroupName:       aws.String("hpc"),
+				PartitionNumber: aws.Int64(2),
+			},
+		},
+		{
+			name: "dedicated host",
+			instance: &infrav1.Instance{
+				HostID:       aws.String("h-0123456789abcdef0"),
+				HostAffinity: aws.String("host"),
+			},
+			expected: &ec2.Placement{
+				HostId:   aws.String("h-0123456789abcdef0"),
+				Affinity: aws.String("host"),
+				Tenancy:  aws.String("host"),
+			},
+		},
+		{
+			name: "dedicated tenancy",
+			instance: &infrav1.Instance{
+				Tenancy: aws.String("dedicated"),
+			},
+			expected: &ec2.Placement{
+				Tenancy: aws.String("dedicated"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			placement := getInstancePlacement(tc.instance)
+			if !reflect.DeepEqual(tc.expected, placement) {
+				t.Errorf("expected placement %v, got %v", tc.expected, placement)
+			}
+		})
+	}
+}
+
+func TestGetInstanceNetworkInterfaceSpecs(t *testing.T) {
+	instance := &infrav1.Instance{
+		SubnetID:         "subnet-1",
+		SecurityGroupIDs: []string{"sg-1", "sg-2"},
+		NetworkInterfaceSpecs: []infrav1.NetworkInterfaceSpec{
+			{DeviceIndex: 0, InterfaceType: "efa"},
+			{DeviceIndex: 1},
+			{
+				DeviceIndex:      2,
+				SubnetID:         aws.String("subnet-storage"),
+				SecurityGroupIDs: []string{"sg-storage"},
+				Description:      "storage network",
+			},
+		},
+	}
+
+	expected := []*ec2.InstanceNetworkInterfaceSpecification{
+		{
+			DeviceIndex:         aws.Int64(0),
+			SubnetId:            aws.String("subnet-1"),
+			Groups:              aws.StringSlice([]string{"sg-1", "sg-2"}),
+			InterfaceType:       aws.String("efa"),
+			DeleteOnTermination: aws.Bool(true),
+		},
+		{
+			DeviceIndex:         aws.Int64(1),
+			SubnetId:            aws.String("subnet-1"),
+			Groups:              aws.StringSlice([]string{"sg-1", "sg-2"}),
+			DeleteOnTermination: aws.Bool(true),
+		},
+		{
+			DeviceIndex:         aws.Int64(2),
+			SubnetId:            aws.String("subnet-storage"),
+			Groups:              aws.StringSlice([]string{"sg-storage"}),
+			Description:         aws.String("storage network"),
+			DeleteOnTermination: aws.Bool(true),
+		},
+	}
+
+	netInterfaces := getInstanceNetworkInterfaceSpecs(instance)
+	if !reflect.DeepEqual(expected, netInterfaces) {
+		t.Errorf("expected network interfaces %v, got %v", expected, netInterfaces)
+	}
+}
+
+func TestGetInstanceCapacityReservationSpecification(t *testing.T) {
+	testCases := []struct {
+		name     string
+		instance *infrav1.Instance
+		expected *ec2.CapacityReservationSpecification
+	}{
+		{
+			name:     "no capacity reservation",
+			instance: &infrav1.Instance{},
+			expected: nil,
+		},
+		{
+			name: "capacity reservation target",
+			instance: &infrav1.Instance{
+				CapacityReservationID: aws.String("cr-0123456789abcdef0"),
+			},
+			expected: &ec2.CapacityReservationSpecification{
+				CapacityReservationTarget: &ec2.CapacityReservationTarget{
+					CapacityReservationId: aws.String("cr-0123456789abcdef0"),
+				},
+			},
+		},
+		{
+			name: "capacity reservation preference",
+			instance: &infrav1.Instance{
+				CapacityReservationPreference: "none",
+			},
+			expected: &ec2.CapacityReservationSpecification{
+				CapacityReservationPreference: aws.String("none"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := getInstanceCapacityReservationSpecification(tc.instance)
+			if !reflect.DeepEqual(tc.expected, spec) {
+				t.Errorf("expected capacity reservation specification %v, got %v", tc.expected, spec)
+			}
+		})
+	}
+}
+
+func TestGetAdditionalSecurityGroupsIDs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-machine"},
+	}
+	awsCluster := &infrav1.AWSCluster{
+		Spec: infrav1.AWSClusterSpec{
+			NetworkSpec: infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{ID: "vpc-1"},
+			},
+		},
+	}
+	awsMachine := &infrav1.AWSMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-machine"},
+		Spec: infrav1.AWSMachineSpec{
+			AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+				{ID: aws.String("sg-1")},
+				{Filters: []infrav1.Filter{{Name: "tag:role", Values: []string{"ingress"}}}},
+			},
+		},
+	}
+
+	client := fake.NewFakeClient(cluster, machine)
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:     client,
+		Cluster:    cluster,
+		Machine:    machine,
+		AWSCluster: awsCluster,
+		AWSMachine: awsMachine,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client: client,
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+		Cluster:    cluster,
+		AWSCluster: awsCluster,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	ec2Mock.EXPECT().DescribeSecurityGroups(gomock.Eq(&ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:role"),
+				Values: aws.StringSlice([]string{"ingress"}),
+			},
+			{
+				Name:   aws.String("vpc-id"),
+				Values: aws.StringSlice([]string{"vpc-1"}),
+			},
+		},
+	})).Return(&ec2.DescribeSecurityGroupsOutput{
+		SecurityGroups: []*ec2.SecurityGroup{
+			{GroupId: aws.String("sg-2")},
+			{GroupId: aws.String("sg-3")},
+		},
+	}, nil)
+
+	s := NewService(clusterScope)
+	ids, err := s.GetAdditionalSecurityGroupsIDs(machineScope)
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	expected := []string{"sg-1", "sg-2", "sg-3"}
+	if !reflect.DeepEqual(expected, ids) {
+		t.Errorf("expected security groups %v, got %v", expected, ids)
+	}
+}
+
+func TestUpdateInstanceResourceTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+		Cluster:    &clusterv1.Cluster{},
+		AWSCluster: &infrav1.AWSCluster{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	attachedFilter := []*ec2.Filter{
+		{
+			Name:   aws.String("attachment.instance-id"),
+			Values: []*string{aws.String("i-1")},
+		},
+	}
+
+	ec2Mock.EXPECT().DescribeVolumes(gomock.Eq(&ec2.DescribeVolumesInput{Filters: attachedFilter})).
+		Return(&ec2.DescribeVolumesOutput{
+			Volumes: []*ec2.Volume{
+				{
+					VolumeId: aws.String("vol-1"),
+					Tags: []*ec2.Tag{
+						{Key: aws.String("team"), Value: aws.String("infra")},
+						{Key: aws.String("old"), Value: aws.String("value")},
+					},
+				},
+			},
+		}, nil)
+	ec2Mock.EXPECT().DescribeNetworkInterfaces(gomock.Eq(&ec2.DescribeNetworkInterfacesInput{Filters: attachedFilter})).
+		Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []*ec2.NetworkInterface{
+				{
+					NetworkInterfaceId: aws.String("eni-1"),
+					TagSet: []*ec2.Tag{
+						{Key: aws.String("team"), Value: aws.String("platform")},
+					},
+				},
+			},
+		}, nil)
+	ec2Mock.EXPECT().DeleteTags(gomock.Eq(&ec2.DeleteTagsInput{
+		Resources: aws.StringSlice([]string{"vol-1"}),
+		Tags:      []*ec2.Tag{{Key: aws.String("old"), Value: aws.String("value")}},
+	})).
+		Return(&ec2.DeleteTagsOutput{}, nil)
+	ec2Mock.EXPECT().CreateTags(gomock.Eq(&ec2.CreateTagsInput{
+		Resources: aws.StringSlice([]string{"eni-1"}),
+		Tags:      []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("infra")}},
+	})).
+		Return(&ec2.CreateTagsOutput{}, nil)
+	ec2Mock.EXPECT().CreateTags(gomock.Eq(&ec2.CreateTagsInput{
+		Resources: aws.StringSlice([]string{"i-1"}),
+		Tags:      []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("infra")}},
+	})).
+		Return(&ec2.CreateTagsOutput{}, nil)
+
+	instance := &infrav1.Instance{
+		ID:   "i-1",
+		Tags: infrav1.Tags{"Name": "test-machine"},
+	}
+
+	s := NewService(clusterScope)
+	if err := s.UpdateInstanceResourceTags(instance, map[string]string{"team": "infra"}, map[string]string{"old": "value"}); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
 	}
 }
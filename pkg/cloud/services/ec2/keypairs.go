@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// ReconcileSSHKeyPair creates the EC2 key pair generated for the cluster and stores its private key in a secret.
+// The private key of a key pair can't be retrieved after its creation, a key pair without a secret is recreated
+// if it's owned by the cluster.
+func (s *Service) ReconcileSSHKeyPair() error {
+	if !s.scope.AWSCluster.Spec.GenerateSSHKey {
+		return nil
+	}
+
+	name := s.scope.SSHKeyName()
+	s.scope.V(2).Info("Reconciling SSH key pair", "key-name", name)
+
+	hasSecret, err := s.scope.HasSSHKeySecret()
+	if err != nil {
+		return err
+	}
+
+	keyPair, err := s.describeKeyPair(name)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case keyPair != nil && !converters.TagsToMap(keyPair.Tags).HasOwned(s.scope.Name()):
+		record.Warnf(s.scope.AWSCluster, "FailedCreateKeyPair", "SSH key pair %q already exists and isn't owned by the cluster", name)
+		return errors.Errorf("SSH key pair %q already exists and isn't owned by cluster %q", name, s.scope.Name())
+	case hasSecret && keyPair != nil:
+		s.scope.V(4).Info("SSH key pair is up to date", "key-name", name)
+		return nil
+	case hasSecret:
+		return errors.Errorf("SSH key pair %q doesn't exist anymore, delete secret %s/%s to generate a new one", name, s.scope.Namespace(), s.scope.SSHKeySecretName())
+	case keyPair != nil:
+		if err := s.deleteKeyPair(name); err != nil {
+			return err
+		}
+	}
+
+	out, err := s.scope.EC2.CreateKeyPair(&ec2.CreateKeyPairInput{
+		KeyName: aws.String(name),
+	})
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreateKeyPair", "Failed to create SSH key pair %q: %v", name, err)
+		return errors.Wrapf(err, "failed to create SSH key pair %q", name)
+	}
+
+	if err := s.tagKeyPair(out.KeyPairId); err != nil {
+		// An untagged key pair would never be replaced, delete it to start over.
+		if deleteErr := s.deleteKeyPair(name); deleteErr != nil {
+			return deleteErr
+		}
+		return err
+	}
+
+	if err := s.scope.CreateSSHKeySecret([]byte(aws.StringValue(out.KeyMaterial))); err != nil {
+		return err
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreateKeyPair", "Created SSH key pair %q", name)
+	s.scope.V(2).Info("Created SSH key pair", "key-name", name)
+	return nil
+}
+
+// DeleteSSHKeyPair deletes the EC2 key pair generated for the cluster, if it's owned by the cluster.
+// The secret holding its private key is owned by the AWSCluster and garbage collected with it.
+func (s *Service) DeleteSSHKeyPair() error {
+	if !s.scope.AWSCluster.Spec.GenerateSSHKey {
+		return nil
+	}
+
+	name := s.scope.SSHKeyName()
+	keyPair, err := s.describeKeyPair(name)
+	if err != nil {
+		return err
+	}
+
+	if keyPair == nil {
+		return nil
+	}
+
+	if !converters.TagsToMap(keyPair.Tags).HasOwned(s.scope.Name()) {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteKeyPair", "SSH key pair %q isn't owned by the cluster, leaving it in place", name)
+		return nil
+	}
+
+	if err := s.deleteKeyPair(name); err != nil {
+		return err
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteKeyPair", "Deleted SSH key pair %q", name)
+	return nil
+}
+
+func (s *Service) describeKeyPair(name string) (*ec2.KeyPairInfo, error) {
+	out, err := s.scope.EC2.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("key-name"),
+				Values: aws.StringSlice([]string{name}),
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe SSH key pair %q", name)
+	}
+
+	if len(out.KeyPairs) == 0 {
+		return nil, nil
+	}
+
+	return out.KeyPairs[0], nil
+}
+
+func (s *Service) tagKeyPair(id *string) error {
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(s.scope.SSHKeyName()),
+		Additional:  s.scope.AdditionalTags(),
+	})
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.scope.EC2.CreateTags(&ec2.CreateTagsInput{
+			Resources: []*string{id},
+			Tags:      converters.MapToTags(tags),
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.ResourceNotFound); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedTagKeyPair", "Failed to tag SSH key pair %q: %v", aws.StringValue(id), err)
+		return errors.Wrapf(err, "failed to tag SSH key pair %q", aws.StringValue(id))
+	}
+
+	return nil
+}
+
+func (s *Service) deleteKeyPair(name string) error {
+	if _, err := s.scope.EC2.DeleteKeyPair(&ec2.DeleteKeyPairInput{
+		KeyName: aws.String(name),
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteKeyPair", "Failed to delete SSH key pair %q: %v", name, err)
+		return errors.Wrapf(err, "failed to delete SSH key pair %q", name)
+	}
+
+	return nil
+}
+
+// fallbackSSHKeyName returns the key name of instances that don't specify one.
+func (s *Service) fallbackSSHKeyName() *string {
+	if s.scope.AWSCluster.Spec.GenerateSSHKey {
+		return aws.String(s.scope.SSHKeyName())
+	}
+
+	return aws.String(defaultSSHKeyName)
+}
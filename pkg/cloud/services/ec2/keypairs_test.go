@@ -0,0 +1,294 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileSSHKeyPair(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	describeInput := &ec2.DescribeKeyPairsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("key-name"),
+				Values: aws.StringSlice([]string{"default.test-cluster-ssh-key"}),
+			},
+		},
+	}
+
+	ownedKeyPair := &ec2.KeyPairInfo{
+		KeyName:   aws.String("default.test-cluster-ssh-key"),
+		KeyPairId: aws.String("key-1"),
+		Tags: []*ec2.Tag{
+			{
+				Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+				Value: aws.String("owned"),
+			},
+		},
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster-ssh-key"},
+	}
+
+	expectTagKeyPair := func(m *mock_ec2iface.MockEC2APIMockRecorder, id string) *gomock.Call {
+		return m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+			DoAndReturn(func(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+				if aws.StringValue(input.Resources[0]) != id {
+					t.Fatalf("expected key pair %q to be tagged, got %q", id, aws.StringValue(input.Resources[0]))
+				}
+				if !converters.TagsToMap(input.Tags).HasOwned("test-cluster") {
+					t.Fatalf("expected key pair to be tagged as owned, got %v", input.Tags)
+				}
+				return &ec2.CreateTagsOutput{}, nil
+			})
+	}
+
+	testCases := []struct {
+		name          string
+		objects       []runtime.Object
+		expect        func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectErr     bool
+		expectPrivKey string
+	}{
+		{
+			name: "creates the key pair, tags it and stores its private key",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				gomock.InOrder(
+					m.DescribeKeyPairs(gomock.Eq(describeInput)).
+						Return(&ec2.DescribeKeyPairsOutput{}, nil),
+					m.CreateKeyPair(gomock.Eq(&ec2.CreateKeyPairInput{
+						KeyName: aws.String("default.test-cluster-ssh-key"),
+					})).
+						Return(&ec2.CreateKeyPairOutput{
+							KeyName:     aws.String("default.test-cluster-ssh-key"),
+							KeyPairId:   aws.String("key-1"),
+							KeyMaterial: aws.String("private-key"),
+						}, nil),
+					expectTagKeyPair(m, "key-1"),
+				)
+			},
+			expectPrivKey: "private-key",
+		},
+		{
+			name: "recreates an owned key pair without a secret",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				gomock.InOrder(
+					m.DescribeKeyPairs(gomock.Eq(describeInput)).
+						Return(&ec2.DescribeKeyPairsOutput{
+							KeyPairs: []*ec2.KeyPairInfo{ownedKeyPair},
+						}, nil),
+					m.DeleteKeyPair(gomock.Eq(&ec2.DeleteKeyPairInput{
+						KeyName: aws.String("default.test-cluster-ssh-key"),
+					})).
+						Return(&ec2.DeleteKeyPairOutput{}, nil),
+					m.CreateKeyPair(gomock.Any()).
+						Return(&ec2.CreateKeyPairOutput{
+							KeyName:     aws.String("default.test-cluster-ssh-key"),
+							KeyPairId:   aws.String("key-2"),
+							KeyMaterial: aws.String("new-private-key"),
+						}, nil),
+					expectTagKeyPair(m, "key-2"),
+				)
+			},
+			expectPrivKey: "new-private-key",
+		},
+		{
+			name: "fails when an existing key pair isn't owned by the cluster",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeKeyPairsOutput{
+						KeyPairs: []*ec2.KeyPairInfo{{KeyName: aws.String("default.test-cluster-ssh-key")}},
+					}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name:    "does nothing when the key pair and its secret exist",
+			objects: []runtime.Object{secret.DeepCopy()},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeKeyPairsOutput{
+						KeyPairs: []*ec2.KeyPairInfo{ownedKeyPair},
+					}, nil)
+			},
+		},
+		{
+			name:    "fails when the key pair of an existing secret was deleted",
+			objects: []runtime.Object{secret.DeepCopy()},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeKeyPairsOutput{}, nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			client := fake.NewFakeClient(tc.objects...)
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+					Spec: infrav1.AWSClusterSpec{
+						GenerateSSHKey: true,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			err = s.ReconcileSSHKeyPair()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+
+			if tc.expectPrivKey == "" {
+				return
+			}
+
+			created := &corev1.Secret{}
+			if err := client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "test-cluster-ssh-key"}, created); err != nil {
+				t.Fatalf("failed to get SSH key secret: %v", err)
+			}
+			if got := string(created.Data[corev1.SSHAuthPrivateKey]); got != tc.expectPrivKey {
+				t.Fatalf("expected private key %q, got %q", tc.expectPrivKey, got)
+			}
+		})
+	}
+}
+
+func TestDeleteSSHKeyPair(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name: "deletes the key pair owned by the cluster",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				gomock.InOrder(
+					m.DescribeKeyPairs(gomock.Any()).
+						Return(&ec2.DescribeKeyPairsOutput{
+							KeyPairs: []*ec2.KeyPairInfo{
+								{
+									KeyName: aws.String("default.test-cluster-ssh-key"),
+									Tags: []*ec2.Tag{
+										{
+											Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+											Value: aws.String("owned"),
+										},
+									},
+								},
+							},
+						}, nil),
+					m.DeleteKeyPair(gomock.Eq(&ec2.DeleteKeyPairInput{
+						KeyName: aws.String("default.test-cluster-ssh-key"),
+					})).
+						Return(&ec2.DeleteKeyPairOutput{}, nil),
+				)
+			},
+		},
+		{
+			name: "leaves key pairs that aren't owned by the cluster",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Any()).
+					Return(&ec2.DescribeKeyPairsOutput{
+						KeyPairs: []*ec2.KeyPairInfo{{KeyName: aws.String("default.test-cluster-ssh-key")}},
+					}, nil)
+			},
+		},
+		{
+			name: "does nothing when the key pair doesn't exist",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Any()).
+					Return(&ec2.DescribeKeyPairsOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: fake.NewFakeClient(),
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+					Spec: infrav1.AWSClusterSpec{
+						GenerateSSHKey: true,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			if err := s.DeleteSSHKeyPair(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
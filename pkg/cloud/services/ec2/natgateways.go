@@ -37,6 +37,15 @@ func (s *Service) reconcileNatGateways() error {
 		return nil
 	}
 
+	if s.natGatewayStrategy() == infrav1.NatGatewayStrategyNone {
+		// NAT gateways left over from another strategy are deleted once the route tables no longer use them.
+		for _, sn := range s.scope.Subnets().FilterPublic() {
+			sn.NatGatewayID = nil
+		}
+		s.scope.V(4).Info("Skipping NAT gateway reconcile, NAT gateway strategy is none")
+		return nil
+	}
+
 	s.scope.V(2).Info("Reconciling NAT gateways")
 
 	if len(s.scope.Subnets().FilterPrivate()) == 0 {
@@ -52,11 +61,18 @@ func (s *Service) reconcileNatGateways() error {
 		return err
 	}
 
-	for _, sn := range s.scope.Subnets().FilterPublic() {
-		if sn.ID == "" {
-			continue
+	natGatewaySubnets := s.natGatewaySubnets(existing)
+	if s.natGatewayStrategy() == infrav1.NatGatewayStrategySingle {
+		// Private subnets route through the single NAT gateway, the gateways left over from the
+		// per-AZ strategy are deleted once the route tables no longer use them.
+		for _, sn := range s.scope.Subnets().FilterPublic() {
+			if len(natGatewaySubnets) > 0 && sn.ID != natGatewaySubnets[0].ID {
+				sn.NatGatewayID = nil
+			}
 		}
+	}
 
+	for _, sn := range natGatewaySubnets {
 		if ngw, ok := existing[sn.ID]; ok {
 			// Make sure tags are up to date.
 			if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
@@ -75,7 +91,7 @@ func (s *Service) reconcileNatGateways() error {
 			continue
 		}
 
-		ng, err := s.createNatGateway(sn.ID)
+		ng, err := s.createNatGateway(sn)
 		if err != nil {
 			return err
 		}
@@ -86,6 +102,128 @@ func (s *Service) reconcileNatGateways() error {
 	return nil
 }
 
+func (s *Service) natGatewayStrategy() infrav1.NatGatewayStrategy {
+	if s.scope.NetworkSpec().FullyPrivate {
+		return infrav1.NatGatewayStrategyNone
+	}
+	if s.scope.VPC().NatGatewayStrategy == "" {
+		return infrav1.NatGatewayStrategyPerAZ
+	}
+	return s.scope.VPC().NatGatewayStrategy
+}
+
+// natGatewaySubnets returns the public subnets that should host a NAT gateway.
+func (s *Service) natGatewaySubnets(existing map[string]*ec2.NatGateway) infrav1.Subnets {
+	subnets := infrav1.Subnets{}
+	for _, sn := range s.scope.Subnets().FilterPublic() {
+		if sn.ID == "" {
+			continue
+		}
+		subnets = append(subnets, sn)
+	}
+
+	if s.natGatewayStrategy() != infrav1.NatGatewayStrategySingle || len(subnets) == 0 {
+		return subnets
+	}
+
+	// Prefer a subnet that already hosts a NAT gateway, so the single gateway is stable across reconciles.
+	for _, sn := range subnets {
+		if _, ok := existing[sn.ID]; ok {
+			return infrav1.Subnets{sn}
+		}
+	}
+	return subnets[:1]
+}
+
+// deleteUnusedNatGateways deletes the NAT gateways left over from a switch to the single or none NAT gateway
+// strategy, and releases their Elastic IPs. It runs after the route tables are reconciled, so that the private
+// subnets route through the remaining NAT gateway, or no longer route through a NAT gateway, first. Gateways
+// still referenced by a route are kept.
+func (s *Service) deleteUnusedNatGateways() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) || s.natGatewayStrategy() == infrav1.NatGatewayStrategyPerAZ {
+		return nil
+	}
+
+	existing, err := s.describeNatGatewaysBySubnet()
+	if err != nil {
+		return err
+	}
+
+	keep := map[string]bool{}
+	if s.natGatewayStrategy() == infrav1.NatGatewayStrategySingle {
+		for _, sn := range s.natGatewaySubnets(existing) {
+			keep[sn.ID] = true
+		}
+	}
+
+	unused := []*ec2.NatGateway{}
+	for subnetID, ngw := range existing {
+		if !keep[subnetID] {
+			unused = append(unused, ngw)
+		}
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+
+	routed, err := s.describeRoutedNatGateways()
+	if err != nil {
+		return err
+	}
+
+	addresses, err := s.describeAddresses(infrav1.APIServerRoleTagValue)
+	if err != nil {
+		return errors.Wrap(err, "failed to query addresses")
+	}
+	owned := map[string]bool{}
+	for _, address := range addresses.Addresses {
+		owned[aws.StringValue(address.AllocationId)] = true
+	}
+
+	for _, ngw := range unused {
+		id := aws.StringValue(ngw.NatGatewayId)
+		if routed[id] {
+			s.scope.V(2).Info("Keeping unused NAT gateway, it's still referenced by a route", "nat-gateway-id", id)
+			continue
+		}
+
+		if err := s.deleteNatGateway(id); err != nil {
+			return err
+		}
+
+		// Elastic IPs provided through natGatewayEIPAllocationID aren't owned by the cluster and are kept.
+		for _, address := range ngw.NatGatewayAddresses {
+			if !owned[aws.StringValue(address.AllocationId)] {
+				continue
+			}
+			if err := s.releaseAddress(aws.StringValue(address.AllocationId)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// describeRoutedNatGateways returns the ids of the NAT gateways referenced by a route of the managed route tables.
+func (s *Service) describeRoutedNatGateways() (map[string]bool, error) {
+	rts, err := s.describeVpcRouteTables()
+	if err != nil {
+		return nil, err
+	}
+
+	routed := map[string]bool{}
+	for _, rt := range rts {
+		for _, route := range rt.Routes {
+			if route.NatGatewayId != nil {
+				routed[*route.NatGatewayId] = true
+			}
+		}
+	}
+
+	return routed, nil
+}
+
 func (s *Service) deleteNatGateways() error {
 	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
 		s.scope.V(4).Info("Skipping NAT gateway deletion in unmanaged mode")
@@ -159,10 +297,20 @@ func (s *Service) getNatGatewayTagParams(id string) infrav1.BuildParams {
 	}
 }
 
-func (s *Service) createNatGateway(subnetID string) (*ec2.NatGateway, error) {
-	ip, err := s.getOrAllocateAddress(infrav1.APIServerRoleTagValue)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create IP address for NAT gateway for subnet ID %q", subnetID)
+func (s *Service) createNatGateway(sn *infrav1.SubnetSpec) (*ec2.NatGateway, error) {
+	subnetID := sn.ID
+
+	var (
+		ip  string
+		err error
+	)
+	if sn.NatGatewayEIPAllocationID != nil {
+		ip = *sn.NatGatewayEIPAllocationID
+	} else {
+		ip, err = s.getOrAllocateAddress(infrav1.APIServerRoleTagValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create IP address for NAT gateway for subnet ID %q", subnetID)
+		}
 	}
 
 	var out *ec2.CreateNatGatewayOutput
@@ -268,5 +416,13 @@ func (s *Service) getNatGatewayForSubnet(sn *infrav1.SubnetSpec) (string, error)
 		return gws[0], nil
 	}
 
+	if s.natGatewayStrategy() == infrav1.NatGatewayStrategySingle {
+		for _, psn := range s.scope.Subnets().FilterPublic() {
+			if psn.NatGatewayID != nil {
+				return *psn.NatGatewayID, nil
+			}
+		}
+	}
+
 	return "", errors.Errorf("no nat gateways available in %q for private subnet %q, current state: %+v", sn.AvailabilityZone, sn.ID, azGateways)
 }
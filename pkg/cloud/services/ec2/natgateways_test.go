@@ -133,6 +133,47 @@ func TestReconcileNatGateways(t *testing.T) {
 					Return(nil, nil)
 			},
 		},
+		{
+			name: "public & private subnet exists with a pre-allocated Elastic IP, should create 1 NAT gateway using it",
+			input: []*infrav1.SubnetSpec{
+				{
+					ID:                        "subnet-1",
+					AvailabilityZone:          "us-east-1a",
+					CidrBlock:                 "10.0.10.0/24",
+					IsPublic:                  true,
+					NatGatewayEIPAllocationID: aws.String("eipalloc-byo"),
+				},
+				{
+					ID:               "subnet-2",
+					AvailabilityZone: "us-east-1a",
+					CidrBlock:        "10.0.12.0/24",
+					IsPublic:         false,
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNatGatewaysPages(gomock.AssignableToTypeOf(&ec2.DescribeNatGatewaysInput{}), gomock.Any()).
+					Return(nil)
+
+				m.DescribeAddresses(gomock.Any()).Times(0)
+				m.AllocateAddress(gomock.Any()).Times(0)
+
+				m.CreateNatGateway(&ec2.CreateNatGatewayInput{
+					AllocationId: aws.String("eipalloc-byo"),
+					SubnetId:     aws.String("subnet-1"),
+				}).Return(&ec2.CreateNatGatewayOutput{
+					NatGateway: &ec2.NatGateway{
+						NatGatewayId: aws.String("natgateway"),
+					},
+				}, nil)
+
+				m.WaitUntilNatGatewayAvailable(&ec2.DescribeNatGatewaysInput{
+					NatGatewayIds: []*string{aws.String("natgateway")},
+				}).Return(nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+		},
 		{
 			name: "two public & 1 private subnet, and one NAT gateway exists",
 			input: []*infrav1.SubnetSpec{
@@ -323,3 +364,215 @@ func TestReconcileNatGateways(t *testing.T) {
 		})
 	}
 }
+
+func TestNatGatewayStrategySingle(t *testing.T) {
+	subnets := infrav1.Subnets{
+		&infrav1.SubnetSpec{ID: "subnet-private-1a", AvailabilityZone: "us-east-1a"},
+		&infrav1.SubnetSpec{ID: "subnet-private-1b", AvailabilityZone: "us-east-1b"},
+		&infrav1.SubnetSpec{ID: "subnet-public-1a", AvailabilityZone: "us-east-1a", IsPublic: true},
+		&infrav1.SubnetSpec{ID: "subnet-public-1b", AvailabilityZone: "us-east-1b", IsPublic: true, NatGatewayID: aws.String("nat-1b")},
+	}
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					VPC: infrav1.VPCSpec{
+						ID:                 "vpc-nats",
+						NatGatewayStrategy: infrav1.NatGatewayStrategySingle,
+					},
+					Subnets: subnets,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	s := NewService(scope)
+
+	hosts := s.natGatewaySubnets(map[string]*ec2.NatGateway{"subnet-public-1b": {NatGatewayId: aws.String("nat-1b")}})
+	if len(hosts) != 1 || hosts[0].ID != "subnet-public-1b" {
+		t.Fatalf("expected the subnet hosting the existing NAT gateway to be reused, got %v", hosts)
+	}
+
+	for _, sn := range subnets.FilterPrivate() {
+		ngw, err := s.getNatGatewayForSubnet(sn)
+		if err != nil {
+			t.Fatalf("got an unexpected error: %v", err)
+		}
+		if ngw != "nat-1b" {
+			t.Fatalf("expected private subnet %q to route through nat-1b, got %q", sn.ID, ngw)
+		}
+	}
+}
+
+func TestDeleteUnusedNatGateways(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	natGateways := []*ec2.NatGateway{
+		{
+			NatGatewayId: aws.String("nat-1a"),
+			SubnetId:     aws.String("subnet-public-1a"),
+			NatGatewayAddresses: []*ec2.NatGatewayAddress{
+				{AllocationId: aws.String("eipalloc-1a")},
+			},
+		},
+		{
+			NatGatewayId: aws.String("nat-1b"),
+			SubnetId:     aws.String("subnet-public-1b"),
+			NatGatewayAddresses: []*ec2.NatGatewayAddress{
+				{AllocationId: aws.String("eipalloc-1b")},
+			},
+		},
+		{
+			NatGatewayId: aws.String("nat-1c"),
+			SubnetId:     aws.String("subnet-public-1c"),
+			NatGatewayAddresses: []*ec2.NatGatewayAddress{
+				{AllocationId: aws.String("eipalloc-byo")},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		strategy infrav1.NatGatewayStrategy
+		expect   func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name:     "per-az strategy, does nothing",
+			strategy: infrav1.NatGatewayStrategyPerAZ,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNatGatewaysPages(gomock.Any(), gomock.Any()).Times(0)
+				m.DeleteNatGateway(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:     "single strategy, deletes the unused NAT gateways and releases their owned addresses",
+			strategy: infrav1.NatGatewayStrategySingle,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNatGatewaysPages(gomock.AssignableToTypeOf(&ec2.DescribeNatGatewaysInput{}), gomock.Any()).
+					DoAndReturn(func(_ *ec2.DescribeNatGatewaysInput, fn func(*ec2.DescribeNatGatewaysOutput, bool) bool) error {
+						fn(&ec2.DescribeNatGatewaysOutput{NatGateways: natGateways}, true)
+						return nil
+					})
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{
+						RouteTables: []*ec2.RouteTable{
+							{
+								RouteTableId: aws.String("rtb-private-1a"),
+								Routes: []*ec2.Route{
+									{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-1a")},
+								},
+							},
+						},
+					}, nil)
+				m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{AllocationId: aws.String("eipalloc-1a")},
+							{AllocationId: aws.String("eipalloc-1b")},
+						},
+					}, nil)
+
+				m.DeleteNatGateway(&ec2.DeleteNatGatewayInput{NatGatewayId: aws.String("nat-1b")}).
+					Return(&ec2.DeleteNatGatewayOutput{}, nil)
+				m.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{NatGatewayIds: []*string{aws.String("nat-1b")}}).
+					Return(&ec2.DescribeNatGatewaysOutput{
+						NatGateways: []*ec2.NatGateway{{NatGatewayId: aws.String("nat-1b"), State: aws.String(ec2.NatGatewayStateDeleted)}},
+					}, nil)
+				m.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: aws.String("eipalloc-1b")}).
+					Return(&ec2.ReleaseAddressOutput{}, nil)
+
+				m.DeleteNatGateway(&ec2.DeleteNatGatewayInput{NatGatewayId: aws.String("nat-1c")}).
+					Return(&ec2.DeleteNatGatewayOutput{}, nil)
+				m.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{NatGatewayIds: []*string{aws.String("nat-1c")}}).
+					Return(&ec2.DescribeNatGatewaysOutput{
+						NatGateways: []*ec2.NatGateway{{NatGatewayId: aws.String("nat-1c"), State: aws.String(ec2.NatGatewayStateDeleted)}},
+					}, nil)
+			},
+		},
+		{
+			name:     "none strategy, deletes all NAT gateways and releases their owned addresses",
+			strategy: infrav1.NatGatewayStrategyNone,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNatGatewaysPages(gomock.AssignableToTypeOf(&ec2.DescribeNatGatewaysInput{}), gomock.Any()).
+					DoAndReturn(func(_ *ec2.DescribeNatGatewaysInput, fn func(*ec2.DescribeNatGatewaysOutput, bool) bool) error {
+						fn(&ec2.DescribeNatGatewaysOutput{NatGateways: natGateways}, true)
+						return nil
+					})
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{}, nil)
+				m.DescribeAddresses(gomock.AssignableToTypeOf(&ec2.DescribeAddressesInput{})).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{AllocationId: aws.String("eipalloc-1a")},
+							{AllocationId: aws.String("eipalloc-1b")},
+						},
+					}, nil)
+
+				for _, id := range []string{"1a", "1b", "1c"} {
+					m.DeleteNatGateway(&ec2.DeleteNatGatewayInput{NatGatewayId: aws.String("nat-" + id)}).
+						Return(&ec2.DeleteNatGatewayOutput{}, nil)
+					m.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{NatGatewayIds: []*string{aws.String("nat-" + id)}}).
+						Return(&ec2.DescribeNatGatewaysOutput{
+							NatGateways: []*ec2.NatGateway{{NatGatewayId: aws.String("nat-" + id), State: aws.String(ec2.NatGatewayStateDeleted)}},
+						}, nil)
+				}
+				m.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: aws.String("eipalloc-1a")}).
+					Return(&ec2.ReleaseAddressOutput{}, nil)
+				m.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: aws.String("eipalloc-1b")}).
+					Return(&ec2.ReleaseAddressOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{
+								ID:                 subnetsVPCID,
+								NatGatewayStrategy: tc.strategy,
+								Tags: infrav1.Tags{
+									infrav1.ClusterTagKey("test-cluster"): "owned",
+								},
+							},
+							Subnets: infrav1.Subnets{
+								&infrav1.SubnetSpec{ID: "subnet-private-1a", AvailabilityZone: "us-east-1a"},
+								&infrav1.SubnetSpec{ID: "subnet-public-1a", AvailabilityZone: "us-east-1a", IsPublic: true},
+								&infrav1.SubnetSpec{ID: "subnet-public-1b", AvailabilityZone: "us-east-1b", IsPublic: true},
+								&infrav1.SubnetSpec{ID: "subnet-public-1c", AvailabilityZone: "us-east-1c", IsPublic: true},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			if err := s.deleteUnusedNatGateways(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
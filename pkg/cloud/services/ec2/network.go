@@ -29,6 +29,16 @@ func (s *Service) ReconcileNetwork() (err error) {
 		return err
 	}
 
+	// Flow logs.
+	if err := s.reconcileFlowLogs(); err != nil {
+		return err
+	}
+
+	// DHCP options.
+	if err := s.reconcileDHCPOptions(); err != nil {
+		return err
+	}
+
 	// Subnets.
 	if err := s.reconcileSubnets(); err != nil {
 		return err
@@ -44,16 +54,41 @@ func (s *Service) ReconcileNetwork() (err error) {
 		return err
 	}
 
+	// Transit Gateway attachment.
+	if err := s.reconcileTransitGatewayAttachment(); err != nil {
+		return err
+	}
+
+	// VPC peering connection.
+	if err := s.reconcileVPCPeering(); err != nil {
+		return err
+	}
+
 	// Routing tables.
 	if err := s.reconcileRouteTables(); err != nil {
 		return err
 	}
 
+	// NAT gateways no longer used after the route tables moved to the remaining ones, or away from NAT gateways.
+	if err := s.deleteUnusedNatGateways(); err != nil {
+		return err
+	}
+
+	// Network ACLs.
+	if err := s.reconcileNetworkACLs(); err != nil {
+		return err
+	}
+
 	// Security groups.
 	if err := s.reconcileSecurityGroups(); err != nil {
 		return err
 	}
 
+	// VPC endpoints.
+	if err := s.reconcileVPCEndpoints(); err != nil {
+		return err
+	}
+
 	s.scope.V(2).Info("Reconcile network completed successfully")
 	return nil
 }
@@ -66,12 +101,17 @@ func (s *Service) DeleteNetwork() (err error) {
 	vpc, err := s.describeVPC()
 	if err != nil {
 		if awserrors.IsNotFound(err) {
-			// If the VPC does not exist, nothing to do
-			return nil
+			// If the VPC does not exist, only the DHCP options sets can be left behind
+			return s.deleteDHCPOptions()
 		}
 		return err
 	}
-	vpc.DeepCopyInto(s.scope.VPC())
+	s.updateVPCSpec(vpc)
+
+	// VPC endpoints.
+	if err := s.deleteVPCEndpoints(); err != nil {
+		return err
+	}
 
 	// Security groups.
 	if err := s.deleteSecurityGroups(); err != nil {
@@ -83,6 +123,16 @@ func (s *Service) DeleteNetwork() (err error) {
 		return err
 	}
 
+	// Transit Gateway attachment.
+	if err := s.deleteTransitGatewayAttachment(); err != nil {
+		return err
+	}
+
+	// VPC peering connection.
+	if err := s.deleteVPCPeering(); err != nil {
+		return err
+	}
+
 	// NAT Gateways.
 	if err := s.deleteNatGateways(); err != nil {
 		return err
@@ -103,6 +153,16 @@ func (s *Service) DeleteNetwork() (err error) {
 		return err
 	}
 
+	// Network ACLs, these can only be deleted once they are no longer associated with subnets.
+	if err := s.deleteNetworkACLs(); err != nil {
+		return err
+	}
+
+	// DHCP options, the VPC is switched back to the default options set before they're deleted.
+	if err := s.deleteDHCPOptions(); err != nil {
+		return err
+	}
+
 	// VPC.
 	if err := s.deleteVPC(); err != nil {
 		return err
@@ -0,0 +1,407 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// defaultNetworkACLRuleNumber is the number of the catch-all deny rule AWS adds to every network ACL.
+	defaultNetworkACLRuleNumber = 32767
+)
+
+func (s *Service) reconcileNetworkACLs() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping network ACLs reconcile in unmanaged mode")
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling network ACLs")
+
+	existing, err := s.describeVPCNetworkACLs()
+	if err != nil {
+		return err
+	}
+
+	spec := s.scope.NetworkSpec().NetworkACLs
+	if spec == nil {
+		spec = &infrav1.NetworkACLsSpec{}
+	}
+
+	tiers := []struct {
+		public  bool
+		spec    *infrav1.NetworkACLSpec
+		subnets infrav1.Subnets
+	}{
+		{public: true, spec: spec.Public, subnets: s.scope.Subnets().FilterPublic()},
+		{public: false, spec: spec.Private, subnets: s.scope.Subnets().FilterPrivate()},
+	}
+
+	for _, tier := range tiers {
+		// The managed network ACL of a tier removed from the spec is replaced by the default network ACL.
+		if tier.spec == nil {
+			if acl := s.findOwnedNetworkACL(existing, tier.public); acl != nil {
+				if err := s.deleteNetworkACL(existing, acl); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		acl, err := s.reconcileNetworkACL(existing, tier.public, tier.spec)
+		if err != nil {
+			return err
+		}
+
+		if err := s.associateNetworkACL(existing, acl, tier.subnets); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) reconcileNetworkACL(existing []*ec2.NetworkAcl, public bool, spec *infrav1.NetworkACLSpec) (*ec2.NetworkAcl, error) {
+	acl := s.findOwnedNetworkACL(existing, public)
+	if acl == nil {
+		var err error
+		acl, err = s.createNetworkACL(public)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	desired := make([]*ec2.NetworkAclEntry, 0, len(spec.Ingress)+len(spec.Egress))
+	for i := range spec.Ingress {
+		desired = append(desired, networkACLEntry(&spec.Ingress[i], false))
+	}
+	for i := range spec.Egress {
+		desired = append(desired, networkACLEntry(&spec.Egress[i], true))
+	}
+
+	if err := s.reconcileNetworkACLEntries(acl, desired); err != nil {
+		return nil, err
+	}
+
+	return acl, nil
+}
+
+func (s *Service) reconcileNetworkACLEntries(acl *ec2.NetworkAcl, desired []*ec2.NetworkAclEntry) error {
+	id := aws.StringValue(acl.NetworkAclId)
+
+	current := make(map[string]*ec2.NetworkAclEntry, len(acl.Entries))
+	for _, entry := range acl.Entries {
+		if aws.Int64Value(entry.RuleNumber) == defaultNetworkACLRuleNumber {
+			continue
+		}
+		current[networkACLEntryKey(entry)] = entry
+	}
+
+	for _, entry := range desired {
+		key := networkACLEntryKey(entry)
+		found, ok := current[key]
+		delete(current, key)
+
+		switch {
+		case !ok:
+			if _, err := s.scope.EC2.CreateNetworkAclEntry(&ec2.CreateNetworkAclEntryInput{
+				NetworkAclId: aws.String(id),
+				RuleNumber:   entry.RuleNumber,
+				Egress:       entry.Egress,
+				Protocol:     entry.Protocol,
+				RuleAction:   entry.RuleAction,
+				CidrBlock:    entry.CidrBlock,
+				PortRange:    entry.PortRange,
+				IcmpTypeCode: entry.IcmpTypeCode,
+			}); err != nil {
+				record.Warnf(s.scope.AWSCluster, "FailedCreateNetworkACLEntry", "Failed to create rule %d in managed network ACL %q: %v", aws.Int64Value(entry.RuleNumber), id, err)
+				return errors.Wrapf(err, "failed to create rule %d in network ACL %q", aws.Int64Value(entry.RuleNumber), id)
+			}
+		case !networkACLEntriesMatch(found, entry):
+			if _, err := s.scope.EC2.ReplaceNetworkAclEntry(&ec2.ReplaceNetworkAclEntryInput{
+				NetworkAclId: aws.String(id),
+				RuleNumber:   entry.RuleNumber,
+				Egress:       entry.Egress,
+				Protocol:     entry.Protocol,
+				RuleAction:   entry.RuleAction,
+				CidrBlock:    entry.CidrBlock,
+				PortRange:    entry.PortRange,
+				IcmpTypeCode: entry.IcmpTypeCode,
+			}); err != nil {
+				record.Warnf(s.scope.AWSCluster, "FailedReplaceNetworkACLEntry", "Failed to replace rule %d in managed network ACL %q: %v", aws.Int64Value(entry.RuleNumber), id, err)
+				return errors.Wrapf(err, "failed to replace rule %d in network ACL %q", aws.Int64Value(entry.RuleNumber), id)
+			}
+		default:
+			continue
+		}
+
+		s.scope.V(2).Info("Reconciled network ACL rule", "network-acl-id", id, "rule-number", aws.Int64Value(entry.RuleNumber), "egress", aws.BoolValue(entry.Egress))
+	}
+
+	// Rules that are no longer part of the spec are removed.
+	for _, entry := range current {
+		if _, err := s.scope.EC2.DeleteNetworkAclEntry(&ec2.DeleteNetworkAclEntryInput{
+			NetworkAclId: aws.String(id),
+			RuleNumber:   entry.RuleNumber,
+			Egress:       entry.Egress,
+		}); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedDeleteNetworkACLEntry", "Failed to delete rule %d from managed network ACL %q: %v", aws.Int64Value(entry.RuleNumber), id, err)
+			return errors.Wrapf(err, "failed to delete rule %d from network ACL %q", aws.Int64Value(entry.RuleNumber), id)
+		}
+		s.scope.V(2).Info("Deleted network ACL rule", "network-acl-id", id, "rule-number", aws.Int64Value(entry.RuleNumber), "egress", aws.BoolValue(entry.Egress))
+	}
+
+	return nil
+}
+
+func (s *Service) associateNetworkACL(existing []*ec2.NetworkAcl, acl *ec2.NetworkAcl, subnets infrav1.Subnets) error {
+	associations := make(map[string]*ec2.NetworkAclAssociation)
+	for _, x := range existing {
+		for _, association := range x.Associations {
+			associations[aws.StringValue(association.SubnetId)] = association
+		}
+	}
+
+	id := aws.StringValue(acl.NetworkAclId)
+	for _, sn := range subnets {
+		association, ok := associations[sn.ID]
+		if !ok {
+			return errors.Errorf("failed to find the network ACL association of subnet %q", sn.ID)
+		}
+
+		if aws.StringValue(association.NetworkAclId) == id {
+			continue
+		}
+
+		if _, err := s.scope.EC2.ReplaceNetworkAclAssociation(&ec2.ReplaceNetworkAclAssociationInput{
+			AssociationId: association.NetworkAclAssociationId,
+			NetworkAclId:  aws.String(id),
+		}); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedAssociateNetworkACL", "Failed to associate managed network ACL %q with subnet %q: %v", id, sn.ID, err)
+			return errors.Wrapf(err, "failed to associate network ACL %q with subnet %q", id, sn.ID)
+		}
+
+		record.Eventf(s.scope.AWSCluster, "SuccessfulAssociateNetworkACL", "Associated managed network ACL %q with subnet %q", id, sn.ID)
+	}
+
+	return nil
+}
+
+func (s *Service) createNetworkACL(public bool) (*ec2.NetworkAcl, error) {
+	out, err := s.scope.EC2.CreateNetworkAcl(&ec2.CreateNetworkAclInput{
+		VpcId: aws.String(s.scope.VPC().ID),
+	})
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreateNetworkACL", "Failed to create managed network ACL: %v", err)
+		return nil, errors.Wrapf(err, "failed to create network ACL in vpc %q", s.scope.VPC().ID)
+	}
+
+	id := aws.StringValue(out.NetworkAcl.NetworkAclId)
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreateNetworkACL", "Created new managed network ACL %q", id)
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if err := tags.Apply(&tags.ApplyParams{
+			EC2Client:   s.scope.EC2,
+			BuildParams: s.getNetworkACLTagParams(id, public),
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.ResourceNotFound); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedTagNetworkACL", "Failed to tag managed network ACL %q: %v", id, err)
+		return nil, errors.Wrapf(err, "failed to tag network ACL %q", id)
+	}
+
+	s.scope.V(2).Info("Created network ACL", "network-acl-id", id, "public", public)
+	return out.NetworkAcl, nil
+}
+
+func (s *Service) deleteNetworkACLs() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping network ACLs deletion in unmanaged mode")
+		return nil
+	}
+
+	existing, err := s.describeVPCNetworkACLs()
+	if err != nil {
+		return err
+	}
+
+	for _, acl := range existing {
+		if aws.BoolValue(acl.IsDefault) || !converters.TagsToMap(acl.Tags).HasOwned(s.scope.Name()) {
+			continue
+		}
+
+		if err := s.deleteNetworkACL(existing, acl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteNetworkACL associates the subnets still using the managed network ACL with the default network ACL
+// of the VPC, and deletes the managed network ACL.
+func (s *Service) deleteNetworkACL(existing []*ec2.NetworkAcl, acl *ec2.NetworkAcl) error {
+	id := aws.StringValue(acl.NetworkAclId)
+
+	if len(acl.Associations) > 0 {
+		var defaultACL *ec2.NetworkAcl
+		for _, x := range existing {
+			if aws.BoolValue(x.IsDefault) {
+				defaultACL = x
+				break
+			}
+		}
+		if defaultACL == nil {
+			return errors.Errorf("failed to find the default network ACL of vpc %q", s.scope.VPC().ID)
+		}
+
+		defaultID := aws.StringValue(defaultACL.NetworkAclId)
+		for _, association := range acl.Associations {
+			if _, err := s.scope.EC2.ReplaceNetworkAclAssociation(&ec2.ReplaceNetworkAclAssociationInput{
+				AssociationId: association.NetworkAclAssociationId,
+				NetworkAclId:  aws.String(defaultID),
+			}); err != nil {
+				record.Warnf(s.scope.AWSCluster, "FailedAssociateNetworkACL", "Failed to associate default network ACL %q with subnet %q: %v", defaultID, aws.StringValue(association.SubnetId), err)
+				return errors.Wrapf(err, "failed to associate network ACL %q with subnet %q", defaultID, aws.StringValue(association.SubnetId))
+			}
+
+			record.Eventf(s.scope.AWSCluster, "SuccessfulAssociateNetworkACL", "Associated default network ACL %q with subnet %q", defaultID, aws.StringValue(association.SubnetId))
+		}
+	}
+
+	if _, err := s.scope.EC2.DeleteNetworkAcl(&ec2.DeleteNetworkAclInput{NetworkAclId: aws.String(id)}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteNetworkACL", "Failed to delete managed network ACL %q: %v", id, err)
+		return errors.Wrapf(err, "failed to delete network ACL %q", id)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteNetworkACL", "Deleted managed network ACL %q", id)
+	s.scope.V(2).Info("Deleted network ACL", "network-acl-id", id)
+	return nil
+}
+
+// findOwnedNetworkACL returns the network ACL managed for the public or private subnets of the cluster, if any.
+func (s *Service) findOwnedNetworkACL(existing []*ec2.NetworkAcl, public bool) *ec2.NetworkAcl {
+	role := networkACLRole(public)
+	for _, x := range existing {
+		if converters.TagsToMap(x.Tags).GetRole() == role && converters.TagsToMap(x.Tags).HasOwned(s.scope.Name()) {
+			return x
+		}
+	}
+	return nil
+}
+
+func (s *Service) describeVPCNetworkACLs() ([]*ec2.NetworkAcl, error) {
+	out, err := s.scope.EC2.DescribeNetworkAcls(&ec2.DescribeNetworkAclsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe network ACLs in vpc %q", s.scope.VPC().ID)
+	}
+
+	return out.NetworkAcls, nil
+}
+
+// networkACLEntry converts a rule of the spec into its EC2 representation, using protocol numbers
+// as returned by the EC2 API.
+func networkACLEntry(rule *infrav1.NetworkACLRule, egress bool) *ec2.NetworkAclEntry {
+	entry := &ec2.NetworkAclEntry{
+		RuleNumber: aws.Int64(rule.RuleNumber),
+		Egress:     aws.Bool(egress),
+		RuleAction: aws.String(string(rule.Action)),
+		CidrBlock:  aws.String(rule.CidrBlock),
+	}
+
+	switch rule.Protocol {
+	case infrav1.SecurityGroupProtocolTCP, infrav1.SecurityGroupProtocolUDP:
+		protocol := "6"
+		if rule.Protocol == infrav1.SecurityGroupProtocolUDP {
+			protocol = "17"
+		}
+		entry.Protocol = aws.String(protocol)
+		entry.PortRange = &ec2.PortRange{
+			From: aws.Int64(rule.FromPort),
+			To:   aws.Int64(rule.ToPort),
+		}
+	case infrav1.SecurityGroupProtocolICMP:
+		entry.Protocol = aws.String("1")
+		entry.IcmpTypeCode = &ec2.IcmpTypeCode{
+			Type: aws.Int64(-1),
+			Code: aws.Int64(-1),
+		}
+	default:
+		entry.Protocol = aws.String(string(rule.Protocol))
+	}
+
+	return entry
+}
+
+func networkACLEntryKey(entry *ec2.NetworkAclEntry) string {
+	return fmt.Sprintf("%t/%d", aws.BoolValue(entry.Egress), aws.Int64Value(entry.RuleNumber))
+}
+
+func networkACLEntriesMatch(current, desired *ec2.NetworkAclEntry) bool {
+	if aws.StringValue(current.Protocol) != aws.StringValue(desired.Protocol) ||
+		aws.StringValue(current.RuleAction) != aws.StringValue(desired.RuleAction) ||
+		aws.StringValue(current.CidrBlock) != aws.StringValue(desired.CidrBlock) {
+		return false
+	}
+
+	if desired.PortRange != nil {
+		return current.PortRange != nil &&
+			aws.Int64Value(current.PortRange.From) == aws.Int64Value(desired.PortRange.From) &&
+			aws.Int64Value(current.PortRange.To) == aws.Int64Value(desired.PortRange.To)
+	}
+
+	return true
+}
+
+func networkACLRole(public bool) string {
+	if public {
+		return infrav1.PublicRoleTagValue
+	}
+	return infrav1.PrivateRoleTagValue
+}
+
+func (s *Service) getNetworkACLTagParams(id string, public bool) infrav1.BuildParams {
+	role := networkACLRole(public)
+	name := fmt.Sprintf("%s-nacl-%s", s.scope.Name(), role)
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(role),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
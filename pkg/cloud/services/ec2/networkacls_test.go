@@ -0,0 +1,256 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcileNetworkACLs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ownedPrivateTags := []*ec2.Tag{
+		{Key: aws.String(infrav1.ClusterTagKey("test-cluster")), Value: aws.String("owned")},
+		{Key: aws.String(infrav1.NameAWSClusterAPIRole), Value: aws.String(infrav1.PrivateRoleTagValue)},
+	}
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name: "no network ACL, creates one and associates the private subnets",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkAcls(gomock.AssignableToTypeOf(&ec2.DescribeNetworkAclsInput{})).
+					Return(&ec2.DescribeNetworkAclsOutput{
+						NetworkAcls: []*ec2.NetworkAcl{
+							{
+								NetworkAclId: aws.String("acl-default"),
+								IsDefault:    aws.Bool(true),
+								Associations: []*ec2.NetworkAclAssociation{
+									{NetworkAclAssociationId: aws.String("aclassoc-1"), NetworkAclId: aws.String("acl-default"), SubnetId: aws.String("subnet-private")},
+									{NetworkAclAssociationId: aws.String("aclassoc-2"), NetworkAclId: aws.String("acl-default"), SubnetId: aws.String("subnet-public")},
+								},
+							},
+						},
+					}, nil)
+				m.CreateNetworkAcl(gomock.Eq(&ec2.CreateNetworkAclInput{VpcId: aws.String("vpc-nacl")})).
+					Return(&ec2.CreateNetworkAclOutput{
+						NetworkAcl: &ec2.NetworkAcl{
+							NetworkAclId: aws.String("acl-private"),
+							Entries: []*ec2.NetworkAclEntry{
+								{RuleNumber: aws.Int64(defaultNetworkACLRuleNumber), Egress: aws.Bool(false)},
+								{RuleNumber: aws.Int64(defaultNetworkACLRuleNumber), Egress: aws.Bool(true)},
+							},
+						},
+					}, nil)
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+				m.CreateNetworkAclEntry(gomock.Eq(&ec2.CreateNetworkAclEntryInput{
+					NetworkAclId: aws.String("acl-private"),
+					RuleNumber:   aws.Int64(100),
+					Egress:       aws.Bool(false),
+					Protocol:     aws.String("6"),
+					RuleAction:   aws.String("allow"),
+					CidrBlock:    aws.String("10.0.0.0/16"),
+					PortRange:    &ec2.PortRange{From: aws.Int64(0), To: aws.Int64(65535)},
+				})).
+					Return(&ec2.CreateNetworkAclEntryOutput{}, nil)
+				m.CreateNetworkAclEntry(gomock.Eq(&ec2.CreateNetworkAclEntryInput{
+					NetworkAclId: aws.String("acl-private"),
+					RuleNumber:   aws.Int64(100),
+					Egress:       aws.Bool(true),
+					Protocol:     aws.String("-1"),
+					RuleAction:   aws.String("allow"),
+					CidrBlock:    aws.String("0.0.0.0/0"),
+				})).
+					Return(&ec2.CreateNetworkAclEntryOutput{}, nil)
+				m.ReplaceNetworkAclAssociation(gomock.Eq(&ec2.ReplaceNetworkAclAssociationInput{
+					AssociationId: aws.String("aclassoc-1"),
+					NetworkAclId:  aws.String("acl-private"),
+				})).
+					Return(&ec2.ReplaceNetworkAclAssociationOutput{}, nil)
+			},
+		},
+		{
+			name: "existing network ACL, replaces changed rules and removes stale ones",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkAcls(gomock.AssignableToTypeOf(&ec2.DescribeNetworkAclsInput{})).
+					Return(&ec2.DescribeNetworkAclsOutput{
+						NetworkAcls: []*ec2.NetworkAcl{
+							{
+								NetworkAclId: aws.String("acl-private"),
+								Tags:         ownedPrivateTags,
+								Associations: []*ec2.NetworkAclAssociation{
+									{NetworkAclAssociationId: aws.String("aclassoc-1"), NetworkAclId: aws.String("acl-private"), SubnetId: aws.String("subnet-private")},
+								},
+								Entries: []*ec2.NetworkAclEntry{
+									{RuleNumber: aws.Int64(100), Egress: aws.Bool(false), Protocol: aws.String("6"), RuleAction: aws.String("allow"), CidrBlock: aws.String("10.0.0.0/8"), PortRange: &ec2.PortRange{From: aws.Int64(0), To: aws.Int64(65535)}},
+									{RuleNumber: aws.Int64(100), Egress: aws.Bool(true), Protocol: aws.String("-1"), RuleAction: aws.String("allow"), CidrBlock: aws.String("0.0.0.0/0")},
+									{RuleNumber: aws.Int64(200), Egress: aws.Bool(true), Protocol: aws.String("-1"), RuleAction: aws.String("deny"), CidrBlock: aws.String("0.0.0.0/0")},
+									{RuleNumber: aws.Int64(defaultNetworkACLRuleNumber), Egress: aws.Bool(true), Protocol: aws.String("-1"), RuleAction: aws.String("deny"), CidrBlock: aws.String("0.0.0.0/0")},
+								},
+							},
+						},
+					}, nil)
+				m.ReplaceNetworkAclEntry(gomock.AssignableToTypeOf(&ec2.ReplaceNetworkAclEntryInput{})).
+					Return(&ec2.ReplaceNetworkAclEntryOutput{}, nil)
+				m.DeleteNetworkAclEntry(gomock.Eq(&ec2.DeleteNetworkAclEntryInput{
+					NetworkAclId: aws.String("acl-private"),
+					RuleNumber:   aws.Int64(200),
+					Egress:       aws.Bool(true),
+				})).
+					Return(&ec2.DeleteNetworkAclEntryOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{
+								ID: "vpc-nacl",
+								Tags: infrav1.Tags{
+									infrav1.ClusterTagKey("test-cluster"): "owned",
+								},
+							},
+							Subnets: infrav1.Subnets{
+								{ID: "subnet-private", AvailabilityZone: "us-east-1a"},
+								{ID: "subnet-public", AvailabilityZone: "us-east-1a", IsPublic: true},
+							},
+							NetworkACLs: &infrav1.NetworkACLsSpec{
+								Private: &infrav1.NetworkACLSpec{
+									Ingress: []infrav1.NetworkACLRule{
+										{RuleNumber: 100, Protocol: infrav1.SecurityGroupProtocolTCP, Action: infrav1.NetworkACLRuleActionAllow, CidrBlock: "10.0.0.0/16", FromPort: 0, ToPort: 65535},
+									},
+									Egress: []infrav1.NetworkACLRule{
+										{RuleNumber: 100, Protocol: infrav1.SecurityGroupProtocolAll, Action: infrav1.NetworkACLRuleActionAllow, CidrBlock: "0.0.0.0/0"},
+									},
+								},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			if err := s.reconcileNetworkACLs(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestReconcileNetworkACLsRemoved(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					VPC: infrav1.VPCSpec{
+						ID: "vpc-nacl",
+						Tags: infrav1.Tags{
+							infrav1.ClusterTagKey("test-cluster"): "owned",
+						},
+					},
+					Subnets: infrav1.Subnets{
+						{ID: "subnet-private", AvailabilityZone: "us-east-1a"},
+						{ID: "subnet-public", AvailabilityZone: "us-east-1a", IsPublic: true},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	ec2Mock.EXPECT().DescribeNetworkAcls(gomock.AssignableToTypeOf(&ec2.DescribeNetworkAclsInput{})).
+		Return(&ec2.DescribeNetworkAclsOutput{
+			NetworkAcls: []*ec2.NetworkAcl{
+				{
+					NetworkAclId: aws.String("acl-default"),
+					IsDefault:    aws.Bool(true),
+					Associations: []*ec2.NetworkAclAssociation{
+						{NetworkAclAssociationId: aws.String("aclassoc-2"), NetworkAclId: aws.String("acl-default"), SubnetId: aws.String("subnet-public")},
+					},
+				},
+				{
+					NetworkAclId: aws.String("acl-private"),
+					Tags: []*ec2.Tag{
+						{Key: aws.String(infrav1.ClusterTagKey("test-cluster")), Value: aws.String("owned")},
+						{Key: aws.String(infrav1.NameAWSClusterAPIRole), Value: aws.String(infrav1.PrivateRoleTagValue)},
+					},
+					Associations: []*ec2.NetworkAclAssociation{
+						{NetworkAclAssociationId: aws.String("aclassoc-1"), NetworkAclId: aws.String("acl-private"), SubnetId: aws.String("subnet-private")},
+					},
+				},
+			},
+		}, nil)
+	gomock.InOrder(
+		ec2Mock.EXPECT().ReplaceNetworkAclAssociation(gomock.Eq(&ec2.ReplaceNetworkAclAssociationInput{
+			AssociationId: aws.String("aclassoc-1"),
+			NetworkAclId:  aws.String("acl-default"),
+		})).
+			Return(&ec2.ReplaceNetworkAclAssociationOutput{}, nil),
+		ec2Mock.EXPECT().DeleteNetworkAcl(gomock.Eq(&ec2.DeleteNetworkAclInput{
+			NetworkAclId: aws.String("acl-private"),
+		})).
+			Return(&ec2.DeleteNetworkAclOutput{}, nil),
+	)
+
+	s := NewService(scope)
+	if err := s.reconcileNetworkACLs(); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
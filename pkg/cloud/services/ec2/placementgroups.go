@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// placementGroupRoleTagValue describes the value for the placement group role
+	placementGroupRoleTagValue = "placement-group"
+)
+
+// reconcilePlacementGroup makes sure the placement group exists, creating it with the requested
+// strategy when it doesn't. Placement groups created here are owned by the cluster.
+func (s *Service) reconcilePlacementGroup(spec *infrav1.PlacementGroup) error {
+	s.scope.V(2).Info("Reconciling placement group", "placement-group", spec.Name)
+
+	pg, err := s.describePlacementGroup(spec.Name)
+	if err != nil {
+		return err
+	}
+
+	if pg != nil {
+		if spec.Strategy != "" && aws.StringValue(pg.Strategy) != spec.Strategy {
+			return errors.Errorf("placement group %q has strategy %q, expected %q", spec.Name, aws.StringValue(pg.Strategy), spec.Strategy)
+		}
+		return nil
+	}
+
+	if spec.Strategy == "" {
+		return errors.Errorf("failed to create placement group %q, no strategy set", spec.Name)
+	}
+
+	input := &ec2.CreatePlacementGroupInput{
+		GroupName: aws.String(spec.Name),
+		Strategy:  aws.String(spec.Strategy),
+	}
+	if spec.Strategy == ec2.PlacementStrategyPartition {
+		input.PartitionCount = spec.PartitionCount
+	}
+
+	if _, err := s.scope.EC2.CreatePlacementGroup(input); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreatePlacementGroup", "Failed to create placement group %q: %v", spec.Name, err)
+		return errors.Wrapf(err, "failed to create placement group %q", spec.Name)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreatePlacementGroup", "Created new placement group %q", spec.Name)
+
+	// Placement groups can't be tagged on creation, their ID is only known once they're described.
+	pg, err = s.describePlacementGroup(spec.Name)
+	if err != nil {
+		return err
+	}
+	if pg == nil {
+		return errors.Errorf("failed to find placement group %q after creating it", spec.Name)
+	}
+
+	if err := tags.Apply(&tags.ApplyParams{
+		EC2Client:   s.scope.EC2,
+		BuildParams: s.getPlacementGroupTagParams(aws.StringValue(pg.GroupId), spec.Name),
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedTagPlacementGroup", "Failed to tag managed placement group %q: %v", spec.Name, err)
+		return errors.Wrapf(err, "failed to tag placement group %q", spec.Name)
+	}
+
+	s.scope.V(2).Info("Created placement group", "placement-group", spec.Name, "strategy", spec.Strategy)
+	return nil
+}
+
+// DeletePlacementGroups deletes the placement groups owned by the cluster.
+func (s *Service) DeletePlacementGroups() error {
+	out, err := s.scope.EC2.DescribePlacementGroups(&ec2.DescribePlacementGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ProviderOwned(s.scope.Name()),
+			filter.EC2.ProviderRole(placementGroupRoleTagValue),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to describe placement groups")
+	}
+
+	for _, pg := range out.PlacementGroups {
+		name := aws.StringValue(pg.GroupName)
+		if _, err := s.scope.EC2.DeletePlacementGroup(&ec2.DeletePlacementGroupInput{
+			GroupName: pg.GroupName,
+		}); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedDeletePlacementGroup", "Failed to delete managed placement group %q: %v", name, err)
+			return errors.Wrapf(err, "failed to delete placement group %q", name)
+		}
+
+		record.Eventf(s.scope.AWSCluster, "SuccessfulDeletePlacementGroup", "Deleted managed placement group %q", name)
+		s.scope.V(2).Info("Deleted placement group", "placement-group", name)
+	}
+
+	return nil
+}
+
+// describePlacementGroup returns the placement group with the given name, or nil if it doesn't exist.
+func (s *Service) describePlacementGroup(name string) (*ec2.PlacementGroup, error) {
+	out, err := s.scope.EC2.DescribePlacementGroups(&ec2.DescribePlacementGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("group-name"),
+				Values: aws.StringSlice([]string{name}),
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe placement group %q", name)
+	}
+
+	if len(out.PlacementGroups) == 0 {
+		return nil, nil
+	}
+
+	return out.PlacementGroups[0], nil
+}
+
+func (s *Service) getPlacementGroupTagParams(id, name string) infrav1.BuildParams {
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(placementGroupRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
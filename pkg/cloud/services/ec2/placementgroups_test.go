@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcilePlacementGroup(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	describeInput := &ec2.DescribePlacementGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("group-name"),
+				Values: aws.StringSlice([]string{"hpc"}),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		spec      *infrav1.PlacementGroup
+		expect    func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name: "creates and tags a missing placement group",
+			spec: &infrav1.PlacementGroup{
+				Name:           "hpc",
+				Strategy:       "partition",
+				PartitionCount: aws.Int64(3),
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				gomock.InOrder(
+					m.DescribePlacementGroups(gomock.Eq(describeInput)).
+						Return(&ec2.DescribePlacementGroupsOutput{}, nil),
+					m.CreatePlacementGroup(gomock.Eq(&ec2.CreatePlacementGroupInput{
+						GroupName:      aws.String("hpc"),
+						Strategy:       aws.String("partition"),
+						PartitionCount: aws.Int64(3),
+					})).
+						Return(&ec2.CreatePlacementGroupOutput{}, nil),
+					m.DescribePlacementGroups(gomock.Eq(describeInput)).
+						Return(&ec2.DescribePlacementGroupsOutput{
+							PlacementGroups: []*ec2.PlacementGroup{
+								{
+									GroupId:   aws.String("pg-1"),
+									GroupName: aws.String("hpc"),
+									Strategy:  aws.String("partition"),
+								},
+							},
+						}, nil),
+					m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+						Return(&ec2.CreateTagsOutput{}, nil),
+				)
+			},
+		},
+		{
+			name: "uses an existing placement group",
+			spec: &infrav1.PlacementGroup{
+				Name: "hpc",
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.Eq(describeInput)).
+					Return(&ec2.DescribePlacementGroupsOutput{
+						PlacementGroups: []*ec2.PlacementGroup{
+							{
+								GroupId:   aws.String("pg-1"),
+								GroupName: aws.String("hpc"),
+								Strategy:  aws.String("cluster"),
+							},
+						},
+					}, nil)
+			},
+		},
+		{
+			name: "fails when an existing placement group has a different strategy",
+			spec: &infrav1.PlacementGroup{
+				Name:     "hpc",
+				Strategy: "spread",
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.Eq(describeInput)).
+					Return(&ec2.DescribePlacementGroupsOutput{
+						PlacementGroups: []*ec2.PlacementGroup{
+							{
+								GroupId:   aws.String("pg-1"),
+								GroupName: aws.String("hpc"),
+								Strategy:  aws.String("cluster"),
+							},
+						},
+					}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name: "fails when a missing placement group has no strategy",
+			spec: &infrav1.PlacementGroup{
+				Name: "hpc",
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.Eq(describeInput)).
+					Return(&ec2.DescribePlacementGroupsOutput{}, nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			err = s.reconcilePlacementGroup(tc.spec)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -54,18 +54,24 @@ func (s *Service) reconcileRouteTables() error {
 		// We need to compile the minimum routes for this subnet first, so we can compare it or create them.
 		var routes []*ec2.Route
 		sn := s.scope.Subnets()[i]
-		if sn.IsPublic {
+		if sn.LocalGatewayID != nil {
+			routes = append(routes, s.getLocalGatewayRoute(*sn.LocalGatewayID))
+		} else if sn.IsPublic {
 			if s.scope.VPC().InternetGatewayID == nil {
 				return errors.Errorf("failed to create routing tables: internet gateway for %q is nil", s.scope.VPC().ID)
 			}
 			routes = append(routes, s.getGatewayPublicRoute())
-		} else {
+		} else if s.natGatewayStrategy() != infrav1.NatGatewayStrategyNone {
 			natGatewayID, err := s.getNatGatewayForSubnet(sn)
 			if err != nil {
 				return err
 			}
 			routes = append(routes, s.getNatGatewayPrivateRoute(natGatewayID))
 		}
+		routes = append(routes, s.getTransitGatewayRoutes()...)
+		routes = append(routes, s.getVPCPeeringRoutes()...)
+
+		routes = append(routes, getAdditionalRoutes(sn)...)
 
 		if rt, ok := subnetRouteMap[sn.ID]; ok {
 			s.scope.V(2).Info("Subnet is already associated with route table", "subnet-id", sn.ID, "route-table-id", *rt.RouteTableId)
@@ -73,31 +79,50 @@ func (s *Service) reconcileRouteTables() error {
 
 			// For managed environments we need to reconcile the routes of our tables if there is a mistmatch.
 			// For example, a gateway can be deleted and our controller will re-create it, then we replace the route
-			// for the subnet to allow traffic to flow.
-			for _, currentRoute := range rt.Routes {
-				for i := range routes {
-					// Routes destination cidr blocks must be unique within a routing table.
-					// If there is a mistmatch, we replace the routing association.
-					specRoute := routes[i]
-					if *currentRoute.DestinationCidrBlock == *specRoute.DestinationCidrBlock &&
-						((currentRoute.GatewayId != nil && *currentRoute.GatewayId != *specRoute.GatewayId) ||
-							(currentRoute.NatGatewayId != nil && *currentRoute.NatGatewayId != *specRoute.NatGatewayId)) {
-
-						if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-							if _, err := s.scope.EC2.ReplaceRoute(&ec2.ReplaceRouteInput{
-								RouteTableId:         rt.RouteTableId,
-								DestinationCidrBlock: specRoute.DestinationCidrBlock,
-								GatewayId:            specRoute.GatewayId,
-								NatGatewayId:         specRoute.NatGatewayId,
-							}); err != nil {
-								return false, err
-							}
-							return true, nil
-						}); err != nil {
-							record.Warnf(s.scope.AWSCluster, "FailedReplaceRoute", "Failed to replace outdated route on managed RouteTable %q: %v", *rt.RouteTableId, err)
-							return errors.Wrapf(err, "failed to replace outdated route on route table %q", *rt.RouteTableId)
-						}
+			// for the subnet to allow traffic to flow. Routes missing from the table, e.g. routes to a transit gateway
+			// or peering connection configured after the table was created, are added.
+			for i := range routes {
+				// Routes destination cidr blocks must be unique within a routing table.
+				// If there is a mistmatch, we replace the routing association.
+				specRoute := routes[i]
+				currentRoute := findRouteByDestination(rt.Routes, *specRoute.DestinationCidrBlock)
+				if currentRoute == nil {
+					if err := s.createRoute(rt.RouteTableId, specRoute); err != nil {
+						return err
 					}
+					continue
+				}
+
+				if routeTargetsMatch(currentRoute, specRoute) {
+					continue
+				}
+
+				if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+					if _, err := s.scope.EC2.ReplaceRoute(&ec2.ReplaceRouteInput{
+						RouteTableId:           rt.RouteTableId,
+						DestinationCidrBlock:   specRoute.DestinationCidrBlock,
+						GatewayId:              specRoute.GatewayId,
+						NatGatewayId:           specRoute.NatGatewayId,
+						TransitGatewayId:       specRoute.TransitGatewayId,
+						LocalGatewayId:         specRoute.LocalGatewayId,
+						InstanceId:             specRoute.InstanceId,
+						VpcPeeringConnectionId: specRoute.VpcPeeringConnectionId,
+					}); err != nil {
+						return false, err
+					}
+					return true, nil
+				}); err != nil {
+					record.Warnf(s.scope.AWSCluster, "FailedReplaceRoute", "Failed to replace outdated route on managed RouteTable %q: %v", *rt.RouteTableId, err)
+					return errors.Wrapf(err, "failed to replace outdated route on route table %q", *rt.RouteTableId)
+				}
+			}
+
+			// Without NAT gateways, the default route of private subnets through a NAT gateway is removed so
+			// that the gateway can be deleted.
+			if !sn.IsPublic && sn.LocalGatewayID == nil && s.natGatewayStrategy() == infrav1.NatGatewayStrategyNone &&
+				findRouteByDestination(routes, anyIPv4CidrBlock) == nil {
+				if err := s.deleteNatGatewayPrivateRoute(rt); err != nil {
+					return err
 				}
 			}
 
@@ -142,6 +167,25 @@ func (s *Service) reconcileRouteTables() error {
 	return nil
 }
 
+// deleteNatGatewayPrivateRoute deletes the default route through a NAT gateway from the route table, if any.
+func (s *Service) deleteNatGatewayPrivateRoute(rt *ec2.RouteTable) error {
+	route := findRouteByDestination(rt.Routes, anyIPv4CidrBlock)
+	if route == nil || route.NatGatewayId == nil {
+		return nil
+	}
+
+	if _, err := s.scope.EC2.DeleteRoute(&ec2.DeleteRouteInput{
+		RouteTableId:         rt.RouteTableId,
+		DestinationCidrBlock: route.DestinationCidrBlock,
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteRoute", "Failed to delete route to %q through NAT Gateway %q from RouteTable %q: %v", anyIPv4CidrBlock, aws.StringValue(route.NatGatewayId), *rt.RouteTableId, err)
+		return errors.Wrapf(err, "failed to delete route to %q from route table %q", anyIPv4CidrBlock, *rt.RouteTableId)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteRoute", "Deleted route to %q through NAT Gateway %q from RouteTable %q", anyIPv4CidrBlock, aws.StringValue(route.NatGatewayId), *rt.RouteTableId)
+	return nil
+}
+
 func (s *Service) describeVpcRouteTablesBySubnet() (map[string]*ec2.RouteTable, error) {
 	rts, err := s.describeVpcRouteTables()
 	if err != nil {
@@ -249,28 +293,10 @@ func (s *Service) createRouteTableWithRoutes(routes []*ec2.Route, isPublic bool)
 	record.Eventf(s.scope.AWSCluster, "SuccessfulTagRouteTable", "Tagged managed RouteTable %q", *out.RouteTable.RouteTableId)
 
 	for i := range routes {
-		route := routes[i]
-		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-			if _, err := s.scope.EC2.CreateRoute(&ec2.CreateRouteInput{
-				RouteTableId:                out.RouteTable.RouteTableId,
-				DestinationCidrBlock:        route.DestinationCidrBlock,
-				DestinationIpv6CidrBlock:    route.DestinationIpv6CidrBlock,
-				EgressOnlyInternetGatewayId: route.EgressOnlyInternetGatewayId,
-				GatewayId:                   route.GatewayId,
-				InstanceId:                  route.InstanceId,
-				NatGatewayId:                route.NatGatewayId,
-				NetworkInterfaceId:          route.NetworkInterfaceId,
-				VpcPeeringConnectionId:      route.VpcPeeringConnectionId,
-			}); err != nil {
-				return false, err
-			}
-			return true, nil
-		}, awserrors.RouteTableNotFound, awserrors.NATGatewayNotFound, awserrors.GatewayNotFound); err != nil {
+		if err := s.createRoute(out.RouteTable.RouteTableId, routes[i]); err != nil {
 			// TODO(vincepri): cleanup the route table if this fails.
-			record.Warnf(s.scope.AWSCluster, "FailedCreateRoute", "Failed to create route %s for RouteTable %q: %v", route.GoString(), *out.RouteTable.RouteTableId, err)
-			return nil, errors.Wrapf(err, "failed to create route in route table %q: %s", *out.RouteTable.RouteTableId, route.GoString())
+			return nil, err
 		}
-		record.Eventf(s.scope.AWSCluster, "SuccessfulCreateRoute", "Created route %s for RouteTable %q", route.GoString(), *out.RouteTable.RouteTableId)
 	}
 
 	return &infrav1.RouteTable{
@@ -278,6 +304,67 @@ func (s *Service) createRouteTableWithRoutes(routes []*ec2.Route, isPublic bool)
 	}, nil
 }
 
+func (s *Service) createRoute(routeTableID *string, route *ec2.Route) error {
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.scope.EC2.CreateRoute(&ec2.CreateRouteInput{
+			RouteTableId:                routeTableID,
+			DestinationCidrBlock:        route.DestinationCidrBlock,
+			DestinationIpv6CidrBlock:    route.DestinationIpv6CidrBlock,
+			EgressOnlyInternetGatewayId: route.EgressOnlyInternetGatewayId,
+			GatewayId:                   route.GatewayId,
+			InstanceId:                  route.InstanceId,
+			LocalGatewayId:              route.LocalGatewayId,
+			NatGatewayId:                route.NatGatewayId,
+			NetworkInterfaceId:          route.NetworkInterfaceId,
+			TransitGatewayId:            route.TransitGatewayId,
+			VpcPeeringConnectionId:      route.VpcPeeringConnectionId,
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.RouteTableNotFound, awserrors.NATGatewayNotFound, awserrors.GatewayNotFound); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreateRoute", "Failed to create route %s for RouteTable %q: %v", route.GoString(), *routeTableID, err)
+		return errors.Wrapf(err, "failed to create route in route table %q: %s", *routeTableID, route.GoString())
+	}
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreateRoute", "Created route %s for RouteTable %q", route.GoString(), *routeTableID)
+	return nil
+}
+
+func findRouteByDestination(routes []*ec2.Route, destinationCidrBlock string) *ec2.Route {
+	for _, route := range routes {
+		if aws.StringValue(route.DestinationCidrBlock) == destinationCidrBlock {
+			return route
+		}
+	}
+	return nil
+}
+
+// routeTargetsMatch returns true if the current route points to the same target as the spec route.
+func routeTargetsMatch(current, spec *ec2.Route) bool {
+	return routeTarget(current) == routeTarget(spec)
+}
+
+// routeTarget returns the target of a route. Routes to an instance are described with the id of its
+// network interface as well, the instance id is therefore checked first.
+func routeTarget(route *ec2.Route) string {
+	targets := []*string{
+		route.GatewayId,
+		route.NatGatewayId,
+		route.TransitGatewayId,
+		route.LocalGatewayId,
+		route.VpcPeeringConnectionId,
+		route.InstanceId,
+		route.NetworkInterfaceId,
+		route.EgressOnlyInternetGatewayId,
+	}
+	for _, target := range targets {
+		if target != nil {
+			return *target
+		}
+	}
+	return ""
+}
+
 func (s *Service) associateRouteTable(rt *infrav1.RouteTable, subnetID string) error {
 	_, err := s.scope.EC2.AssociateRouteTable(&ec2.AssociateRouteTableInput{
 		RouteTableId: aws.String(rt.ID),
@@ -307,6 +394,30 @@ func (s *Service) getGatewayPublicRoute() *ec2.Route {
 	}
 }
 
+func (s *Service) getLocalGatewayRoute(localGatewayID string) *ec2.Route {
+	return &ec2.Route{
+		DestinationCidrBlock: aws.String(anyIPv4CidrBlock),
+		LocalGatewayId:       aws.String(localGatewayID),
+	}
+}
+
+// getAdditionalRoutes returns the routes configured on the subnet in addition to the default ones.
+// The AWSCluster webhook ensures each route has exactly one target.
+func getAdditionalRoutes(sn *infrav1.SubnetSpec) []*ec2.Route {
+	routes := make([]*ec2.Route, 0, len(sn.AdditionalRoutes))
+	for _, r := range sn.AdditionalRoutes {
+		routes = append(routes, &ec2.Route{
+			DestinationCidrBlock:   aws.String(r.DestinationCidrBlock),
+			TransitGatewayId:       r.TransitGatewayID,
+			VpcPeeringConnectionId: r.VPCPeeringConnectionID,
+			InstanceId:             r.InstanceID,
+			NatGatewayId:           r.NatGatewayID,
+		})
+	}
+
+	return routes
+}
+
 func (s *Service) getRouteTableTagParams(id string, public bool) infrav1.BuildParams {
 	var name strings.Builder
 
@@ -17,6 +17,7 @@ limitations under the License.
 package ec2
 
 import (
+	"encoding/json"
 	"fmt"
 
 	errlist "k8s.io/apimachinery/pkg/util/errors"
@@ -45,8 +46,20 @@ const (
 
 	// IPProtocolICMPv6 is how EC2 represents the ICMPv6 protocol in ingress rules
 	IPProtocolICMPv6 = "58"
+
+	// SecurityGroupRulesLastAppliedAnnotation is the key for the AWSCluster annotation which tracks
+	// the rules last applied to the managed security groups when drift remediation is enabled.
+	// Rules that differ from the declared state but not from the last applied state are spec changes,
+	// only the other differences are reported as drift.
+	SecurityGroupRulesLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-aws-last-applied-security-group-rules"
 )
 
+// securityGroupRules are the rules applied to a security group.
+type securityGroupRules struct {
+	Ingress infrav1.IngressRules `json:"ingress,omitempty"`
+	Egress  infrav1.IngressRules `json:"egress,omitempty"`
+}
+
 func (s *Service) reconcileSecurityGroups() error {
 	s.scope.V(2).Info("Reconciling security groups")
 
@@ -67,6 +80,22 @@ func (s *Service) reconcileSecurityGroups() error {
 		infrav1.SecurityGroupControlPlane,
 		infrav1.SecurityGroupNode,
 	}
+	if s.privateEndpointsEnabled() {
+		roles = append(roles, infrav1.SecurityGroupVPCEndpoint)
+	}
+
+	// Security groups created in this iteration have no rules yet, missing rules are not drift.
+	created := map[infrav1.SecurityGroupRole]bool{}
+	driftRemediation := s.scope.SecurityGroupDriftRemediationInterval() > 0
+
+	lastApplied := map[infrav1.SecurityGroupRole]securityGroupRules{}
+	if driftRemediation {
+		lastApplied, err = s.lastAppliedSecurityGroupRules()
+		if err != nil {
+			return err
+		}
+	}
+	applied := map[infrav1.SecurityGroupRole]securityGroupRules{}
 
 	// First iteration makes sure that the security group are valid and fully created.
 	for i := range roles {
@@ -84,6 +113,7 @@ func (s *Service) reconcileSecurityGroups() error {
 				Name: *sg.GroupName,
 			}
 			s.scope.V(2).Info("Created security group for role", "role", role, "security-group", s.scope.SecurityGroups()[role])
+			created[role] = true
 			continue
 		}
 
@@ -119,6 +149,13 @@ func (s *Service) reconcileSecurityGroups() error {
 			return err
 		}
 
+		// Drift can only be told apart from spec changes once rules were applied with drift remediation enabled.
+		last, known := lastApplied[i]
+		detectDrift := driftRemediation && known && !created[i]
+		if detectDrift {
+			s.recordSecurityGroupDrift(sg.ID, "ingress", current, want, last.Ingress)
+		}
+
 		toRevoke := current.Difference(want)
 		if len(toRevoke) > 0 {
 			if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
@@ -146,11 +183,129 @@ func (s *Service) reconcileSecurityGroups() error {
 
 			s.scope.V(2).Info("Authorized ingress rules in security group", "authorized-ingress-rules", toAuthorize, "security-group-id", sg.ID)
 		}
+
+		rules := securityGroupRules{Ingress: want}
+		// New security groups come with the default egress rule, which only has to be restored on existing
+		// node security groups once restricted egress rules are removed from the spec.
+		if i == infrav1.SecurityGroupNode && (s.nodeEgressRulesEnabled() || !created[i]) {
+			rules.Egress = s.getNodeEgressRules()
+
+			var lastEgress infrav1.IngressRules
+			if detectDrift {
+				lastEgress = last.Egress
+			}
+			if err := s.reconcileSecurityGroupEgressRules(sg.ID, rules.Egress, lastEgress); err != nil {
+				return err
+			}
+		}
+		applied[i] = rules
+	}
+
+	if driftRemediation {
+		return s.setLastAppliedSecurityGroupRules(applied)
 	}
 
 	return nil
 }
 
+// reconcileSecurityGroupEgressRules replaces the egress rules of the security group with the given rules.
+// When the rules last applied to the security group are given, differences from them are reported as drift.
+func (s *Service) reconcileSecurityGroupEgressRules(id string, want, lastApplied infrav1.IngressRules) error {
+	current, err := s.describeSecurityGroupEgressRules(id)
+	if err != nil {
+		return err
+	}
+
+	if lastApplied != nil {
+		s.recordSecurityGroupDrift(id, "egress", current, want, lastApplied)
+	}
+
+	toRevoke := current.Difference(want)
+	if len(toRevoke) > 0 {
+		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+			if err := s.revokeSecurityGroupEgressRules(id, toRevoke); err != nil {
+				return false, err
+			}
+			return true, nil
+		}, awserrors.GroupNotFound); err != nil {
+			return errors.Wrapf(err, "failed to revoke security group egress rules for %q", id)
+		}
+
+		s.scope.V(2).Info("Revoked egress rules from security group", "revoked-egress-rules", toRevoke, "security-group-id", id)
+	}
+
+	toAuthorize := want.Difference(current)
+	if len(toAuthorize) > 0 {
+		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+			if err := s.authorizeSecurityGroupEgressRules(id, toAuthorize); err != nil {
+				return false, err
+			}
+			return true, nil
+		}, awserrors.GroupNotFound); err != nil {
+			return err
+		}
+
+		s.scope.V(2).Info("Authorized egress rules in security group", "authorized-egress-rules", toAuthorize, "security-group-id", id)
+	}
+
+	return nil
+}
+
+// recordSecurityGroupDrift emits an event for the rules changed out-of-band since the last reconcile.
+func (s *Service) recordSecurityGroupDrift(id, direction string, current, want, lastApplied infrav1.IngressRules) {
+	added, removed := securityGroupDrift(current, want, lastApplied)
+	if len(added) > 0 {
+		record.Warnf(s.scope.AWSCluster, "SecurityGroupDrift", "Revoking %s rules added out-of-band to security group %q: %v", direction, id, added)
+	}
+	if len(removed) > 0 {
+		record.Warnf(s.scope.AWSCluster, "SecurityGroupDrift", "Restoring %s rules removed out-of-band from security group %q: %v", direction, id, removed)
+	}
+}
+
+// securityGroupDrift returns the rules added and removed out-of-band. Rules removed from or added to
+// the spec since they were last applied are changes of the declared state, not drift.
+func securityGroupDrift(current, want, lastApplied infrav1.IngressRules) (added, removed infrav1.IngressRules) {
+	added = current.Difference(want).Difference(lastApplied)
+
+	missing := want.Difference(current)
+	removed = missing.Difference(missing.Difference(lastApplied))
+
+	return added, removed
+}
+
+// lastAppliedSecurityGroupRules returns the rules last applied to the managed security groups.
+func (s *Service) lastAppliedSecurityGroupRules() (map[infrav1.SecurityGroupRole]securityGroupRules, error) {
+	out := map[infrav1.SecurityGroupRole]securityGroupRules{}
+
+	annotation := s.scope.AWSCluster.GetAnnotations()[SecurityGroupRulesLastAppliedAnnotation]
+	if annotation == "" {
+		return out, nil
+	}
+
+	if err := json.Unmarshal([]byte(annotation), &out); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse annotation %q", SecurityGroupRulesLastAppliedAnnotation)
+	}
+
+	return out, nil
+}
+
+// setLastAppliedSecurityGroupRules stores the rules applied to the managed security groups.
+func (s *Service) setLastAppliedSecurityGroupRules(rules map[infrav1.SecurityGroupRole]securityGroupRules) error {
+	b, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	annotations := s.scope.AWSCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[SecurityGroupRulesLastAppliedAnnotation] = string(b)
+	s.scope.AWSCluster.SetAnnotations(annotations)
+
+	return nil
+}
+
 func (s *Service) deleteSecurityGroups() error {
 	for _, sg := range s.scope.SecurityGroups() {
 		current := sg.IngressRules
@@ -159,6 +314,13 @@ func (s *Service) deleteSecurityGroups() error {
 			return err
 		}
 
+		// Restricted egress rules reference the other cluster security groups, which blocks their deletion.
+		if s.nodeEgressRulesEnabled() {
+			if err := s.revokeAllSecurityGroupEgressRules(sg.ID); awserrors.IsIgnorableSecurityGroupError(err) != nil {
+				return err
+			}
+		}
+
 		s.scope.V(2).Info("Revoked ingress rules from security group", "revoked-ingress-rules", current, "security-group-id", sg.ID)
 	}
 
@@ -355,6 +517,108 @@ func (s *Service) revokeAllSecurityGroupIngressRules(id string) error {
 	return nil
 }
 
+func (s *Service) describeSecurityGroupEgressRules(id string) (infrav1.IngressRules, error) {
+	out, err := s.scope.EC2.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{GroupIds: []*string{aws.String(id)}})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query security group %q", id)
+	}
+
+	var rules infrav1.IngressRules
+	for _, sg := range out.SecurityGroups {
+		for _, ec2rule := range sg.IpPermissionsEgress {
+			rules = append(rules, ingressRuleFromSDKType(ec2rule))
+		}
+	}
+
+	return rules, nil
+}
+
+func (s *Service) authorizeSecurityGroupEgressRules(id string, rules infrav1.IngressRules) error {
+	input := &ec2.AuthorizeSecurityGroupEgressInput{GroupId: aws.String(id)}
+	for _, rule := range rules {
+		input.IpPermissions = append(input.IpPermissions, ingressRuleToSDKType(rule))
+	}
+
+	if _, err := s.scope.EC2.AuthorizeSecurityGroupEgress(input); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedAuthorizeSecurityGroupEgressRules", "Failed to authorize security group egress rules %v for SecurityGroup %q: %v", rules, id, err)
+		return errors.Wrapf(err, "failed to authorize security group %q egress rules: %v", id, rules)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulAuthorizeSecurityGroupEgressRules", "Authorized security group egress rules %v for SecurityGroup %q", rules, id)
+	return nil
+}
+
+func (s *Service) revokeSecurityGroupEgressRules(id string, rules infrav1.IngressRules) error {
+	input := &ec2.RevokeSecurityGroupEgressInput{GroupId: aws.String(id)}
+	for _, rule := range rules {
+		input.IpPermissions = append(input.IpPermissions, ingressRuleToSDKType(rule))
+	}
+
+	if _, err := s.scope.EC2.RevokeSecurityGroupEgress(input); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedRevokeSecurityGroupEgressRules", "Failed to revoke security group egress rules %v for SecurityGroup %q: %v", rules, id, err)
+		return errors.Wrapf(err, "failed to revoke security group %q egress rules: %v", id, rules)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulRevokeSecurityGroupEgressRules", "Revoked security group egress rules %v for SecurityGroup %q", rules, id)
+	return nil
+}
+
+func (s *Service) revokeAllSecurityGroupEgressRules(id string) error {
+	describeInput := &ec2.DescribeSecurityGroupsInput{GroupIds: []*string{aws.String(id)}}
+
+	securityGroups, err := s.scope.EC2.DescribeSecurityGroups(describeInput)
+	if err != nil {
+		return errors.Wrapf(err, "failed to query security group %q", id)
+	}
+
+	for _, sg := range securityGroups.SecurityGroups {
+		if len(sg.IpPermissionsEgress) > 0 {
+			revokeInput := &ec2.RevokeSecurityGroupEgressInput{
+				GroupId:       aws.String(id),
+				IpPermissions: sg.IpPermissionsEgress,
+			}
+			if _, err := s.scope.EC2.RevokeSecurityGroupEgress(revokeInput); err != nil {
+				record.Warnf(s.scope.AWSCluster, "FailedRevokeSecurityGroupEgressRules", "Failed to revoke all security group egress rules for SecurityGroup %q: %v", *sg.GroupId, err)
+				return errors.Wrapf(err, "failed to revoke security group %q egress rules", id)
+			}
+			record.Eventf(s.scope.AWSCluster, "SuccessfulRevokeSecurityGroupEgressRules", "Revoked all security group egress rules for SecurityGroup %q", *sg.GroupId)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) nodeEgressRulesEnabled() bool {
+	return len(s.scope.NetworkSpec().NodeEgressRules) > 0
+}
+
+// getNodeEgressRules returns the egress rules of the node security group. Without restricted egress rules
+// this is the default rule of security groups allowing all outbound traffic. Otherwise traffic to the cluster
+// security groups is always allowed so nodes can reach the API server and each other.
+func (s *Service) getNodeEgressRules() infrav1.IngressRules {
+	if !s.nodeEgressRulesEnabled() {
+		return infrav1.IngressRules{
+			{
+				Protocol:   infrav1.SecurityGroupProtocolAll,
+				CidrBlocks: []string{anyIPv4CidrBlock},
+			},
+		}
+	}
+
+	rules := infrav1.IngressRules{
+		{
+			Description: "Kubernetes cluster",
+			Protocol:    infrav1.SecurityGroupProtocolAll,
+			SourceSecurityGroupIDs: []string{
+				s.scope.SecurityGroups()[infrav1.SecurityGroupAPIServerLB].ID,
+				s.scope.SecurityGroups()[infrav1.SecurityGroupControlPlane].ID,
+				s.scope.SecurityGroups()[infrav1.SecurityGroupNode].ID,
+			},
+		},
+	}
+	return append(rules, s.scope.NetworkSpec().NodeEgressRules...)
+}
+
 func (s *Service) defaultSSHIngressRule(sourceSecurityGroupID string) *infrav1.IngressRule {
 	return &infrav1.IngressRule{
 		Description:            "SSH",
@@ -368,6 +632,10 @@ func (s *Service) defaultSSHIngressRule(sourceSecurityGroupID string) *infrav1.I
 func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (infrav1.IngressRules, error) {
 	switch role {
 	case infrav1.SecurityGroupBastion:
+		// Instances are reached through Session Manager, nothing is exposed over SSH.
+		if s.scope.AWSCluster.Spec.SessionManager.Enabled {
+			return infrav1.IngressRules{}, nil
+		}
 		return infrav1.IngressRules{
 			{
 				Description: "SSH",
@@ -378,13 +646,13 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 			},
 		}, nil
 	case infrav1.SecurityGroupControlPlane:
-		return infrav1.IngressRules{
+		rules := infrav1.IngressRules{
 			s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID),
 			{
 				Description: "Kubernetes API",
 				Protocol:    infrav1.SecurityGroupProtocolTCP,
-				FromPort:    6443,
-				ToPort:      6443,
+				FromPort:    int64(s.scope.APIServerInstancePort()),
+				ToPort:      int64(s.scope.APIServerInstancePort()),
 				SourceSecurityGroupIDs: []string{
 					s.scope.SecurityGroups()[infrav1.SecurityGroupAPIServerLB].ID,
 					s.scope.SecurityGroups()[infrav1.SecurityGroupControlPlane].ID,
@@ -425,10 +693,11 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 					s.scope.SecurityGroups()[infrav1.SecurityGroupNode].ID,
 				},
 			},
-		}, nil
+		}
+		return append(rules, s.scope.NetworkSpec().AdditionalControlPlaneIngressRules...), nil
 
 	case infrav1.SecurityGroupNode:
-		return infrav1.IngressRules{
+		rules := infrav1.IngressRules{
 			s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID),
 			{
 				Description: "Node Port Services",
@@ -468,7 +737,15 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 					s.scope.SecurityGroups()[infrav1.SecurityGroupControlPlane].ID,
 				},
 			},
-		}, nil
+		}
+		if s.scope.NetworkSpec().ElasticFabricAdapterSupport {
+			rules = append(rules, &infrav1.IngressRule{
+				Description:            "Elastic Fabric Adapter",
+				Protocol:               infrav1.SecurityGroupProtocolAll,
+				SourceSecurityGroupIDs: []string{s.scope.SecurityGroups()[infrav1.SecurityGroupNode].ID},
+			})
+		}
+		return append(rules, s.scope.NetworkSpec().AdditionalNodeIngressRules...), nil
 	case infrav1.SecurityGroupAPIServerLB:
 		return infrav1.IngressRules{
 			{
@@ -479,6 +756,16 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 				CidrBlocks:  []string{anyIPv4CidrBlock},
 			},
 		}, nil
+	case infrav1.SecurityGroupVPCEndpoint:
+		return infrav1.IngressRules{
+			{
+				Description: "HTTPS (VPC endpoints)",
+				Protocol:    infrav1.SecurityGroupProtocolTCP,
+				FromPort:    443,
+				ToPort:      443,
+				CidrBlocks:  []string{s.scope.VPC().CidrBlock},
+			},
+		}, nil
 	case infrav1.SecurityGroupLB:
 		// We hand this group off to the in-cluster cloud provider, so these rules aren't used
 		return infrav1.IngressRules{}, nil
@@ -20,17 +20,20 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/golang/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
 
@@ -305,6 +308,258 @@ func TestControlPlaneSecurityGroupNotOpenToAnyCIDR(t *testing.T) {
 	}
 }
 
+func TestAdditionalIngressRules(t *testing.T) {
+	konnectivity := &infrav1.IngressRule{
+		Description: "konnectivity",
+		Protocol:    infrav1.SecurityGroupProtocolTCP,
+		FromPort:    8132,
+		ToPort:      8132,
+		CidrBlocks:  []string{"10.0.0.0/16"},
+	}
+	nodeExporter := &infrav1.IngressRule{
+		Description: "node exporter",
+		Protocol:    infrav1.SecurityGroupProtocolTCP,
+		FromPort:    9100,
+		ToPort:      9100,
+		CidrBlocks:  []string{"10.0.0.0/16"},
+	}
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					AdditionalControlPlaneIngressRules: infrav1.IngressRules{konnectivity},
+					AdditionalNodeIngressRules:         infrav1.IngressRules{nodeExporter},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	s := NewService(scope)
+	testCases := []struct {
+		role infrav1.SecurityGroupRole
+		want *infrav1.IngressRule
+	}{
+		{role: infrav1.SecurityGroupControlPlane, want: konnectivity},
+		{role: infrav1.SecurityGroupNode, want: nodeExporter},
+	}
+	for _, tc := range testCases {
+		rules, err := s.getSecurityGroupIngressRules(tc.role)
+		if err != nil {
+			t.Fatalf("Failed to lookup %s security group ingress rules: %v", tc.role, err)
+		}
+		if len(rules.Difference(infrav1.IngressRules{tc.want})) != len(rules)-1 {
+			t.Fatalf("Expected %s security group ingress rules to include %v", tc.role, tc.want)
+		}
+	}
+}
+
+func TestElasticFabricAdapterIngressRule(t *testing.T) {
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					ElasticFabricAdapterSupport: true,
+				},
+			},
+			Status: infrav1.AWSClusterStatus{
+				Network: infrav1.Network{
+					SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+						infrav1.SecurityGroupNode: {ID: "sg-node"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	efa := &infrav1.IngressRule{
+		Description:            "Elastic Fabric Adapter",
+		Protocol:               infrav1.SecurityGroupProtocolAll,
+		SourceSecurityGroupIDs: []string{"sg-node"},
+	}
+
+	s := NewService(scope)
+	rules, err := s.getSecurityGroupIngressRules(infrav1.SecurityGroupNode)
+	if err != nil {
+		t.Fatalf("Failed to lookup node security group ingress rules: %v", err)
+	}
+	if len(rules.Difference(infrav1.IngressRules{efa})) != len(rules)-1 {
+		t.Fatalf("Expected node security group ingress rules to include %v", efa)
+	}
+}
+
+func TestReconcileNodeEgressRules(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	https := &infrav1.IngressRule{
+		Description: "HTTPS",
+		Protocol:    infrav1.SecurityGroupProtocolTCP,
+		FromPort:    443,
+		ToPort:      443,
+		CidrBlocks:  []string{"10.0.0.0/16"},
+	}
+	ntp := &infrav1.IngressRule{
+		Description: "NTP",
+		Protocol:    infrav1.SecurityGroupProtocolUDP,
+		FromPort:    123,
+		ToPort:      123,
+		CidrBlocks:  []string{"169.254.169.123/32"},
+	}
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					NodeEgressRules: infrav1.IngressRules{https, ntp},
+				},
+			},
+			Status: infrav1.AWSClusterStatus{
+				Network: infrav1.Network{
+					SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+						infrav1.SecurityGroupAPIServerLB:  {ID: "sg-lb"},
+						infrav1.SecurityGroupControlPlane: {ID: "sg-cp"},
+						infrav1.SecurityGroupNode:         {ID: "sg-node"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	ec2Mock.EXPECT().DescribeSecurityGroups(gomock.Eq(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: []*string{aws.String("sg-node")},
+	})).
+		Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{
+				{
+					GroupId: aws.String("sg-node"),
+					IpPermissionsEgress: []*ec2.IpPermission{
+						{
+							IpProtocol: aws.String("-1"),
+							IpRanges:   []*ec2.IpRange{{CidrIp: aws.String(anyIPv4CidrBlock)}},
+						},
+						ingressRuleToSDKType(https),
+					},
+				},
+			},
+		}, nil)
+	ec2Mock.EXPECT().RevokeSecurityGroupEgress(gomock.Eq(&ec2.RevokeSecurityGroupEgressInput{
+		GroupId: aws.String("sg-node"),
+		IpPermissions: []*ec2.IpPermission{
+			{
+				IpProtocol: aws.String("-1"),
+				IpRanges:   []*ec2.IpRange{{CidrIp: aws.String(anyIPv4CidrBlock)}},
+			},
+		},
+	})).
+		Return(&ec2.RevokeSecurityGroupEgressOutput{}, nil)
+	ec2Mock.EXPECT().AuthorizeSecurityGroupEgress(gomock.AssignableToTypeOf(&ec2.AuthorizeSecurityGroupEgressInput{})).
+		DoAndReturn(func(input *ec2.AuthorizeSecurityGroupEgressInput) (*ec2.AuthorizeSecurityGroupEgressOutput, error) {
+			if len(input.IpPermissions) != 2 {
+				t.Fatalf("Expected the cluster and NTP egress rules to be authorized, got %v", input.IpPermissions)
+			}
+			return &ec2.AuthorizeSecurityGroupEgressOutput{}, nil
+		})
+
+	s := NewService(scope)
+	if err := s.reconcileSecurityGroupEgressRules("sg-node", s.getNodeEgressRules(), nil); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
+
+func TestReconcileNodeEgressRulesRevert(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	https := &infrav1.IngressRule{
+		Description: "HTTPS",
+		Protocol:    infrav1.SecurityGroupProtocolTCP,
+		FromPort:    443,
+		ToPort:      443,
+		CidrBlocks:  []string{"10.0.0.0/16"},
+	}
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSClients: scope.AWSClients{
+			EC2: ec2Mock,
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Status: infrav1.AWSClusterStatus{
+				Network: infrav1.Network{
+					SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+						infrav1.SecurityGroupAPIServerLB:  {ID: "sg-lb"},
+						infrav1.SecurityGroupControlPlane: {ID: "sg-cp"},
+						infrav1.SecurityGroupNode:         {ID: "sg-node"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	ec2Mock.EXPECT().DescribeSecurityGroups(gomock.Eq(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: []*string{aws.String("sg-node")},
+	})).
+		Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{
+				{
+					GroupId: aws.String("sg-node"),
+					IpPermissionsEgress: []*ec2.IpPermission{
+						ingressRuleToSDKType(https),
+					},
+				},
+			},
+		}, nil)
+	ec2Mock.EXPECT().RevokeSecurityGroupEgress(gomock.Eq(&ec2.RevokeSecurityGroupEgressInput{
+		GroupId:       aws.String("sg-node"),
+		IpPermissions: []*ec2.IpPermission{ingressRuleToSDKType(https)},
+	})).
+		Return(&ec2.RevokeSecurityGroupEgressOutput{}, nil)
+	ec2Mock.EXPECT().AuthorizeSecurityGroupEgress(gomock.Eq(&ec2.AuthorizeSecurityGroupEgressInput{
+		GroupId: aws.String("sg-node"),
+		IpPermissions: []*ec2.IpPermission{
+			{
+				IpProtocol: aws.String("-1"),
+				IpRanges:   []*ec2.IpRange{{CidrIp: aws.String(anyIPv4CidrBlock)}},
+			},
+		},
+	})).
+		Return(&ec2.AuthorizeSecurityGroupEgressOutput{}, nil)
+
+	s := NewService(scope)
+	if err := s.reconcileSecurityGroupEgressRules("sg-node", s.getNodeEgressRules(), nil); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
+
 func matchesTags(input *ec2.CreateTagsInput) gomock.Matcher {
 	return tagMatcher{input}
 }
@@ -346,3 +601,202 @@ func (t tagMatcher) Matches(x interface{}) bool {
 func (t tagMatcher) String() string {
 	return fmt.Sprintf("matches %v", t.CreateTagsInput)
 }
+
+// eventRecorder captures the events recorded through the record package.
+type eventRecorder struct {
+	sync.Mutex
+	events []string
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, eventtype, reason, message string) {
+	r.Lock()
+	defer r.Unlock()
+	r.events = append(r.events, fmt.Sprintf("%s %s %s", eventtype, reason, message))
+}
+
+func (r *eventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *eventRecorder) PastEventf(object runtime.Object, _ metav1.Time, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+func (r *eventRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+func (r *eventRecorder) reset() []string {
+	r.Lock()
+	defer r.Unlock()
+	events := r.events
+	r.events = nil
+	return events
+}
+
+var (
+	recorderOnce sync.Once
+	recorder     = &eventRecorder{}
+)
+
+func TestRecordSecurityGroupDrift(t *testing.T) {
+	recorderOnce.Do(func() {
+		record.InitFromRecorder(recorder)
+	})
+
+	ssh := &infrav1.IngressRule{
+		Description: "SSH",
+		Protocol:    infrav1.SecurityGroupProtocolTCP,
+		FromPort:    22,
+		ToPort:      22,
+		CidrBlocks:  []string{anyIPv4CidrBlock},
+	}
+	apiServer := &infrav1.IngressRule{
+		Description: "Kubernetes API",
+		Protocol:    infrav1.SecurityGroupProtocolTCP,
+		FromPort:    6443,
+		ToPort:      6443,
+		CidrBlocks:  []string{anyIPv4CidrBlock},
+	}
+	https := &infrav1.IngressRule{
+		Description: "HTTPS",
+		Protocol:    infrav1.SecurityGroupProtocolTCP,
+		FromPort:    443,
+		ToPort:      443,
+		CidrBlocks:  []string{"10.0.0.0/16"},
+	}
+
+	testCases := []struct {
+		name           string
+		current        infrav1.IngressRules
+		want           infrav1.IngressRules
+		lastApplied    infrav1.IngressRules
+		expectedEvents []string
+	}{
+		{
+			name:        "no drift",
+			current:     infrav1.IngressRules{apiServer},
+			want:        infrav1.IngressRules{apiServer},
+			lastApplied: infrav1.IngressRules{apiServer},
+		},
+		{
+			name:        "rule added to the spec isn't drift",
+			current:     infrav1.IngressRules{apiServer},
+			want:        infrav1.IngressRules{apiServer, ssh},
+			lastApplied: infrav1.IngressRules{apiServer},
+		},
+		{
+			name:        "rule removed from the spec isn't drift",
+			current:     infrav1.IngressRules{apiServer, ssh},
+			want:        infrav1.IngressRules{apiServer},
+			lastApplied: infrav1.IngressRules{apiServer, ssh},
+		},
+		{
+			name:        "rule added out-of-band",
+			current:     infrav1.IngressRules{apiServer, ssh},
+			want:        infrav1.IngressRules{apiServer},
+			lastApplied: infrav1.IngressRules{apiServer},
+			expectedEvents: []string{
+				fmt.Sprintf("Warning SecurityGroupDrift Revoking ingress rules added out-of-band to security group \"sg-1\": %v", infrav1.IngressRules{ssh}),
+			},
+		},
+		{
+			name:        "rule removed out-of-band",
+			current:     infrav1.IngressRules{},
+			want:        infrav1.IngressRules{apiServer},
+			lastApplied: infrav1.IngressRules{apiServer},
+			expectedEvents: []string{
+				fmt.Sprintf("Warning SecurityGroupDrift Restoring ingress rules removed out-of-band from security group \"sg-1\": %v", infrav1.IngressRules{apiServer}),
+			},
+		},
+		{
+			name:        "rule replaced out-of-band",
+			current:     infrav1.IngressRules{ssh},
+			want:        infrav1.IngressRules{apiServer, https},
+			lastApplied: infrav1.IngressRules{apiServer},
+			expectedEvents: []string{
+				fmt.Sprintf("Warning SecurityGroupDrift Revoking ingress rules added out-of-band to security group \"sg-1\": %v", infrav1.IngressRules{ssh}),
+				fmt.Sprintf("Warning SecurityGroupDrift Restoring ingress rules removed out-of-band from security group \"sg-1\": %v", infrav1.IngressRules{apiServer}),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			recorder.reset()
+
+			s := NewService(scope)
+			s.recordSecurityGroupDrift("sg-1", "ingress", tc.current, tc.want, tc.lastApplied)
+
+			if events := recorder.reset(); !reflect.DeepEqual(events, tc.expectedEvents) {
+				t.Fatalf("expected events %v, got %v", tc.expectedEvents, events)
+			}
+		})
+	}
+}
+
+func TestLastAppliedSecurityGroupRules(t *testing.T) {
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	s := NewService(scope)
+
+	lastApplied, err := s.lastAppliedSecurityGroupRules()
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+	if len(lastApplied) != 0 {
+		t.Fatalf("expected no rules without the annotation, got %v", lastApplied)
+	}
+
+	applied := map[infrav1.SecurityGroupRole]securityGroupRules{
+		infrav1.SecurityGroupNode: {
+			Ingress: infrav1.IngressRules{
+				{
+					Description:            "Node Port Services",
+					Protocol:               infrav1.SecurityGroupProtocolTCP,
+					FromPort:               30000,
+					ToPort:                 32767,
+					SourceSecurityGroupIDs: []string{"sg-node"},
+				},
+			},
+			Egress: infrav1.IngressRules{
+				{
+					Description: "HTTPS",
+					Protocol:    infrav1.SecurityGroupProtocolTCP,
+					FromPort:    443,
+					ToPort:      443,
+					CidrBlocks:  []string{"10.0.0.0/16"},
+				},
+			},
+		},
+	}
+	if err := s.setLastAppliedSecurityGroupRules(applied); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	lastApplied, err = s.lastAppliedSecurityGroupRules()
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(lastApplied, applied) {
+		t.Fatalf("expected rules %v, got %v", applied, lastApplied)
+	}
+}
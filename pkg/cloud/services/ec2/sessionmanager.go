@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+)
+
+// ReconcileSessionManagerTargets records the instances of the cluster that can be reached
+// through Session Manager in the cluster status.
+func (s *Service) ReconcileSessionManagerTargets() error {
+	if !s.scope.AWSCluster.Spec.SessionManager.Enabled {
+		s.scope.AWSCluster.Status.SessionManagerTargets = nil
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling Session Manager targets")
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.Cluster(s.scope.Name()),
+			filter.EC2.InstanceStates(ec2.InstanceStateNameRunning),
+		},
+	}
+
+	var targets []string
+	if err := s.scope.EC2.DescribeInstancesPages(input, func(out *ec2.DescribeInstancesOutput, last bool) bool {
+		for _, res := range out.Reservations {
+			for _, instance := range res.Instances {
+				targets = append(targets, aws.StringValue(instance.InstanceId))
+			}
+		}
+		return true
+	}); err != nil {
+		return errors.Wrapf(err, "failed to describe instances of cluster %q", s.scope.Name())
+	}
+
+	sort.Strings(targets)
+	s.scope.AWSCluster.Status.SessionManagerTargets = targets
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcileSessionManagerTargets(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name    string
+		enabled bool
+		expect  func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		want    []string
+	}{
+		{
+			name:   "clears the targets when session manager access is disabled",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+		{
+			name:    "records the running instances of the cluster",
+			enabled: true,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstancesPages(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+						fn(&ec2.DescribeInstancesOutput{
+							Reservations: []*ec2.Reservation{
+								{
+									Instances: []*ec2.Instance{
+										{InstanceId: aws.String("i-2")},
+										{InstanceId: aws.String("i-1")},
+									},
+								},
+							},
+						}, true)
+						return nil
+					})
+			},
+			want: []string{"i-1", "i-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			awsCluster := &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					SessionManager: infrav1.SessionManager{Enabled: tc.enabled},
+				},
+				Status: infrav1.AWSClusterStatus{
+					SessionManagerTargets: []string{"i-stale"},
+				},
+			}
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: awsCluster,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			if err := s.ReconcileSessionManagerTargets(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(awsCluster.Status.SessionManagerTargets, tc.want) {
+				t.Fatalf("expected targets %v, got %v", tc.want, awsCluster.Status.SessionManagerTargets)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package ec2
 
 import (
+	"encoding/binary"
+	"fmt"
+	"net"
 	"strings"
 
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
@@ -33,13 +36,47 @@ import (
 )
 
 const (
-	defaultPrivateSubnetCidr = "10.0.0.0/24"
-	defaultPublicSubnetCidr  = "10.0.1.0/24"
+	// defaultSubnetPrefixIncrease is how many bits longer than the VPC prefix default subnets are,
+	// e.g. a /16 VPC gets /24 subnets.
+	defaultSubnetPrefixIncrease = 8
+	// maxSubnetPrefix is the smallest subnet size AWS allows.
+	maxSubnetPrefix = 28
 
 	internalLoadBalancerTag = "kubernetes.io/role/internal-elb"
 	externalLoadBalancerTag = "kubernetes.io/role/elb"
 )
 
+// defaultSubnetCidr returns the index-th default subnet CIDR carved out of the VPC CIDR block.
+func (s *Service) defaultSubnetCidr(index int) (string, error) {
+	vpcCidr := s.scope.VPC().CidrBlock
+	if vpcCidr == "" {
+		vpcCidr = defaultVPCCidr
+	}
+
+	_, network, err := net.ParseCIDR(vpcCidr)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse vpc cidr block %q", vpcCidr)
+	}
+	ip := network.IP.To4()
+	if ip == nil {
+		return "", errors.Errorf("vpc cidr block %q is not an IPv4 block", vpcCidr)
+	}
+
+	ones, _ := network.Mask.Size()
+	prefix := ones + defaultSubnetPrefixIncrease
+	if prefix > maxSubnetPrefix {
+		prefix = maxSubnetPrefix
+	}
+	if prefix <= ones || index >= 1<<uint(prefix-ones) {
+		return "", errors.Errorf("vpc cidr block %q is too small for %d default subnets", vpcCidr, index+1)
+	}
+
+	base := binary.BigEndian.Uint32(ip) + uint32(index)<<uint(32-prefix)
+	subnet := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(subnet, base)
+	return fmt.Sprintf("%s/%d", subnet, prefix), nil
+}
+
 func (s *Service) reconcileSubnets() error {
 	s.scope.V(2).Info("Reconciling subnets")
 
@@ -55,9 +92,9 @@ func (s *Service) reconcileSubnets() error {
 	}
 
 	// If the subnets are empty, populate the slice with the default configuration.
-	// Adds a single private and public subnet in the first available zone.
+	// Adds a private and a public subnet in each of the selected availability zones.
 	if len(existing) < 2 && len(subnets) < 2 {
-		zones, err := s.getAvailableZones()
+		zones, err := s.getSubnetZones()
 		if err != nil {
 			return err
 		}
@@ -67,26 +104,42 @@ func (s *Service) reconcileSubnets() error {
 				return errors.New("expected at least one private subnet available for use, got 0")
 			}
 
-			subnets = append(subnets, &infrav1.SubnetSpec{
-				CidrBlock:        defaultPrivateSubnetCidr,
-				AvailabilityZone: zones[0],
-				IsPublic:         false,
-			})
+			for i, zone := range zones {
+				cidr, err := s.defaultSubnetCidr(2 * i)
+				if err != nil {
+					return err
+				}
+				subnets = append(subnets, &infrav1.SubnetSpec{
+					CidrBlock:        cidr,
+					AvailabilityZone: zone,
+					IsPublic:         false,
+				})
+			}
 		}
 
-		if len(subnets.FilterPublic()) == 0 {
+		if len(subnets.FilterPublic()) == 0 && !s.scope.NetworkSpec().FullyPrivate {
 			if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
 				return errors.New("expected at least one public subnet available for use, got 0")
 			}
 
-			subnets = append(subnets, &infrav1.SubnetSpec{
-				CidrBlock:        defaultPublicSubnetCidr,
-				AvailabilityZone: zones[0],
-				IsPublic:         true,
-			})
+			for i, zone := range zones {
+				cidr, err := s.defaultSubnetCidr(2*i + 1)
+				if err != nil {
+					return err
+				}
+				subnets = append(subnets, &infrav1.SubnetSpec{
+					CidrBlock:        cidr,
+					AvailabilityZone: zone,
+					IsPublic:         true,
+				})
+			}
 		}
 	}
 
+	if s.scope.NetworkSpec().FullyPrivate && !s.scope.VPC().IsUnmanaged(s.scope.Name()) && len(subnets.FilterPublic()) > 0 {
+		return errors.New("public subnets cannot be used in fully private mode")
+	}
+
 LoopExisting:
 	for i := range existing {
 		exsn := existing[i]
@@ -98,7 +151,7 @@ LoopExisting:
 			if (sn.ID != "" && exsn.ID == sn.ID) || (sn.CidrBlock == exsn.CidrBlock) {
 				if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
 					// TODO(vincepri): Validate provided subnet passes some basic checks.
-					exsn.DeepCopyInto(sn)
+					updateSubnetSpec(exsn, sn)
 					continue LoopExisting
 				}
 
@@ -117,7 +170,7 @@ LoopExisting:
 				}
 
 				// TODO(vincepri): check if subnet needs to be updated.
-				exsn.DeepCopyInto(sn)
+				updateSubnetSpec(exsn, sn)
 				continue LoopExisting
 			}
 		}
@@ -138,14 +191,32 @@ LoopExisting:
 				return err
 			}
 
-			nsn.DeepCopyInto(subnet)
+			updateSubnetSpec(nsn, subnet)
 		}
 	}
 
+	if len(s.scope.NetworkSpec().SubnetFilters) > 0 && s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.Network().Subnets = existing.DeepCopy()
+	} else {
+		s.scope.Network().Subnets = nil
+	}
+
 	s.scope.V(2).Info("Subnets available", "subnets", subnets)
 	return nil
 }
 
+// updateSubnetSpec records the observed state of a subnet in the subnet spec, leaving
+// user provided configuration untouched.
+func updateSubnetSpec(observed, sn *infrav1.SubnetSpec) {
+	natGatewayEIPAllocationID := sn.NatGatewayEIPAllocationID
+	localGatewayID := sn.LocalGatewayID
+	additionalRoutes := sn.AdditionalRoutes
+	observed.DeepCopyInto(sn)
+	sn.NatGatewayEIPAllocationID = natGatewayEIPAllocationID
+	sn.LocalGatewayID = localGatewayID
+	sn.AdditionalRoutes = additionalRoutes
+}
+
 func (s *Service) deleteSubnets() error {
 	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
 		s.scope.V(4).Info("Skipping subnets deletion in unmanaged mode")
@@ -180,6 +251,10 @@ func (s *Service) describeVpcSubnets() (infrav1.Subnets, error) {
 		input.Filters = append(input.Filters, filter.EC2.VPC(s.scope.VPC().ID))
 	}
 
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		input.Filters = append(input.Filters, converters.FiltersToSDK(s.scope.NetworkSpec().SubnetFilters)...)
+	}
+
 	out, err := s.scope.EC2.DescribeSubnets(input)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to describe subnets in vpc %q", s.scope.VPC().ID)
@@ -203,6 +278,7 @@ func (s *Service) describeVpcSubnets() (infrav1.Subnets, error) {
 			ID:               *ec2sn.SubnetId,
 			CidrBlock:        *ec2sn.CidrBlock,
 			AvailabilityZone: *ec2sn.AvailabilityZone,
+			OutpostARN:       ec2sn.OutpostArn,
 			Tags:             converters.TagsToMap(ec2sn.Tags),
 		}
 
@@ -241,6 +317,7 @@ func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, err
 		VpcId:            aws.String(s.scope.VPC().ID),
 		CidrBlock:        aws.String(sn.CidrBlock),
 		AvailabilityZone: aws.String(sn.AvailabilityZone),
+		OutpostArn:       sn.OutpostARN,
 	})
 
 	if err != nil {
@@ -301,6 +378,7 @@ func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, err
 		AvailabilityZone: *out.Subnet.AvailabilityZone,
 		CidrBlock:        *out.Subnet.CidrBlock,
 		IsPublic:         sn.IsPublic,
+		OutpostARN:       out.Subnet.OutpostArn,
 	}, nil
 }
 
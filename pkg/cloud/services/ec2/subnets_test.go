@@ -142,7 +142,7 @@ func TestReconcileSubnets(t *testing.T) {
 
 				m.CreateSubnet(gomock.Eq(&ec2.CreateSubnetInput{
 					VpcId:            aws.String(subnetsVPCID),
-					CidrBlock:        aws.String(defaultPublicSubnetCidr),
+					CidrBlock:        aws.String("10.0.1.0/24"),
 					AvailabilityZone: aws.String("us-east-1a"),
 				})).
 					Return(&ec2.CreateSubnetOutput{
@@ -338,14 +338,14 @@ func TestReconcileSubnets(t *testing.T) {
 
 				firstSubnet := m.CreateSubnet(gomock.Eq(&ec2.CreateSubnetInput{
 					VpcId:            aws.String(subnetsVPCID),
-					CidrBlock:        aws.String(defaultPrivateSubnetCidr),
+					CidrBlock:        aws.String("10.0.0.0/24"),
 					AvailabilityZone: aws.String("us-east-1c"),
 				})).
 					Return(&ec2.CreateSubnetOutput{
 						Subnet: &ec2.Subnet{
 							VpcId:               aws.String(subnetsVPCID),
 							SubnetId:            aws.String("subnet-1"),
-							CidrBlock:           aws.String(defaultPrivateSubnetCidr),
+							CidrBlock:           aws.String("10.0.0.0/24"),
 							AvailabilityZone:    aws.String("us-east-1c"),
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
@@ -360,14 +360,14 @@ func TestReconcileSubnets(t *testing.T) {
 
 				secondSubnet := m.CreateSubnet(gomock.Eq(&ec2.CreateSubnetInput{
 					VpcId:            aws.String(subnetsVPCID),
-					CidrBlock:        aws.String(defaultPublicSubnetCidr),
+					CidrBlock:        aws.String("10.0.1.0/24"),
 					AvailabilityZone: aws.String("us-east-1c"),
 				})).
 					Return(&ec2.CreateSubnetOutput{
 						Subnet: &ec2.Subnet{
 							VpcId:               aws.String(subnetsVPCID),
 							SubnetId:            aws.String("subnet-2"),
-							CidrBlock:           aws.String(defaultPublicSubnetCidr),
+							CidrBlock:           aws.String("10.0.1.0/24"),
 							AvailabilityZone:    aws.String("us-east-1c"),
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
@@ -390,6 +390,59 @@ func TestReconcileSubnets(t *testing.T) {
 
 			},
 		},
+		{
+			name: "no subnet exist in fully private mode, expect one private subnet from defaults",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: subnetsVPCID,
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+				},
+				Subnets:      []*infrav1.SubnetSpec{},
+				FullyPrivate: true,
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAvailabilityZones(gomock.Any()).
+					Return(&ec2.DescribeAvailabilityZonesOutput{
+						AvailabilityZones: []*ec2.AvailabilityZone{
+							{
+								ZoneName: aws.String("us-east-1c"),
+							},
+						},
+					}, nil)
+
+				describeCall := m.DescribeSubnets(gomock.AssignableToTypeOf(&ec2.DescribeSubnetsInput{})).
+					Return(&ec2.DescribeSubnetsOutput{}, nil)
+
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{}, nil)
+
+				m.DescribeNatGatewaysPages(gomock.Any(), gomock.Any()).Return(nil)
+
+				subnet := m.CreateSubnet(gomock.Eq(&ec2.CreateSubnetInput{
+					VpcId:            aws.String(subnetsVPCID),
+					CidrBlock:        aws.String("10.0.0.0/24"),
+					AvailabilityZone: aws.String("us-east-1c"),
+				})).
+					Return(&ec2.CreateSubnetOutput{
+						Subnet: &ec2.Subnet{
+							VpcId:               aws.String(subnetsVPCID),
+							SubnetId:            aws.String("subnet-1"),
+							CidrBlock:           aws.String("10.0.0.0/24"),
+							AvailabilityZone:    aws.String("us-east-1c"),
+							MapPublicIpOnLaunch: aws.Bool(false),
+						},
+					}, nil).
+					After(describeCall)
+
+				m.WaitUntilSubnetAvailable(gomock.Any()).
+					After(subnet)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+		},
 		{
 			name: "managed VPC respects public tag",
 			input: &infrav1.NetworkSpec{
@@ -480,7 +533,7 @@ func TestReconcileSubnets(t *testing.T) {
 
 				m.CreateSubnet(gomock.Eq(&ec2.CreateSubnetInput{
 					VpcId:            aws.String(subnetsVPCID),
-					CidrBlock:        aws.String(defaultPrivateSubnetCidr),
+					CidrBlock:        aws.String("10.0.0.0/24"),
 					AvailabilityZone: aws.String("us-east-1a"),
 				})).
 					Return(&ec2.CreateSubnetOutput{
@@ -671,6 +724,92 @@ func TestDiscoverSubnets(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "provided VPC discovers subnets by filters",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: subnetsVPCID,
+				},
+				SubnetFilters: []infrav1.Filter{
+					{
+						Name:   "tag:kubernetes.io/role/internal-elb",
+						Values: []string{"1"},
+					},
+				},
+			},
+			mocks: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeSubnets(gomock.Eq(&ec2.DescribeSubnetsInput{
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("state"),
+							Values: []*string{aws.String("pending"), aws.String("available")},
+						},
+						{
+							Name:   aws.String("vpc-id"),
+							Values: []*string{aws.String(subnetsVPCID)},
+						},
+						{
+							Name:   aws.String("tag:kubernetes.io/role/internal-elb"),
+							Values: []*string{aws.String("1")},
+						},
+					},
+				})).
+					Return(&ec2.DescribeSubnetsOutput{
+						Subnets: []*ec2.Subnet{
+							{
+								VpcId:            aws.String(subnetsVPCID),
+								SubnetId:         aws.String("subnet-2"),
+								AvailabilityZone: aws.String("us-east-1a"),
+								CidrBlock:        aws.String("10.0.11.0/24"),
+								Tags: []*ec2.Tag{
+									{
+										Key:   aws.String("kubernetes.io/role/internal-elb"),
+										Value: aws.String("1"),
+									},
+								},
+							},
+							{
+								VpcId:            aws.String(subnetsVPCID),
+								SubnetId:         aws.String("subnet-3"),
+								AvailabilityZone: aws.String("us-east-1b"),
+								CidrBlock:        aws.String("10.0.12.0/24"),
+								Tags: []*ec2.Tag{
+									{
+										Key:   aws.String("kubernetes.io/role/internal-elb"),
+										Value: aws.String("1"),
+									},
+								},
+							},
+						},
+					}, nil)
+
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{}, nil)
+
+				m.DescribeNatGatewaysPages(gomock.AssignableToTypeOf(&ec2.DescribeNatGatewaysInput{}), gomock.Any()).
+					Return(nil)
+			},
+			expect: []*infrav1.SubnetSpec{
+				{
+					ID:               "subnet-2",
+					AvailabilityZone: "us-east-1a",
+					CidrBlock:        "10.0.11.0/24",
+					IsPublic:         false,
+					Tags: infrav1.Tags{
+						"kubernetes.io/role/internal-elb": "1",
+					},
+				},
+				{
+					ID:               "subnet-3",
+					AvailabilityZone: "us-east-1b",
+					CidrBlock:        "10.0.12.0/24",
+					IsPublic:         false,
+					Tags: infrav1.Tags{
+						"kubernetes.io/role/internal-elb": "1",
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -729,3 +868,173 @@ func TestDiscoverSubnets(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultSubnetZones(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *infrav1.NetworkSpec
+		expectZones   []string
+		expectCidrs   []string
+		expectedError bool
+	}{
+		{
+			name:        "defaults to the first available zone",
+			input:       &infrav1.NetworkSpec{},
+			expectZones: []string{"us-east-1a"},
+			expectCidrs: []string{"10.0.0.0/24", "10.0.1.0/24"},
+		},
+		{
+			name: "usage limit spreads subnets across zones",
+			input: &infrav1.NetworkSpec{
+				VPC:                        infrav1.VPCSpec{CidrBlock: "10.1.0.0/16"},
+				AvailabilityZoneUsageLimit: aws.Int(2),
+			},
+			expectZones: []string{"us-east-1a", "us-east-1b"},
+			expectCidrs: []string{"10.1.0.0/24", "10.1.1.0/24", "10.1.2.0/24", "10.1.3.0/24"},
+		},
+		{
+			name: "allowlist pins zones",
+			input: &infrav1.NetworkSpec{
+				AvailabilityZones:          []string{"us-east-1c", "us-east-1b", "us-east-1z"},
+				AvailabilityZoneUsageLimit: aws.Int(3),
+			},
+			expectZones: []string{"us-east-1b", "us-east-1c"},
+		},
+		{
+			name: "allowlist with no available zones fails",
+			input: &infrav1.NetworkSpec{
+				AvailabilityZones: []string{"us-east-1z"},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: *tc.input,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			ec2Mock.EXPECT().DescribeAvailabilityZones(gomock.Any()).
+				Return(&ec2.DescribeAvailabilityZonesOutput{
+					AvailabilityZones: []*ec2.AvailabilityZone{
+						{ZoneName: aws.String("us-east-1c")},
+						{ZoneName: aws.String("us-east-1a")},
+						{ZoneName: aws.String("us-east-1b")},
+					},
+				}, nil)
+
+			s := NewService(scope)
+			zones, err := s.getSubnetZones()
+			if tc.expectedError {
+				if err == nil {
+					t.Fatal("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(zones, tc.expectZones) {
+				t.Fatalf("expected zones %v, got %v", tc.expectZones, zones)
+			}
+
+			for i, expected := range tc.expectCidrs {
+				cidr, err := s.defaultSubnetCidr(i)
+				if err != nil {
+					t.Fatalf("got an unexpected error: %v", err)
+				}
+				if cidr != expected {
+					t.Fatalf("expected default subnet %d to be %q, got %q", i, expected, cidr)
+				}
+			}
+		})
+	}
+}
+
+func TestSubnetTagParams(t *testing.T) {
+	testCases := []struct {
+		name     string
+		isPublic bool
+		tags     infrav1.Tags
+		expected infrav1.Tags
+	}{
+		{
+			name:     "private subnet with custom tags",
+			isPublic: false,
+			tags: infrav1.Tags{
+				"cost-center": "platform",
+				"team":        "subnet-owner",
+			},
+			expected: infrav1.Tags{
+				"Name":                                "test-cluster-subnet-private",
+				"cost-center":                         "platform",
+				"team":                                "subnet-owner",
+				internalLoadBalancerTag:               "1",
+				"kubernetes.io/cluster/test-cluster":  "shared",
+				infrav1.ClusterTagKey("test-cluster"): "owned",
+				infrav1.NameAWSClusterAPIRole:         infrav1.PrivateRoleTagValue,
+			},
+		},
+		{
+			name:     "public subnet tags cannot override the provider tags",
+			isPublic: true,
+			tags: infrav1.Tags{
+				"Name":                                "custom-name",
+				infrav1.ClusterTagKey("test-cluster"): "shared",
+				externalLoadBalancerTag:               "0",
+			},
+			expected: infrav1.Tags{
+				"Name":                                "test-cluster-subnet-public",
+				"team":                                "cluster-owner",
+				externalLoadBalancerTag:               "0",
+				"kubernetes.io/cluster/test-cluster":  "shared",
+				infrav1.ClusterTagKey("test-cluster"): "owned",
+				infrav1.NameAWSClusterAPIRole:         infrav1.PublicRoleTagValue,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						AdditionalTags: infrav1.Tags{
+							"team": "cluster-owner",
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			s := NewService(scope)
+			tags := infrav1.Build(s.getSubnetTagParams("subnet-1", tc.isPublic, tc.tags))
+			if !reflect.DeepEqual(tags, tc.expected) {
+				t.Fatalf("expected tags %v, got %v", tc.expected, tags)
+			}
+		})
+	}
+}
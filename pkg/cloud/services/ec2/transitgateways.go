@@ -0,0 +1,355 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// transitGatewayAttachmentRoleTagValue describes the value for the transit gateway attachment role
+	transitGatewayAttachmentRoleTagValue = "tgw-attachment"
+)
+
+func (s *Service) reconcileTransitGatewayAttachment() error {
+	spec := s.scope.NetworkSpec().TransitGateway
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping transit gateway attachment reconcile in unmanaged mode")
+		s.scope.Network().TransitGatewayAttachment = nil
+		return nil
+	}
+
+	// Without a transit gateway in the spec there is only something to clean up if the status still
+	// records an attachment.
+	if spec == nil && s.scope.Network().TransitGatewayAttachment == nil {
+		return nil
+	}
+
+	attachments, err := s.describeTransitGatewayAttachments()
+	if err != nil {
+		return err
+	}
+
+	var attachment *ec2.TransitGatewayVpcAttachment
+	for _, a := range attachments {
+		if spec != nil && aws.StringValue(a.TransitGatewayId) == spec.ID && aws.StringValue(a.State) != ec2.TransitGatewayAttachmentStateDeleting {
+			attachment = a
+			continue
+		}
+
+		// Attachments to a transit gateway removed from the spec, or replaced with another one, are
+		// deleted together with the routes through that transit gateway.
+		if err := s.deleteTransitGatewayRoutes(aws.StringValue(a.TransitGatewayId)); err != nil {
+			return err
+		}
+		if err := s.deleteTransitGatewayVpcAttachment(a); err != nil {
+			return err
+		}
+	}
+
+	if spec == nil {
+		s.scope.Network().TransitGatewayAttachment = nil
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling transit gateway attachment", "transit-gateway-id", spec.ID)
+
+	if attachment == nil {
+		attachment, err = s.createTransitGatewayAttachment(spec)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Make sure tags are up to date.
+		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+			if err := tags.Ensure(converters.TagsToMap(attachment.Tags), &tags.ApplyParams{
+				EC2Client:   s.scope.EC2,
+				BuildParams: s.getTransitGatewayAttachmentTagParams(*attachment.TransitGatewayAttachmentId),
+			}); err != nil {
+				return false, err
+			}
+			return true, nil
+		}, awserrors.ResourceNotFound); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedTagTransitGatewayAttachment", "Failed to tag managed Transit Gateway attachment %q: %v", *attachment.TransitGatewayAttachmentId, err)
+			return errors.Wrapf(err, "failed to tag transit gateway attachment %q", *attachment.TransitGatewayAttachmentId)
+		}
+	}
+
+	s.scope.Network().TransitGatewayAttachment = &infrav1.TransitGatewayAttachment{
+		ID:    aws.StringValue(attachment.TransitGatewayAttachmentId),
+		State: aws.StringValue(attachment.State),
+	}
+
+	// Route table association and propagation can only be configured once the attachment is available.
+	if aws.StringValue(attachment.State) != ec2.TransitGatewayAttachmentStateAvailable {
+		s.scope.V(2).Info("Waiting for transit gateway attachment to become available", "transit-gateway-attachment-id", *attachment.TransitGatewayAttachmentId, "state", aws.StringValue(attachment.State))
+		return nil
+	}
+
+	if err := s.reconcileTransitGatewayRouteTableAssociation(spec, attachment); err != nil {
+		return err
+	}
+
+	return s.reconcileTransitGatewayRouteTablePropagations(spec, attachment)
+}
+
+func (s *Service) createTransitGatewayAttachment(spec *infrav1.TransitGatewaySpec) (*ec2.TransitGatewayVpcAttachment, error) {
+	subnetIDs := spec.SubnetIDs
+	if len(subnetIDs) == 0 {
+		// A transit gateway attachment accepts at most one subnet per availability zone.
+		zones := map[string]bool{}
+		for _, sn := range s.scope.Subnets().FilterPrivate() {
+			if sn.ID == "" || zones[sn.AvailabilityZone] {
+				continue
+			}
+			zones[sn.AvailabilityZone] = true
+			subnetIDs = append(subnetIDs, sn.ID)
+		}
+	}
+
+	if len(subnetIDs) == 0 {
+		return nil, errors.Errorf("failed to create transit gateway attachment for vpc %q: no subnets available", s.scope.VPC().ID)
+	}
+
+	out, err := s.scope.EC2.CreateTransitGatewayVpcAttachment(&ec2.CreateTransitGatewayVpcAttachmentInput{
+		TransitGatewayId: aws.String(spec.ID),
+		VpcId:            aws.String(s.scope.VPC().ID),
+		SubnetIds:        aws.StringSlice(subnetIDs),
+		TagSpecifications: []*ec2.TagSpecification{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeTransitGatewayAttachment),
+				Tags:         converters.MapToTags(infrav1.Build(s.getTransitGatewayAttachmentTagParams(""))),
+			},
+		},
+	})
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreateTransitGatewayAttachment", "Failed to attach managed VPC %q to Transit Gateway %q: %v", s.scope.VPC().ID, spec.ID, err)
+		return nil, errors.Wrapf(err, "failed to attach vpc %q to transit gateway %q", s.scope.VPC().ID, spec.ID)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreateTransitGatewayAttachment", "Attached managed VPC %q to Transit Gateway %q", s.scope.VPC().ID, spec.ID)
+	s.scope.V(2).Info("Created transit gateway attachment", "transit-gateway-attachment-id", aws.StringValue(out.TransitGatewayVpcAttachment.TransitGatewayAttachmentId))
+
+	return out.TransitGatewayVpcAttachment, nil
+}
+
+func (s *Service) reconcileTransitGatewayRouteTableAssociation(spec *infrav1.TransitGatewaySpec, attachment *ec2.TransitGatewayVpcAttachment) error {
+	if spec.AssociationRouteTableID == nil {
+		return nil
+	}
+
+	out, err := s.scope.EC2.DescribeTransitGatewayAttachments(&ec2.DescribeTransitGatewayAttachmentsInput{
+		TransitGatewayAttachmentIds: []*string{attachment.TransitGatewayAttachmentId},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe transit gateway attachment %q", *attachment.TransitGatewayAttachmentId)
+	}
+
+	for _, a := range out.TransitGatewayAttachments {
+		if a.Association == nil {
+			continue
+		}
+		if aws.StringValue(a.Association.TransitGatewayRouteTableId) == *spec.AssociationRouteTableID {
+			return nil
+		}
+		return errors.Errorf("transit gateway attachment %q is already associated with route table %q", *attachment.TransitGatewayAttachmentId, aws.StringValue(a.Association.TransitGatewayRouteTableId))
+	}
+
+	if _, err := s.scope.EC2.AssociateTransitGatewayRouteTable(&ec2.AssociateTransitGatewayRouteTableInput{
+		TransitGatewayAttachmentId: attachment.TransitGatewayAttachmentId,
+		TransitGatewayRouteTableId: spec.AssociationRouteTableID,
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedAssociateTransitGatewayRouteTable", "Failed to associate Transit Gateway attachment %q with route table %q: %v", *attachment.TransitGatewayAttachmentId, *spec.AssociationRouteTableID, err)
+		return errors.Wrapf(err, "failed to associate transit gateway attachment %q with route table %q", *attachment.TransitGatewayAttachmentId, *spec.AssociationRouteTableID)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulAssociateTransitGatewayRouteTable", "Associated Transit Gateway attachment %q with route table %q", *attachment.TransitGatewayAttachmentId, *spec.AssociationRouteTableID)
+	return nil
+}
+
+func (s *Service) reconcileTransitGatewayRouteTablePropagations(spec *infrav1.TransitGatewaySpec, attachment *ec2.TransitGatewayVpcAttachment) error {
+	for _, routeTableID := range spec.PropagationRouteTableIDs {
+		out, err := s.scope.EC2.GetTransitGatewayRouteTablePropagations(&ec2.GetTransitGatewayRouteTablePropagationsInput{
+			TransitGatewayRouteTableId: aws.String(routeTableID),
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("transit-gateway-attachment-id"),
+					Values: []*string{attachment.TransitGatewayAttachmentId},
+				},
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get propagations for transit gateway route table %q", routeTableID)
+		}
+
+		if len(out.TransitGatewayRouteTablePropagations) > 0 {
+			continue
+		}
+
+		if _, err := s.scope.EC2.EnableTransitGatewayRouteTablePropagation(&ec2.EnableTransitGatewayRouteTablePropagationInput{
+			TransitGatewayAttachmentId: attachment.TransitGatewayAttachmentId,
+			TransitGatewayRouteTableId: aws.String(routeTableID),
+		}); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedEnableTransitGatewayRouteTablePropagation", "Failed to propagate Transit Gateway attachment %q to route table %q: %v", *attachment.TransitGatewayAttachmentId, routeTableID, err)
+			return errors.Wrapf(err, "failed to propagate transit gateway attachment %q to route table %q", *attachment.TransitGatewayAttachmentId, routeTableID)
+		}
+
+		record.Eventf(s.scope.AWSCluster, "SuccessfulEnableTransitGatewayRouteTablePropagation", "Propagated Transit Gateway attachment %q to route table %q", *attachment.TransitGatewayAttachmentId, routeTableID)
+	}
+
+	return nil
+}
+
+func (s *Service) deleteTransitGatewayAttachment() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping transit gateway attachment deletion in unmanaged mode")
+		return nil
+	}
+
+	attachments, err := s.describeTransitGatewayAttachments()
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		if err := s.deleteTransitGatewayVpcAttachment(attachment); err != nil {
+			return err
+		}
+	}
+
+	s.scope.Network().TransitGatewayAttachment = nil
+	return nil
+}
+
+func (s *Service) deleteTransitGatewayVpcAttachment(attachment *ec2.TransitGatewayVpcAttachment) error {
+	id := aws.StringValue(attachment.TransitGatewayAttachmentId)
+	if aws.StringValue(attachment.State) == ec2.TransitGatewayAttachmentStateDeleting {
+		return nil
+	}
+
+	if _, err := s.scope.EC2.DeleteTransitGatewayVpcAttachment(&ec2.DeleteTransitGatewayVpcAttachmentInput{
+		TransitGatewayAttachmentId: attachment.TransitGatewayAttachmentId,
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteTransitGatewayAttachment", "Failed to delete managed Transit Gateway attachment %q: %v", id, err)
+		return errors.Wrapf(err, "failed to delete transit gateway attachment %q", id)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteTransitGatewayAttachment", "Deleted managed Transit Gateway attachment %q", id)
+	s.scope.V(2).Info("Deleted transit gateway attachment", "transit-gateway-attachment-id", id)
+	return nil
+}
+
+// deleteTransitGatewayRoutes deletes the routes through the transit gateway from the managed route tables.
+func (s *Service) deleteTransitGatewayRoutes(transitGatewayID string) error {
+	rts, err := s.describeVpcRouteTables()
+	if err != nil {
+		return err
+	}
+
+	for _, rt := range rts {
+		for _, route := range rt.Routes {
+			if aws.StringValue(route.TransitGatewayId) != transitGatewayID {
+				continue
+			}
+
+			if _, err := s.scope.EC2.DeleteRoute(&ec2.DeleteRouteInput{
+				RouteTableId:         rt.RouteTableId,
+				DestinationCidrBlock: route.DestinationCidrBlock,
+			}); err != nil {
+				record.Warnf(s.scope.AWSCluster, "FailedDeleteRoute", "Failed to delete route to %q through Transit Gateway %q from RouteTable %q: %v", aws.StringValue(route.DestinationCidrBlock), transitGatewayID, *rt.RouteTableId, err)
+				return errors.Wrapf(err, "failed to delete route to %q from route table %q", aws.StringValue(route.DestinationCidrBlock), *rt.RouteTableId)
+			}
+
+			record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteRoute", "Deleted route to %q through Transit Gateway %q from RouteTable %q", aws.StringValue(route.DestinationCidrBlock), transitGatewayID, *rt.RouteTableId)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) describeTransitGatewayAttachments() ([]*ec2.TransitGatewayVpcAttachment, error) {
+	input := &ec2.DescribeTransitGatewayVpcAttachmentsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.Cluster(s.scope.Name()),
+			{
+				Name: aws.String("state"),
+				Values: aws.StringSlice([]string{
+					ec2.TransitGatewayAttachmentStateInitiating,
+					ec2.TransitGatewayAttachmentStateAvailable,
+					ec2.TransitGatewayAttachmentStateDeleting,
+					ec2.TransitGatewayAttachmentStateModifying,
+					ec2.TransitGatewayAttachmentStatePending,
+					ec2.TransitGatewayAttachmentStatePendingAcceptance,
+				}),
+			},
+		},
+	}
+
+	out, err := s.scope.EC2.DescribeTransitGatewayVpcAttachments(input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe transit gateway attachments for vpc %q", s.scope.VPC().ID)
+	}
+
+	return out.TransitGatewayVpcAttachments, nil
+}
+
+// transitGatewayRoutesReady returns true when routes through the Transit Gateway can be created.
+func (s *Service) transitGatewayRoutesReady() bool {
+	attachment := s.scope.Network().TransitGatewayAttachment
+	return s.scope.NetworkSpec().TransitGateway != nil && attachment != nil &&
+		attachment.State == ec2.TransitGatewayAttachmentStateAvailable
+}
+
+func (s *Service) getTransitGatewayRoutes() []*ec2.Route {
+	if !s.transitGatewayRoutesReady() {
+		return nil
+	}
+
+	routes := make([]*ec2.Route, 0, len(s.scope.NetworkSpec().TransitGateway.DestinationCidrBlocks))
+	for _, cidr := range s.scope.NetworkSpec().TransitGateway.DestinationCidrBlocks {
+		routes = append(routes, &ec2.Route{
+			DestinationCidrBlock: aws.String(cidr),
+			TransitGatewayId:     aws.String(s.scope.NetworkSpec().TransitGateway.ID),
+		})
+	}
+	return routes
+}
+
+func (s *Service) getTransitGatewayAttachmentTagParams(id string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-tgw-attachment", s.scope.Name())
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(transitGatewayAttachmentRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
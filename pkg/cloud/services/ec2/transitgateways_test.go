@@ -0,0 +1,253 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcileTransitGatewayAttachment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name          string
+		removed       bool
+		expect        func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectedState string
+		expectRoutes  int
+	}{
+		{
+			name: "no attachment, creates one in the private subnets",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeTransitGatewayVpcAttachments(gomock.AssignableToTypeOf(&ec2.DescribeTransitGatewayVpcAttachmentsInput{})).
+					Return(&ec2.DescribeTransitGatewayVpcAttachmentsOutput{}, nil)
+				m.CreateTransitGatewayVpcAttachment(gomock.AssignableToTypeOf(&ec2.CreateTransitGatewayVpcAttachmentInput{})).
+					DoAndReturn(func(input *ec2.CreateTransitGatewayVpcAttachmentInput) (*ec2.CreateTransitGatewayVpcAttachmentOutput, error) {
+						if got := aws.StringValueSlice(input.SubnetIds); len(got) != 1 || got[0] != "subnet-private-1a" {
+							t.Fatalf("expected attachment in subnet-private-1a only, got %v", got)
+						}
+						return &ec2.CreateTransitGatewayVpcAttachmentOutput{
+							TransitGatewayVpcAttachment: &ec2.TransitGatewayVpcAttachment{
+								TransitGatewayAttachmentId: aws.String("tgw-attach-1"),
+								State:                      aws.String(ec2.TransitGatewayAttachmentStatePending),
+							},
+						}, nil
+					})
+			},
+			expectedState: ec2.TransitGatewayAttachmentStatePending,
+			expectRoutes:  0,
+		},
+		{
+			name: "available attachment, associates and propagates route tables",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeTransitGatewayVpcAttachments(gomock.AssignableToTypeOf(&ec2.DescribeTransitGatewayVpcAttachmentsInput{})).
+					Return(&ec2.DescribeTransitGatewayVpcAttachmentsOutput{
+						TransitGatewayVpcAttachments: []*ec2.TransitGatewayVpcAttachment{
+							{
+								TransitGatewayAttachmentId: aws.String("tgw-attach-1"),
+								TransitGatewayId:           aws.String("tgw-1"),
+								State:                      aws.String(ec2.TransitGatewayAttachmentStateAvailable),
+							},
+						},
+					}, nil)
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+				m.DescribeTransitGatewayAttachments(gomock.AssignableToTypeOf(&ec2.DescribeTransitGatewayAttachmentsInput{})).
+					Return(&ec2.DescribeTransitGatewayAttachmentsOutput{
+						TransitGatewayAttachments: []*ec2.TransitGatewayAttachment{
+							{TransitGatewayAttachmentId: aws.String("tgw-attach-1")},
+						},
+					}, nil)
+				m.AssociateTransitGatewayRouteTable(gomock.Eq(&ec2.AssociateTransitGatewayRouteTableInput{
+					TransitGatewayAttachmentId: aws.String("tgw-attach-1"),
+					TransitGatewayRouteTableId: aws.String("tgw-rtb-assoc"),
+				})).
+					Return(&ec2.AssociateTransitGatewayRouteTableOutput{}, nil)
+				m.GetTransitGatewayRouteTablePropagations(gomock.AssignableToTypeOf(&ec2.GetTransitGatewayRouteTablePropagationsInput{})).
+					Return(&ec2.GetTransitGatewayRouteTablePropagationsOutput{}, nil)
+				m.EnableTransitGatewayRouteTablePropagation(gomock.Eq(&ec2.EnableTransitGatewayRouteTablePropagationInput{
+					TransitGatewayAttachmentId: aws.String("tgw-attach-1"),
+					TransitGatewayRouteTableId: aws.String("tgw-rtb-prop"),
+				})).
+					Return(&ec2.EnableTransitGatewayRouteTablePropagationOutput{}, nil)
+			},
+			expectedState: ec2.TransitGatewayAttachmentStateAvailable,
+			expectRoutes:  2,
+		},
+		{
+			name: "attachment to another transit gateway, deletes it with its routes and creates one",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeTransitGatewayVpcAttachments(gomock.AssignableToTypeOf(&ec2.DescribeTransitGatewayVpcAttachmentsInput{})).
+					Return(&ec2.DescribeTransitGatewayVpcAttachmentsOutput{
+						TransitGatewayVpcAttachments: []*ec2.TransitGatewayVpcAttachment{
+							{
+								TransitGatewayAttachmentId: aws.String("tgw-attach-old"),
+								TransitGatewayId:           aws.String("tgw-old"),
+								State:                      aws.String(ec2.TransitGatewayAttachmentStateAvailable),
+							},
+						},
+					}, nil)
+				expectTransitGatewayRoutesDeleted(m, "tgw-old")
+				m.DeleteTransitGatewayVpcAttachment(gomock.Eq(&ec2.DeleteTransitGatewayVpcAttachmentInput{
+					TransitGatewayAttachmentId: aws.String("tgw-attach-old"),
+				})).
+					Return(&ec2.DeleteTransitGatewayVpcAttachmentOutput{}, nil)
+				m.CreateTransitGatewayVpcAttachment(gomock.AssignableToTypeOf(&ec2.CreateTransitGatewayVpcAttachmentInput{})).
+					Return(&ec2.CreateTransitGatewayVpcAttachmentOutput{
+						TransitGatewayVpcAttachment: &ec2.TransitGatewayVpcAttachment{
+							TransitGatewayAttachmentId: aws.String("tgw-attach-1"),
+							TransitGatewayId:           aws.String("tgw-1"),
+							State:                      aws.String(ec2.TransitGatewayAttachmentStatePending),
+						},
+					}, nil)
+			},
+			expectedState: ec2.TransitGatewayAttachmentStatePending,
+			expectRoutes:  0,
+		},
+		{
+			name:    "transit gateway removed from the spec, deletes the attachment and its routes",
+			removed: true,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeTransitGatewayVpcAttachments(gomock.AssignableToTypeOf(&ec2.DescribeTransitGatewayVpcAttachmentsInput{})).
+					Return(&ec2.DescribeTransitGatewayVpcAttachmentsOutput{
+						TransitGatewayVpcAttachments: []*ec2.TransitGatewayVpcAttachment{
+							{
+								TransitGatewayAttachmentId: aws.String("tgw-attach-1"),
+								TransitGatewayId:           aws.String("tgw-1"),
+								State:                      aws.String(ec2.TransitGatewayAttachmentStateAvailable),
+							},
+						},
+					}, nil)
+				expectTransitGatewayRoutesDeleted(m, "tgw-1")
+				m.DeleteTransitGatewayVpcAttachment(gomock.Eq(&ec2.DeleteTransitGatewayVpcAttachmentInput{
+					TransitGatewayAttachmentId: aws.String("tgw-attach-1"),
+				})).
+					Return(&ec2.DeleteTransitGatewayVpcAttachmentOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			transitGateway := &infrav1.TransitGatewaySpec{
+				ID:                       "tgw-1",
+				AssociationRouteTableID:  aws.String("tgw-rtb-assoc"),
+				PropagationRouteTableIDs: []string{"tgw-rtb-prop"},
+				DestinationCidrBlocks:    []string{"192.168.0.0/16", "172.16.0.0/12"},
+			}
+			var status *infrav1.TransitGatewayAttachment
+			if tc.removed {
+				transitGateway = nil
+				status = &infrav1.TransitGatewayAttachment{
+					ID:    "tgw-attach-1",
+					State: ec2.TransitGatewayAttachmentStateAvailable,
+				}
+			}
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{
+								ID: "vpc-tgw",
+								Tags: infrav1.Tags{
+									infrav1.ClusterTagKey("test-cluster"): "owned",
+								},
+							},
+							Subnets: infrav1.Subnets{
+								&infrav1.SubnetSpec{ID: "subnet-private-1a", AvailabilityZone: "us-east-1a"},
+								&infrav1.SubnetSpec{ID: "subnet-private-1a-2", AvailabilityZone: "us-east-1a"},
+								&infrav1.SubnetSpec{ID: "subnet-public-1a", AvailabilityZone: "us-east-1a", IsPublic: true},
+							},
+							TransitGateway: transitGateway,
+						},
+					},
+					Status: infrav1.AWSClusterStatus{
+						Network: infrav1.Network{
+							TransitGatewayAttachment: status,
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			if err := s.reconcileTransitGatewayAttachment(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+
+			attachment := scope.Network().TransitGatewayAttachment
+			if tc.expectedState == "" {
+				if attachment != nil {
+					t.Fatalf("expected no transit gateway attachment status, got %+v", attachment)
+				}
+				return
+			}
+			if attachment == nil || attachment.ID != "tgw-attach-1" || attachment.State != tc.expectedState {
+				t.Fatalf("unexpected transit gateway attachment status: %+v", attachment)
+			}
+
+			if routes := s.getTransitGatewayRoutes(); len(routes) != tc.expectRoutes {
+				t.Fatalf("expected %d transit gateway routes, got %d", tc.expectRoutes, len(routes))
+			}
+		})
+	}
+}
+
+// expectTransitGatewayRoutesDeleted expects the routes through the transit gateway to be deleted from the
+// managed route tables, routes to other targets are kept.
+func expectTransitGatewayRoutesDeleted(m *mock_ec2iface.MockEC2APIMockRecorder, transitGatewayID string) {
+	m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+		Return(&ec2.DescribeRouteTablesOutput{
+			RouteTables: []*ec2.RouteTable{
+				{
+					RouteTableId: aws.String("rtb-private"),
+					Routes: []*ec2.Route{
+						{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-1")},
+						{DestinationCidrBlock: aws.String("192.168.0.0/16"), TransitGatewayId: aws.String(transitGatewayID)},
+					},
+				},
+			},
+		}, nil)
+	m.DeleteRoute(gomock.Eq(&ec2.DeleteRouteInput{
+		RouteTableId:         aws.String("rtb-private"),
+		DestinationCidrBlock: aws.String("192.168.0.0/16"),
+	})).
+		Return(&ec2.DeleteRouteOutput{}, nil)
+}
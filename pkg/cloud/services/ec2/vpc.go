@@ -54,7 +54,7 @@ func (s *Service) reconcileVPC() error {
 	}
 
 	if vpc.IsUnmanaged(s.scope.Name()) {
-		vpc.DeepCopyInto(s.scope.VPC())
+		s.updateVPCSpec(vpc)
 		s.scope.V(2).Info("Working on unmanaged VPC", "vpc-id", vpc.ID)
 		return nil
 	}
@@ -83,11 +83,20 @@ func (s *Service) reconcileVPC() error {
 		return errors.Wrapf(err, "failed to to set vpc attributes for %q", vpc.ID)
 	}
 
-	vpc.DeepCopyInto(s.scope.VPC())
+	s.updateVPCSpec(vpc)
 	s.scope.V(2).Info("Working on managed VPC", "vpc-id", vpc.ID)
 	return nil
 }
 
+// updateVPCSpec records the observed state of the VPC in the cluster spec, leaving
+// user provided configuration untouched.
+func (s *Service) updateVPCSpec(vpc *infrav1.VPCSpec) {
+	s.scope.VPC().ID = vpc.ID
+	s.scope.VPC().CidrBlock = vpc.CidrBlock
+	s.scope.VPC().InternetGatewayID = vpc.InternetGatewayID
+	s.scope.VPC().Tags = vpc.Tags
+}
+
 func (s *Service) ensureManagedVPCAttributes(vpc *infrav1.VPCSpec) error {
 	var (
 		errs    []error
@@ -157,6 +166,9 @@ func (s *Service) createVPC() (*infrav1.VPCSpec, error) {
 	input := &ec2.CreateVpcInput{
 		CidrBlock: aws.String(s.scope.VPC().CidrBlock),
 	}
+	if s.scope.VPC().InstanceTenancy != "" {
+		input.InstanceTenancy = aws.String(s.scope.VPC().InstanceTenancy)
+	}
 
 	out, err := s.scope.EC2.CreateVpc(input)
 	if err != nil {
@@ -193,9 +205,10 @@ func (s *Service) createVPC() (*infrav1.VPCSpec, error) {
 	record.Eventf(s.scope.AWSCluster, "SuccessfulTagVPC", "Tagged managed VPC %q", *out.Vpc.VpcId)
 
 	return &infrav1.VPCSpec{
-		ID:        *out.Vpc.VpcId,
-		CidrBlock: *out.Vpc.CidrBlock,
-		Tags:      infrav1.Build(tagParams),
+		ID:              *out.Vpc.VpcId,
+		CidrBlock:       *out.Vpc.CidrBlock,
+		InstanceTenancy: aws.StringValue(out.Vpc.InstanceTenancy),
+		Tags:            infrav1.Build(tagParams),
 	}, nil
 }
 
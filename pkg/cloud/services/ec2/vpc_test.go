@@ -154,6 +154,39 @@ func TestReconcileVPC(t *testing.T) {
 					Return(nil, nil)
 			},
 		},
+		{
+			name:   "managed vpc does not exist, creates it with dedicated tenancy",
+			input:  &infrav1.VPCSpec{CidrBlock: "10.1.0.0/16", InstanceTenancy: ec2.TenancyDedicated},
+			output: &infrav1.VPCSpec{ID: "vpc-new", CidrBlock: "10.1.0.0/16", InstanceTenancy: ec2.TenancyDedicated},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+					Return(&ec2.DescribeVpcsOutput{}, nil)
+
+				m.CreateVpc(gomock.Eq(&ec2.CreateVpcInput{
+					CidrBlock:       aws.String("10.1.0.0/16"),
+					InstanceTenancy: aws.String(ec2.TenancyDedicated),
+				})).
+					Return(&ec2.CreateVpcOutput{
+						Vpc: &ec2.Vpc{
+							State:           aws.String("available"),
+							VpcId:           aws.String("vpc-new"),
+							CidrBlock:       aws.String("10.1.0.0/16"),
+							InstanceTenancy: aws.String(ec2.TenancyDedicated),
+						},
+					}, nil)
+
+				m.DescribeVpcAttribute(gomock.AssignableToTypeOf(&ec2.DescribeVpcAttributeInput{})).
+					DoAndReturn(describeVpcAttributeTrue).MinTimes(1)
+
+				m.WaitUntilVpcAvailable(gomock.Eq(&ec2.DescribeVpcsInput{
+					VpcIds: []*string{aws.String("vpc-new")},
+				})).
+					Return(nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
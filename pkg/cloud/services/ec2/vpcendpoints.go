@@ -0,0 +1,375 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// vpcEndpointRoleTagValue describes the value for the VPC endpoint role
+	vpcEndpointRoleTagValue = "vpc-endpoint"
+
+	// s3EndpointService is the only service reached through a gateway endpoint.
+	s3EndpointService = "s3"
+)
+
+var (
+	// defaultPrivateEndpointServices are the services a private cluster needs to bootstrap.
+	defaultPrivateEndpointServices = []string{
+		s3EndpointService,
+		"ecr.api",
+		"ecr.dkr",
+		"ec2",
+		"elasticloadbalancing",
+		"sts",
+		"secretsmanager",
+	}
+
+	// sessionManagerEndpointServices are the services the SSM agent needs to reach Session Manager.
+	sessionManagerEndpointServices = []string{
+		"ssm",
+		"ssmmessages",
+		"ec2messages",
+	}
+
+	// vpcEndpointServicePrefixes are the prefixes of the endpoint service names of AWS services. Some
+	// services are named with the "cn.com.amazonaws" prefix in the China regions, the names are therefore
+	// looked up from the endpoint services available in the region.
+	vpcEndpointServicePrefixes = []string{
+		"com.amazonaws",
+		"cn.com.amazonaws",
+	}
+)
+
+func (s *Service) privateEndpointsEnabled() bool {
+	return s.scope.VPC().PrivateEndpoints != nil && !s.scope.VPC().IsUnmanaged(s.scope.Name())
+}
+
+func (s *Service) privateEndpointServices() []string {
+	services := s.scope.VPC().PrivateEndpoints.Services
+	if len(services) == 0 {
+		services = defaultPrivateEndpointServices
+	}
+
+	if !s.scope.AWSCluster.Spec.SessionManager.Enabled {
+		return services
+	}
+
+	present := map[string]bool{}
+	for _, svc := range services {
+		present[svc] = true
+	}
+
+	result := append([]string{}, services...)
+	for _, svc := range sessionManagerEndpointServices {
+		if !present[svc] {
+			result = append(result, svc)
+		}
+	}
+	return result
+}
+
+func (s *Service) reconcileVPCEndpoints() error {
+	if !s.privateEndpointsEnabled() {
+		s.scope.V(4).Info("Skipping VPC endpoints reconcile")
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling VPC endpoints")
+
+	existing, err := s.describeVPCEndpointsByService()
+	if err != nil {
+		return err
+	}
+
+	services := s.privateEndpointServices()
+	serviceNames, err := s.describeVPCEndpointServiceNames(services)
+	if err != nil {
+		return err
+	}
+
+	for _, svc := range services {
+		serviceName, ok := serviceNames[svc]
+		if !ok {
+			record.Warnf(s.scope.AWSCluster, "FailedCreateVPCEndpoint", "VPC endpoint service %q is not available in region %q", svc, s.scope.Region())
+			return errors.Errorf("vpc endpoint service %q is not available in region %q", svc, s.scope.Region())
+		}
+
+		if ep, ok := existing[serviceName]; ok {
+			// Make sure tags are up to date.
+			if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+				if err := tags.Ensure(converters.TagsToMap(ep.Tags), &tags.ApplyParams{
+					EC2Client:   s.scope.EC2,
+					BuildParams: s.getVPCEndpointTagParams(*ep.VpcEndpointId, svc),
+				}); err != nil {
+					return false, err
+				}
+				return true, nil
+			}, awserrors.ResourceNotFound); err != nil {
+				record.Warnf(s.scope.AWSCluster, "FailedTagVPCEndpoint", "Failed to tag managed VPC endpoint %q: %v", *ep.VpcEndpointId, err)
+				return errors.Wrapf(err, "failed to tag vpc endpoint %q", *ep.VpcEndpointId)
+			}
+			if svc == s3EndpointService {
+				if err := s.reconcileGatewayEndpointRouteTables(ep); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := s.createVPCEndpoint(svc, serviceName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) createVPCEndpoint(svc, serviceName string) error {
+	input := &ec2.CreateVpcEndpointInput{
+		VpcId:       aws.String(s.scope.VPC().ID),
+		ServiceName: aws.String(serviceName),
+	}
+
+	if svc == s3EndpointService {
+		input.VpcEndpointType = aws.String(ec2.VpcEndpointTypeGateway)
+		input.RouteTableIds = aws.StringSlice(s.gatewayEndpointRouteTableIDs())
+	} else {
+		input.VpcEndpointType = aws.String(ec2.VpcEndpointTypeInterface)
+		input.PrivateDnsEnabled = aws.Bool(true)
+		input.SecurityGroupIds = aws.StringSlice([]string{s.scope.SecurityGroups()[infrav1.SecurityGroupVPCEndpoint].ID})
+		// Interface endpoints accept at most one subnet per availability zone.
+		zones := map[string]bool{}
+		for _, sn := range s.scope.Subnets().FilterPrivate() {
+			if sn.ID == "" || zones[sn.AvailabilityZone] {
+				continue
+			}
+			zones[sn.AvailabilityZone] = true
+			input.SubnetIds = append(input.SubnetIds, aws.String(sn.ID))
+		}
+	}
+
+	out, err := s.scope.EC2.CreateVpcEndpoint(input)
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreateVPCEndpoint", "Failed to create new managed VPC endpoint for service %q: %v", svc, err)
+		return errors.Wrapf(err, "failed to create vpc endpoint for service %q", svc)
+	}
+
+	endpointID := aws.StringValue(out.VpcEndpoint.VpcEndpointId)
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreateVPCEndpoint", "Created new managed VPC endpoint %q for service %q", endpointID, svc)
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if err := tags.Apply(&tags.ApplyParams{
+			EC2Client:   s.scope.EC2,
+			BuildParams: s.getVPCEndpointTagParams(endpointID, svc),
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.ResourceNotFound); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedTagVPCEndpoint", "Failed to tag managed VPC endpoint %q: %v", endpointID, err)
+		return errors.Wrapf(err, "failed to tag vpc endpoint %q", endpointID)
+	}
+
+	s.scope.V(2).Info("Created VPC endpoint", "vpc-endpoint-id", endpointID, "service", svc)
+	return nil
+}
+
+// gatewayEndpointRouteTableIDs returns the route tables of the cluster subnets, which the gateway endpoint is added to.
+func (s *Service) gatewayEndpointRouteTableIDs() []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, sn := range s.scope.Subnets() {
+		id := aws.StringValue(sn.RouteTableID)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// reconcileGatewayEndpointRouteTables adds the gateway endpoint to the route tables of the cluster subnets
+// and removes it from the route tables that are no longer used by the cluster.
+func (s *Service) reconcileGatewayEndpointRouteTables(ep *ec2.VpcEndpoint) error {
+	desired := s.gatewayEndpointRouteTableIDs()
+
+	current := map[string]bool{}
+	for _, id := range aws.StringValueSlice(ep.RouteTableIds) {
+		current[id] = true
+	}
+	wanted := map[string]bool{}
+	for _, id := range desired {
+		wanted[id] = true
+	}
+
+	input := &ec2.ModifyVpcEndpointInput{VpcEndpointId: ep.VpcEndpointId}
+	for _, id := range desired {
+		if !current[id] {
+			input.AddRouteTableIds = append(input.AddRouteTableIds, aws.String(id))
+		}
+	}
+	for _, id := range aws.StringValueSlice(ep.RouteTableIds) {
+		if !wanted[id] {
+			input.RemoveRouteTableIds = append(input.RemoveRouteTableIds, aws.String(id))
+		}
+	}
+
+	if len(input.AddRouteTableIds) == 0 && len(input.RemoveRouteTableIds) == 0 {
+		return nil
+	}
+
+	endpointID := aws.StringValue(ep.VpcEndpointId)
+	if _, err := s.scope.EC2.ModifyVpcEndpoint(input); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedModifyVPCEndpoint", "Failed to update the route tables of managed VPC endpoint %q: %v", endpointID, err)
+		return errors.Wrapf(err, "failed to update route tables of vpc endpoint %q", endpointID)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulModifyVPCEndpoint", "Updated the route tables of managed VPC endpoint %q", endpointID)
+	s.scope.V(2).Info("Updated VPC endpoint route tables", "vpc-endpoint-id", endpointID,
+		"added", aws.StringValueSlice(input.AddRouteTableIds), "removed", aws.StringValueSlice(input.RemoveRouteTableIds))
+	return nil
+}
+
+func (s *Service) deleteVPCEndpoints() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping VPC endpoints deletion in unmanaged mode")
+		return nil
+	}
+
+	existing, err := s.describeVPCEndpointsByService()
+	if err != nil {
+		return err
+	}
+
+	if len(existing) == 0 {
+		return nil
+	}
+
+	ids := make([]*string, 0, len(existing))
+	for _, ep := range existing {
+		ids = append(ids, ep.VpcEndpointId)
+	}
+
+	out, err := s.scope.EC2.DeleteVpcEndpoints(&ec2.DeleteVpcEndpointsInput{VpcEndpointIds: ids})
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteVPCEndpoints", "Failed to delete managed VPC endpoints: %v", err)
+		return errors.Wrap(err, "failed to delete vpc endpoints")
+	}
+	if len(out.Unsuccessful) > 0 {
+		item := out.Unsuccessful[0]
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteVPCEndpoints", "Failed to delete managed VPC endpoint %q: %s", aws.StringValue(item.ResourceId), item.Error.String())
+		return errors.Errorf("failed to delete vpc endpoint %q: %s", aws.StringValue(item.ResourceId), item.Error.String())
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteVPCEndpoints", "Deleted %d managed VPC endpoints", len(ids))
+	s.scope.V(2).Info("Deleted VPC endpoints", "count", len(ids))
+	return nil
+}
+
+func (s *Service) describeVPCEndpointsByService() (map[string]*ec2.VpcEndpoint, error) {
+	input := &ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.Cluster(s.scope.Name()),
+		},
+	}
+
+	endpoints := make(map[string]*ec2.VpcEndpoint)
+	for {
+		out, err := s.scope.EC2.DescribeVpcEndpoints(input)
+		if err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedDescribeVPCEndpoints", "Failed to describe VPC endpoints in vpc %q: %v", s.scope.VPC().ID, err)
+			return nil, errors.Wrapf(err, "failed to describe vpc endpoints in vpc %q", s.scope.VPC().ID)
+		}
+
+		for _, ep := range out.VpcEndpoints {
+			switch aws.StringValue(ep.State) {
+			case "deleting", "deleted", "failed", "rejected":
+				continue
+			}
+			endpoints[aws.StringValue(ep.ServiceName)] = ep
+		}
+
+		if aws.StringValue(out.NextToken) == "" {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+
+	return endpoints, nil
+}
+
+// describeVPCEndpointServiceNames returns the endpoint service names of the given services, keyed by service.
+// Services that aren't available in the region are left out.
+func (s *Service) describeVPCEndpointServiceNames(services []string) (map[string]string, error) {
+	candidates := make(map[string]string)
+	for _, svc := range services {
+		for _, prefix := range vpcEndpointServicePrefixes {
+			candidates[fmt.Sprintf("%s.%s.%s", prefix, s.scope.Region(), svc)] = svc
+		}
+	}
+
+	input := &ec2.DescribeVpcEndpointServicesInput{}
+	names := make(map[string]string)
+	for {
+		out, err := s.scope.EC2.DescribeVpcEndpointServices(input)
+		if err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedDescribeVPCEndpointServices", "Failed to describe VPC endpoint services: %v", err)
+			return nil, errors.Wrap(err, "failed to describe vpc endpoint services")
+		}
+
+		for _, name := range aws.StringValueSlice(out.ServiceNames) {
+			if svc, ok := candidates[name]; ok {
+				names[svc] = name
+			}
+		}
+
+		if aws.StringValue(out.NextToken) == "" {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+
+	return names, nil
+}
+
+func (s *Service) getVPCEndpointTagParams(id, svc string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-vpce-%s", s.scope.Name(), svc)
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(vpcEndpointRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
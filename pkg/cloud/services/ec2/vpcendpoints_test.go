@@ -0,0 +1,314 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcileVPCEndpoints(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name      string
+		region    string
+		input     infrav1.VPCSpec
+		expect    func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name: "private endpoints disabled",
+			input: infrav1.VPCSpec{
+				ID: "vpc-endpoints",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey("test-cluster"): "owned",
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+		{
+			name: "unmanaged vpc",
+			input: infrav1.VPCSpec{
+				ID:               "vpc-endpoints",
+				PrivateEndpoints: &infrav1.PrivateEndpointsSpec{},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+		{
+			name: "creates missing endpoints",
+			input: infrav1.VPCSpec{
+				ID: "vpc-endpoints",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey("test-cluster"): "owned",
+				},
+				PrivateEndpoints: &infrav1.PrivateEndpointsSpec{
+					Services: []string{"s3", "ecr.api", "sts"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcEndpoints(gomock.AssignableToTypeOf(&ec2.DescribeVpcEndpointsInput{})).
+					Return(&ec2.DescribeVpcEndpointsOutput{
+						VpcEndpoints: []*ec2.VpcEndpoint{
+							{
+								VpcEndpointId: aws.String("vpce-sts"),
+								ServiceName:   aws.String("com.amazonaws.us-east-1.sts"),
+								State:         aws.String("available"),
+								Tags: []*ec2.Tag{
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+										Value: aws.String("owned"),
+									},
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+										Value: aws.String("vpc-endpoint"),
+									},
+									{
+										Key:   aws.String("Name"),
+										Value: aws.String("test-cluster-vpce-sts"),
+									},
+								},
+							},
+						},
+					}, nil)
+
+				m.DescribeVpcEndpointServices(gomock.Eq(&ec2.DescribeVpcEndpointServicesInput{})).
+					Return(&ec2.DescribeVpcEndpointServicesOutput{
+						ServiceNames: aws.StringSlice([]string{
+							"com.amazonaws.us-east-1.ec2",
+							"com.amazonaws.us-east-1.ecr.api",
+							"com.amazonaws.us-east-1.s3",
+						}),
+						NextToken: aws.String("next"),
+					}, nil)
+				m.DescribeVpcEndpointServices(gomock.Eq(&ec2.DescribeVpcEndpointServicesInput{NextToken: aws.String("next")})).
+					Return(&ec2.DescribeVpcEndpointServicesOutput{
+						ServiceNames: aws.StringSlice([]string{
+							"com.amazonaws.us-east-1.sts",
+						}),
+					}, nil)
+
+				m.CreateVpcEndpoint(gomock.Eq(&ec2.CreateVpcEndpointInput{
+					VpcId:           aws.String("vpc-endpoints"),
+					ServiceName:     aws.String("com.amazonaws.us-east-1.s3"),
+					VpcEndpointType: aws.String("Gateway"),
+					RouteTableIds:   aws.StringSlice([]string{"rtb-private", "rtb-public"}),
+				})).
+					Return(&ec2.CreateVpcEndpointOutput{VpcEndpoint: &ec2.VpcEndpoint{VpcEndpointId: aws.String("vpce-s3")}}, nil)
+
+				m.CreateVpcEndpoint(gomock.Eq(&ec2.CreateVpcEndpointInput{
+					VpcId:             aws.String("vpc-endpoints"),
+					ServiceName:       aws.String("com.amazonaws.us-east-1.ecr.api"),
+					VpcEndpointType:   aws.String("Interface"),
+					PrivateDnsEnabled: aws.Bool(true),
+					SecurityGroupIds:  aws.StringSlice([]string{"sg-endpoint"}),
+					SubnetIds:         aws.StringSlice([]string{"subnet-private"}),
+				})).
+					Return(&ec2.CreateVpcEndpointOutput{VpcEndpoint: &ec2.VpcEndpoint{VpcEndpointId: aws.String("vpce-ecr-api")}}, nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil).
+					Times(2)
+			},
+		},
+		{
+			name: "updates the route tables of the gateway endpoint",
+			input: infrav1.VPCSpec{
+				ID: "vpc-endpoints",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey("test-cluster"): "owned",
+				},
+				PrivateEndpoints: &infrav1.PrivateEndpointsSpec{
+					Services: []string{"s3"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcEndpoints(gomock.AssignableToTypeOf(&ec2.DescribeVpcEndpointsInput{})).
+					Return(&ec2.DescribeVpcEndpointsOutput{
+						VpcEndpoints: []*ec2.VpcEndpoint{
+							{
+								VpcEndpointId: aws.String("vpce-s3"),
+								ServiceName:   aws.String("com.amazonaws.us-east-1.s3"),
+								State:         aws.String("available"),
+								RouteTableIds: aws.StringSlice([]string{"rtb-private", "rtb-removed"}),
+								Tags: []*ec2.Tag{
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+										Value: aws.String("owned"),
+									},
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+										Value: aws.String("vpc-endpoint"),
+									},
+									{
+										Key:   aws.String("Name"),
+										Value: aws.String("test-cluster-vpce-s3"),
+									},
+								},
+							},
+						},
+					}, nil)
+
+				m.DescribeVpcEndpointServices(gomock.Eq(&ec2.DescribeVpcEndpointServicesInput{})).
+					Return(&ec2.DescribeVpcEndpointServicesOutput{
+						ServiceNames: aws.StringSlice([]string{"com.amazonaws.us-east-1.s3"}),
+					}, nil)
+
+				m.ModifyVpcEndpoint(gomock.Eq(&ec2.ModifyVpcEndpointInput{
+					VpcEndpointId:       aws.String("vpce-s3"),
+					AddRouteTableIds:    aws.StringSlice([]string{"rtb-public"}),
+					RemoveRouteTableIds: aws.StringSlice([]string{"rtb-removed"}),
+				})).
+					Return(&ec2.ModifyVpcEndpointOutput{}, nil)
+			},
+		},
+		{
+			name:   "uses the service names of the region",
+			region: "cn-north-1",
+			input: infrav1.VPCSpec{
+				ID: "vpc-endpoints",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey("test-cluster"): "owned",
+				},
+				PrivateEndpoints: &infrav1.PrivateEndpointsSpec{
+					Services: []string{"ecr.api"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcEndpoints(gomock.AssignableToTypeOf(&ec2.DescribeVpcEndpointsInput{})).
+					Return(&ec2.DescribeVpcEndpointsOutput{}, nil)
+
+				m.DescribeVpcEndpointServices(gomock.Eq(&ec2.DescribeVpcEndpointServicesInput{})).
+					Return(&ec2.DescribeVpcEndpointServicesOutput{
+						ServiceNames: aws.StringSlice([]string{
+							"cn.com.amazonaws.cn-north-1.ecr.api",
+							"com.amazonaws.cn-north-1.ec2",
+						}),
+					}, nil)
+
+				m.CreateVpcEndpoint(gomock.Eq(&ec2.CreateVpcEndpointInput{
+					VpcId:             aws.String("vpc-endpoints"),
+					ServiceName:       aws.String("cn.com.amazonaws.cn-north-1.ecr.api"),
+					VpcEndpointType:   aws.String("Interface"),
+					PrivateDnsEnabled: aws.Bool(true),
+					SecurityGroupIds:  aws.StringSlice([]string{"sg-endpoint"}),
+					SubnetIds:         aws.StringSlice([]string{"subnet-private"}),
+				})).
+					Return(&ec2.CreateVpcEndpointOutput{VpcEndpoint: &ec2.VpcEndpoint{VpcEndpointId: aws.String("vpce-ecr-api")}}, nil)
+
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+		},
+		{
+			name: "fails on services not available in the region",
+			input: infrav1.VPCSpec{
+				ID: "vpc-endpoints",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey("test-cluster"): "owned",
+				},
+				PrivateEndpoints: &infrav1.PrivateEndpointsSpec{
+					Services: []string{"unknown"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcEndpoints(gomock.AssignableToTypeOf(&ec2.DescribeVpcEndpointsInput{})).
+					Return(&ec2.DescribeVpcEndpointsOutput{}, nil)
+
+				m.DescribeVpcEndpointServices(gomock.Eq(&ec2.DescribeVpcEndpointServicesInput{})).
+					Return(&ec2.DescribeVpcEndpointServicesOutput{
+						ServiceNames: aws.StringSlice([]string{"com.amazonaws.us-east-1.ec2"}),
+					}, nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			region := tc.region
+			if region == "" {
+				region = "us-east-1"
+			}
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						Region: region,
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: tc.input,
+							Subnets: infrav1.Subnets{
+								&infrav1.SubnetSpec{
+									ID:               "subnet-private",
+									AvailabilityZone: "us-east-1a",
+									RouteTableID:     aws.String("rtb-private"),
+								},
+								&infrav1.SubnetSpec{
+									ID:               "subnet-public",
+									AvailabilityZone: "us-east-1a",
+									IsPublic:         true,
+									RouteTableID:     aws.String("rtb-public"),
+								},
+							},
+						},
+					},
+					Status: infrav1.AWSClusterStatus{
+						Network: infrav1.Network{
+							SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+								infrav1.SecurityGroupVPCEndpoint: {ID: "sg-endpoint"},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			err = s.reconcileVPCEndpoints()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,402 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// vpcPeeringConnectionRoleTagValue describes the value for the VPC peering connection role
+	vpcPeeringConnectionRoleTagValue = "vpc-peering"
+)
+
+func (s *Service) reconcileVPCPeering() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping VPC peering reconcile in unmanaged mode")
+		return nil
+	}
+
+	spec := s.scope.VPC().Peering
+	if spec == nil && s.scope.Network().VPCPeeringConnection == nil {
+		return nil
+	}
+
+	// Delete the connections to a peer VPC that was removed from or replaced in the spec.
+	peerVPCID := ""
+	if spec != nil {
+		peerVPCID = spec.PeerVPCID
+	}
+	if err := s.deleteUnwantedVPCPeeringConnections(peerVPCID); err != nil {
+		return err
+	}
+
+	if spec == nil {
+		s.scope.Network().VPCPeeringConnection = nil
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling VPC peering connection", "peer-vpc-id", spec.PeerVPCID)
+
+	pcx, err := s.describeVPCPeeringConnection(spec.PeerVPCID)
+	if err != nil {
+		return err
+	}
+
+	if pcx == nil {
+		pcx, err = s.createVPCPeeringConnection(spec)
+		if err != nil {
+			return err
+		}
+	}
+
+	id := aws.StringValue(pcx.VpcPeeringConnectionId)
+	if pcx.Status != nil && aws.StringValue(pcx.Status.Code) == ec2.VpcPeeringConnectionStateReasonCodePendingAcceptance {
+		pcx, err = s.acceptVPCPeeringConnection(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	peerCidrBlock, err := s.getPeerCidrBlock(pcx)
+	if err != nil {
+		return err
+	}
+
+	state := ""
+	if pcx.Status != nil {
+		state = aws.StringValue(pcx.Status.Code)
+	}
+	s.scope.Network().VPCPeeringConnection = &infrav1.VPCPeeringConnection{
+		ID:            id,
+		State:         state,
+		PeerCidrBlock: peerCidrBlock,
+	}
+
+	// Routes through the peering connection can only be created once the connection is active.
+	if state != ec2.VpcPeeringConnectionStateReasonCodeActive {
+		s.scope.V(2).Info("Waiting for VPC peering connection to become active", "vpc-peering-connection-id", id, "state", state)
+		return nil
+	}
+
+	return s.reconcilePeerRoutes(spec, id)
+}
+
+func (s *Service) createVPCPeeringConnection(spec *infrav1.VPCPeeringSpec) (*ec2.VpcPeeringConnection, error) {
+	out, err := s.scope.EC2.CreateVpcPeeringConnection(&ec2.CreateVpcPeeringConnectionInput{
+		VpcId:     aws.String(s.scope.VPC().ID),
+		PeerVpcId: aws.String(spec.PeerVPCID),
+	})
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedCreateVPCPeeringConnection", "Failed to peer managed VPC %q with VPC %q: %v", s.scope.VPC().ID, spec.PeerVPCID, err)
+		return nil, errors.Wrapf(err, "failed to create peering connection between vpc %q and vpc %q", s.scope.VPC().ID, spec.PeerVPCID)
+	}
+
+	id := aws.StringValue(out.VpcPeeringConnection.VpcPeeringConnectionId)
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreateVPCPeeringConnection", "Created VPC peering connection %q between managed VPC %q and VPC %q", id, s.scope.VPC().ID, spec.PeerVPCID)
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if err := tags.Apply(&tags.ApplyParams{
+			EC2Client:   s.scope.EC2,
+			BuildParams: s.getVPCPeeringConnectionTagParams(id),
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.ResourceNotFound); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedTagVPCPeeringConnection", "Failed to tag managed VPC peering connection %q: %v", id, err)
+		return nil, errors.Wrapf(err, "failed to tag vpc peering connection %q", id)
+	}
+
+	s.scope.V(2).Info("Created VPC peering connection", "vpc-peering-connection-id", id)
+	return out.VpcPeeringConnection, nil
+}
+
+func (s *Service) acceptVPCPeeringConnection(id string) (*ec2.VpcPeeringConnection, error) {
+	out, err := s.scope.EC2.AcceptVpcPeeringConnection(&ec2.AcceptVpcPeeringConnectionInput{
+		VpcPeeringConnectionId: aws.String(id),
+	})
+	if err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedAcceptVPCPeeringConnection", "Failed to accept VPC peering connection %q: %v", id, err)
+		return nil, errors.Wrapf(err, "failed to accept vpc peering connection %q", id)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulAcceptVPCPeeringConnection", "Accepted VPC peering connection %q", id)
+	return out.VpcPeeringConnection, nil
+}
+
+// reconcilePeerRoutes makes sure the route tables of the peer VPC route the managed VPC through the peering connection.
+func (s *Service) reconcilePeerRoutes(spec *infrav1.VPCPeeringSpec, pcxID string) error {
+	rts, err := s.describePeerRouteTables(spec)
+	if err != nil {
+		return err
+	}
+
+	route := &ec2.Route{
+		DestinationCidrBlock:   aws.String(s.scope.VPC().CidrBlock),
+		VpcPeeringConnectionId: aws.String(pcxID),
+	}
+
+	for _, rt := range rts {
+		current := findRouteByDestination(rt.Routes, s.scope.VPC().CidrBlock)
+		if current == nil {
+			if err := s.createRoute(rt.RouteTableId, route); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if aws.StringValue(current.VpcPeeringConnectionId) == pcxID {
+			continue
+		}
+
+		// Only replace routes left behind by a peering connection that no longer exists,
+		// anything else was put there by someone else and must not be hijacked.
+		if aws.StringValue(current.State) != ec2.RouteStateBlackhole {
+			return errors.Errorf("failed to create route to %q in peer route table %q: a route to a different target already exists", s.scope.VPC().CidrBlock, *rt.RouteTableId)
+		}
+
+		if _, err := s.scope.EC2.ReplaceRoute(&ec2.ReplaceRouteInput{
+			RouteTableId:           rt.RouteTableId,
+			DestinationCidrBlock:   route.DestinationCidrBlock,
+			VpcPeeringConnectionId: route.VpcPeeringConnectionId,
+		}); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedReplaceRoute", "Failed to replace outdated route on peer RouteTable %q: %v", *rt.RouteTableId, err)
+			return errors.Wrapf(err, "failed to replace outdated route on peer route table %q", *rt.RouteTableId)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteVPCPeering() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping VPC peering deletion in unmanaged mode")
+		return nil
+	}
+
+	if err := s.deleteUnwantedVPCPeeringConnections(""); err != nil {
+		return err
+	}
+
+	s.scope.Network().VPCPeeringConnection = nil
+	return nil
+}
+
+// deleteUnwantedVPCPeeringConnections deletes the managed peering connections to any VPC other than the given
+// peer VPC, or all of them when no peer VPC is given.
+func (s *Service) deleteUnwantedVPCPeeringConnections(peerVPCID string) error {
+	existing, err := s.describeVPCPeeringConnections()
+	if err != nil {
+		return err
+	}
+
+	for _, pcx := range existing {
+		if peerVPCID != "" && pcx.AccepterVpcInfo != nil && aws.StringValue(pcx.AccepterVpcInfo.VpcId) == peerVPCID {
+			continue
+		}
+		if err := s.deleteVPCPeeringConnection(pcx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteVPCPeeringConnection(pcx *ec2.VpcPeeringConnection) error {
+	id := aws.StringValue(pcx.VpcPeeringConnectionId)
+	if err := s.deleteVPCPeeringRoutes(pcx); err != nil {
+		return err
+	}
+
+	if _, err := s.scope.EC2.DeleteVpcPeeringConnection(&ec2.DeleteVpcPeeringConnectionInput{
+		VpcPeeringConnectionId: pcx.VpcPeeringConnectionId,
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteVPCPeeringConnection", "Failed to delete managed VPC peering connection %q: %v", id, err)
+		return errors.Wrapf(err, "failed to delete vpc peering connection %q", id)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteVPCPeeringConnection", "Deleted managed VPC peering connection %q", id)
+	s.scope.V(2).Info("Deleted VPC peering connection", "vpc-peering-connection-id", id)
+	return nil
+}
+
+// deleteVPCPeeringRoutes removes the routes through the peering connection from the route tables
+// of both the managed VPC and the peer VPC.
+func (s *Service) deleteVPCPeeringRoutes(pcx *ec2.VpcPeeringConnection) error {
+	out, err := s.scope.EC2.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("route.vpc-peering-connection-id"),
+				Values: []*string{pcx.VpcPeeringConnectionId},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe route tables with routes through vpc peering connection %q", aws.StringValue(pcx.VpcPeeringConnectionId))
+	}
+
+	for _, rt := range out.RouteTables {
+		for _, route := range rt.Routes {
+			if aws.StringValue(route.VpcPeeringConnectionId) != aws.StringValue(pcx.VpcPeeringConnectionId) {
+				continue
+			}
+
+			if _, err := s.scope.EC2.DeleteRoute(&ec2.DeleteRouteInput{
+				RouteTableId:         rt.RouteTableId,
+				DestinationCidrBlock: route.DestinationCidrBlock,
+			}); err != nil {
+				record.Warnf(s.scope.AWSCluster, "FailedDeleteRoute", "Failed to delete route to %q from RouteTable %q: %v", aws.StringValue(route.DestinationCidrBlock), *rt.RouteTableId, err)
+				return errors.Wrapf(err, "failed to delete route to %q from route table %q", aws.StringValue(route.DestinationCidrBlock), *rt.RouteTableId)
+			}
+
+			record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteRoute", "Deleted route to %q from RouteTable %q", aws.StringValue(route.DestinationCidrBlock), *rt.RouteTableId)
+		}
+	}
+
+	return nil
+}
+
+// describeVPCPeeringConnection returns the managed peering connection with the given peer VPC, if any.
+func (s *Service) describeVPCPeeringConnection(peerVPCID string) (*ec2.VpcPeeringConnection, error) {
+	out, err := s.describeVPCPeeringConnectionsWithFilters(&ec2.Filter{
+		Name:   aws.String("accepter-vpc-info.vpc-id"),
+		Values: []*string{aws.String(peerVPCID)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out) == 0 {
+		return nil, nil
+	}
+
+	return out[0], nil
+}
+
+// describeVPCPeeringConnections returns the managed peering connections of the VPC.
+func (s *Service) describeVPCPeeringConnections() ([]*ec2.VpcPeeringConnection, error) {
+	return s.describeVPCPeeringConnectionsWithFilters()
+}
+
+func (s *Service) describeVPCPeeringConnectionsWithFilters(filters ...*ec2.Filter) ([]*ec2.VpcPeeringConnection, error) {
+	out, err := s.scope.EC2.DescribeVpcPeeringConnections(&ec2.DescribeVpcPeeringConnectionsInput{
+		Filters: append([]*ec2.Filter{
+			{
+				Name:   aws.String("requester-vpc-info.vpc-id"),
+				Values: []*string{aws.String(s.scope.VPC().ID)},
+			},
+			filter.EC2.ProviderOwned(s.scope.Name()),
+			filter.EC2.ProviderRole(vpcPeeringConnectionRoleTagValue),
+			{
+				Name: aws.String("status-code"),
+				Values: aws.StringSlice([]string{
+					ec2.VpcPeeringConnectionStateReasonCodeInitiatingRequest,
+					ec2.VpcPeeringConnectionStateReasonCodePendingAcceptance,
+					ec2.VpcPeeringConnectionStateReasonCodeProvisioning,
+					ec2.VpcPeeringConnectionStateReasonCodeActive,
+				}),
+			},
+		}, filters...),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe vpc peering connections for vpc %q", s.scope.VPC().ID)
+	}
+
+	return out.VpcPeeringConnections, nil
+}
+
+func (s *Service) describePeerRouteTables(spec *infrav1.VPCPeeringSpec) ([]*ec2.RouteTable, error) {
+	input := &ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{filter.EC2.VPC(spec.PeerVPCID)},
+	}
+	if len(spec.PeerRouteTableIDs) > 0 {
+		input.RouteTableIds = aws.StringSlice(spec.PeerRouteTableIDs)
+	}
+
+	out, err := s.scope.EC2.DescribeRouteTables(input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe route tables of peer vpc %q", spec.PeerVPCID)
+	}
+
+	return out.RouteTables, nil
+}
+
+// getPeerCidrBlock returns the CIDR block of the peer VPC. The accepter information of a peering connection
+// only carries the CIDR block once it has been accepted, so it falls back to looking up the peer VPC.
+func (s *Service) getPeerCidrBlock(pcx *ec2.VpcPeeringConnection) (string, error) {
+	if pcx.AccepterVpcInfo != nil && aws.StringValue(pcx.AccepterVpcInfo.CidrBlock) != "" {
+		return aws.StringValue(pcx.AccepterVpcInfo.CidrBlock), nil
+	}
+
+	peerVPCID := s.scope.VPC().Peering.PeerVPCID
+	out, err := s.scope.EC2.DescribeVpcs(&ec2.DescribeVpcsInput{
+		VpcIds: []*string{aws.String(peerVPCID)},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe peer vpc %q", peerVPCID)
+	}
+	if len(out.Vpcs) == 0 {
+		return "", awserrors.NewNotFound(errors.Errorf("could not find peer vpc %q", peerVPCID))
+	}
+
+	return aws.StringValue(out.Vpcs[0].CidrBlock), nil
+}
+
+// vpcPeeringRoutesReady returns true when routes through the VPC peering connection can be created.
+func (s *Service) vpcPeeringRoutesReady() bool {
+	pcx := s.scope.Network().VPCPeeringConnection
+	return s.scope.VPC().Peering != nil && pcx != nil &&
+		pcx.State == ec2.VpcPeeringConnectionStateReasonCodeActive && pcx.PeerCidrBlock != ""
+}
+
+func (s *Service) getVPCPeeringRoutes() []*ec2.Route {
+	if !s.vpcPeeringRoutesReady() {
+		return nil
+	}
+
+	pcx := s.scope.Network().VPCPeeringConnection
+	return []*ec2.Route{
+		{
+			DestinationCidrBlock:   aws.String(pcx.PeerCidrBlock),
+			VpcPeeringConnectionId: aws.String(pcx.ID),
+		},
+	}
+}
+
+func (s *Service) getVPCPeeringConnectionTagParams(id string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-vpc-peering", s.scope.Name())
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(vpcPeeringConnectionRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func TestReconcileVPCPeering(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	activePcx := &ec2.VpcPeeringConnection{
+		VpcPeeringConnectionId: aws.String("pcx-1"),
+		Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String(ec2.VpcPeeringConnectionStateReasonCodeActive)},
+		AccepterVpcInfo: &ec2.VpcPeeringConnectionVpcInfo{
+			VpcId:     aws.String("vpc-management"),
+			CidrBlock: aws.String("172.16.0.0/16"),
+		},
+	}
+	stalePcx := &ec2.VpcPeeringConnection{
+		VpcPeeringConnectionId: aws.String("pcx-old"),
+		Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String(ec2.VpcPeeringConnectionStateReasonCodeActive)},
+		AccepterVpcInfo: &ec2.VpcPeeringConnectionVpcInfo{
+			VpcId:     aws.String("vpc-old"),
+			CidrBlock: aws.String("192.168.0.0/16"),
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		removed       bool
+		expect        func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectedState string
+	}{
+		{
+			name: "no peering connection, creates one",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcPeeringConnections(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{})).
+					Return(&ec2.DescribeVpcPeeringConnectionsOutput{}, nil).
+					Times(2)
+				m.CreateVpcPeeringConnection(gomock.Eq(&ec2.CreateVpcPeeringConnectionInput{
+					VpcId:     aws.String("vpc-workload"),
+					PeerVpcId: aws.String("vpc-management"),
+				})).
+					Return(&ec2.CreateVpcPeeringConnectionOutput{
+						VpcPeeringConnection: &ec2.VpcPeeringConnection{
+							VpcPeeringConnectionId: aws.String("pcx-1"),
+							Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String(ec2.VpcPeeringConnectionStateReasonCodeInitiatingRequest)},
+							AccepterVpcInfo:        &ec2.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-management")},
+						},
+					}, nil)
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+				m.DescribeVpcs(gomock.Eq(&ec2.DescribeVpcsInput{
+					VpcIds: []*string{aws.String("vpc-management")},
+				})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-management"), CidrBlock: aws.String("172.16.0.0/16")}},
+					}, nil)
+			},
+			expectedState: ec2.VpcPeeringConnectionStateReasonCodeInitiatingRequest,
+		},
+		{
+			name: "peering connection pending acceptance, accepts it and installs peer routes",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcPeeringConnections(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{})).
+					Return(&ec2.DescribeVpcPeeringConnectionsOutput{
+						VpcPeeringConnections: []*ec2.VpcPeeringConnection{
+							{
+								VpcPeeringConnectionId: aws.String("pcx-1"),
+								Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String(ec2.VpcPeeringConnectionStateReasonCodePendingAcceptance)},
+								AccepterVpcInfo:        &ec2.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-management")},
+							},
+						},
+					}, nil).
+					Times(2)
+				m.AcceptVpcPeeringConnection(gomock.Eq(&ec2.AcceptVpcPeeringConnectionInput{
+					VpcPeeringConnectionId: aws.String("pcx-1"),
+				})).
+					Return(&ec2.AcceptVpcPeeringConnectionOutput{
+						VpcPeeringConnection: &ec2.VpcPeeringConnection{
+							VpcPeeringConnectionId: aws.String("pcx-1"),
+							Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String(ec2.VpcPeeringConnectionStateReasonCodeActive)},
+							AccepterVpcInfo: &ec2.VpcPeeringConnectionVpcInfo{
+								VpcId:     aws.String("vpc-management"),
+								CidrBlock: aws.String("172.16.0.0/16"),
+							},
+						},
+					}, nil)
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{
+						RouteTables: []*ec2.RouteTable{
+							{
+								RouteTableId: aws.String("rtb-missing"),
+							},
+							{
+								RouteTableId: aws.String("rtb-current"),
+								Routes: []*ec2.Route{
+									{
+										DestinationCidrBlock:   aws.String("10.0.0.0/16"),
+										VpcPeeringConnectionId: aws.String("pcx-1"),
+									},
+								},
+							},
+							{
+								RouteTableId: aws.String("rtb-stale"),
+								Routes: []*ec2.Route{
+									{
+										DestinationCidrBlock:   aws.String("10.0.0.0/16"),
+										VpcPeeringConnectionId: aws.String("pcx-deleted"),
+										State:                  aws.String(ec2.RouteStateBlackhole),
+									},
+								},
+							},
+						},
+					}, nil)
+				m.CreateRoute(gomock.Eq(&ec2.CreateRouteInput{
+					RouteTableId:           aws.String("rtb-missing"),
+					DestinationCidrBlock:   aws.String("10.0.0.0/16"),
+					VpcPeeringConnectionId: aws.String("pcx-1"),
+				})).
+					Return(&ec2.CreateRouteOutput{}, nil)
+				m.ReplaceRoute(gomock.Eq(&ec2.ReplaceRouteInput{
+					RouteTableId:           aws.String("rtb-stale"),
+					DestinationCidrBlock:   aws.String("10.0.0.0/16"),
+					VpcPeeringConnectionId: aws.String("pcx-1"),
+				})).
+					Return(&ec2.ReplaceRouteOutput{}, nil)
+			},
+			expectedState: ec2.VpcPeeringConnectionStateReasonCodeActive,
+		},
+		{
+			name: "peer vpc replaced, deletes the connection to the previous peer vpc and its routes",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcPeeringConnections(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{})).
+					Return(&ec2.DescribeVpcPeeringConnectionsOutput{
+						VpcPeeringConnections: []*ec2.VpcPeeringConnection{stalePcx, activePcx},
+					}, nil)
+				expectVPCPeeringConnectionDeleted(m, "pcx-old")
+				m.DescribeVpcPeeringConnections(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{})).
+					Return(&ec2.DescribeVpcPeeringConnectionsOutput{
+						VpcPeeringConnections: []*ec2.VpcPeeringConnection{activePcx},
+					}, nil)
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{}, nil)
+			},
+			expectedState: ec2.VpcPeeringConnectionStateReasonCodeActive,
+		},
+		{
+			name:    "peering removed, deletes the connection and its routes",
+			removed: true,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcPeeringConnections(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{})).
+					Return(&ec2.DescribeVpcPeeringConnectionsOutput{
+						VpcPeeringConnections: []*ec2.VpcPeeringConnection{activePcx},
+					}, nil)
+				expectVPCPeeringConnectionDeleted(m, "pcx-1")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSClients: scope.AWSClients{
+					EC2: ec2Mock,
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{
+								ID:        "vpc-workload",
+								CidrBlock: "10.0.0.0/16",
+								Tags: infrav1.Tags{
+									infrav1.ClusterTagKey("test-cluster"): "owned",
+								},
+								Peering: &infrav1.VPCPeeringSpec{
+									PeerVPCID: "vpc-management",
+								},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			if tc.removed {
+				scope.VPC().Peering = nil
+				scope.Network().VPCPeeringConnection = &infrav1.VPCPeeringConnection{ID: "pcx-1"}
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			if err := s.reconcileVPCPeering(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+
+			pcx := scope.Network().VPCPeeringConnection
+			if tc.removed {
+				if pcx != nil {
+					t.Fatalf("expected no VPC peering connection in status, got %+v", pcx)
+				}
+				return
+			}
+			if pcx == nil {
+				t.Fatal("expected VPC peering connection in status, got nil")
+			}
+			if pcx.ID != "pcx-1" || pcx.State != tc.expectedState || pcx.PeerCidrBlock != "172.16.0.0/16" {
+				t.Fatalf("unexpected VPC peering connection status: %+v", pcx)
+			}
+		})
+	}
+}
+
+// expectVPCPeeringConnectionDeleted expects the routes through the peering connection and the connection to be deleted.
+func expectVPCPeeringConnectionDeleted(m *mock_ec2iface.MockEC2APIMockRecorder, id string) {
+	m.DescribeRouteTables(gomock.Eq(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("route.vpc-peering-connection-id"),
+				Values: []*string{aws.String(id)},
+			},
+		},
+	})).
+		Return(&ec2.DescribeRouteTablesOutput{
+			RouteTables: []*ec2.RouteTable{
+				{
+					RouteTableId: aws.String("rtb-private"),
+					Routes: []*ec2.Route{
+						{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-1")},
+						{DestinationCidrBlock: aws.String("192.168.0.0/16"), VpcPeeringConnectionId: aws.String(id)},
+					},
+				},
+				{
+					RouteTableId: aws.String("rtb-peer"),
+					Routes: []*ec2.Route{
+						{DestinationCidrBlock: aws.String("10.0.0.0/16"), VpcPeeringConnectionId: aws.String(id)},
+					},
+				},
+			},
+		}, nil)
+	m.DeleteRoute(gomock.Eq(&ec2.DeleteRouteInput{
+		RouteTableId:         aws.String("rtb-private"),
+		DestinationCidrBlock: aws.String("192.168.0.0/16"),
+	})).
+		Return(&ec2.DeleteRouteOutput{}, nil)
+	m.DeleteRoute(gomock.Eq(&ec2.DeleteRouteInput{
+		RouteTableId:         aws.String("rtb-peer"),
+		DestinationCidrBlock: aws.String("10.0.0.0/16"),
+	})).
+		Return(&ec2.DeleteRouteOutput{}, nil)
+	m.DeleteVpcPeeringConnection(gomock.Eq(&ec2.DeleteVpcPeeringConnectionInput{
+		VpcPeeringConnectionId: aws.String(id),
+	})).
+		Return(&ec2.DeleteVpcPeeringConnectionOutput{}, nil)
+}
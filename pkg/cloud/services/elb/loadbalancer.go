@@ -39,54 +39,98 @@ import (
 // this is the identifier for classic ELBs: https://docs.aws.amazon.com/IAM/latest/UserGuide/list_elasticloadbalancing.html#elasticloadbalancing-resources-for-iam-policies
 const elbResourceType = "elasticloadbalancing:loadbalancer"
 
+// proxyProtocolPolicyName is the name of the policy enabling proxy protocol on the instance ports of a classic ELB.
+const proxyProtocolPolicyName = "k8s-proxyprotocol-enabled"
+
 // ReconcileLoadbalancers reconciles the load balancers for the given cluster.
 func (s *Service) ReconcileLoadbalancers() error {
 	s.scope.V(2).Info("Reconciling load balancers")
 
+	if s.existingAPIServerELB() {
+		return s.reconcileExistingAPIServerELB()
+	}
+
 	// Get default api server spec.
 	spec, err := s.getAPIServerClassicELBSpec()
+	if err != nil {
+		return err
+	}
 
+	apiELB, err := s.reconcileClassicELB(spec)
 	if err != nil {
 		return err
 	}
 
+	// TODO(vincepri): check if anything has changed and reconcile as necessary.
+	apiELB.DeepCopyInto(&s.scope.Network().APIServerELB)
+	s.scope.V(4).Info("Control plane load balancer", "api-server-elb", apiELB)
+
+	if s.internalAPIServerELBEnabled() {
+		internalSpec, err := s.getAPIServerInternalClassicELBSpec()
+		if err != nil {
+			return err
+		}
+
+		internalELB, err := s.reconcileClassicELB(internalSpec)
+		if err != nil {
+			return err
+		}
+
+		s.scope.Network().APIServerInternalELB = internalELB
+		s.scope.V(4).Info("Control plane internal load balancer", "api-server-internal-elb", internalELB)
+	}
+
+	s.scope.V(2).Info("Reconcile load balancers completed successfully")
+	return nil
+}
+
+// reconcileClassicELB creates the classic load balancer described by the spec, or brings
+// an existing one in line with it.
+func (s *Service) reconcileClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicELB, error) {
 	// Describe or create.
 	apiELB, err := s.describeClassicELB(spec.Name)
 	if IsNotFound(err) {
 		apiELB, err = s.createClassicELB(spec)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		s.scope.V(2).Info("Created new classic load balancer for apiserver", "api-server-elb-name", apiELB.Name)
+		s.scope.V(2).Info("Created new classic load balancer for apiserver", "elb-name", apiELB.Name)
 	} else if err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := s.reconcileListeners(apiELB, spec); err != nil {
+		return nil, err
 	}
 
 	if !reflect.DeepEqual(spec.Attributes, apiELB.Attributes) {
 		err := s.configureAttributes(apiELB.Name, spec.Attributes)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	if err := s.reconcileELBTags(apiELB.Name, spec.Tags); err != nil {
-		return errors.Wrapf(err, "failed to reconcile tags for apiserver load balancer %q", apiELB.Name)
+	if spec.HealthCheck != nil && !reflect.DeepEqual(spec.HealthCheck, apiELB.HealthCheck) {
+		if err := s.configureHealthCheck(apiELB.Name, spec.HealthCheck); err != nil {
+			return nil, err
+		}
+		apiELB.HealthCheck = spec.HealthCheck
 	}
 
-	// Reconcile the subnets and availability zones from the spec
-	// and the ones currently attached to the load balancer.
-	if len(apiELB.SubnetIDs) != len(spec.SubnetIDs) {
-		_, err := s.scope.ELB.AttachLoadBalancerToSubnets(&elb.AttachLoadBalancerToSubnetsInput{
-			LoadBalancerName: &apiELB.Name,
-			Subnets:          aws.StringSlice(spec.SubnetIDs),
-		})
-		if err != nil {
-			return errors.Wrapf(err, "failed to attach apiserver load balancer %q to subnets", apiELB.Name)
+	if spec.ProxyProtocol != apiELB.ProxyProtocol {
+		if err := s.configureProxyProtocol(apiELB.Name, spec.Listeners, spec.ProxyProtocol); err != nil {
+			return nil, err
 		}
+		apiELB.ProxyProtocol = spec.ProxyProtocol
 	}
-	if len(apiELB.AvailabilityZones) != len(spec.AvailabilityZones) {
-		apiELB.AvailabilityZones = spec.AvailabilityZones
+
+	if err := s.reconcileELBTags(apiELB.Name, spec.Tags); err != nil {
+		return nil, errors.Wrapf(err, "failed to reconcile tags for apiserver load balancer %q", apiELB.Name)
+	}
+
+	if err := s.reconcileSubnets(apiELB, spec); err != nil {
+		return nil, err
 	}
 
 	// Reconcile the security groups from the spec and the ones currently attached to the load balancer
@@ -96,13 +140,150 @@ func (s *Service) ReconcileLoadbalancers() error {
 			SecurityGroups:   aws.StringSlice(spec.SecurityGroupIDs),
 		})
 		if err != nil {
-			return errors.Wrapf(err, "failed to apply security groups to load balancer %q", apiELB.Name)
+			return nil, errors.Wrapf(err, "failed to apply security groups to load balancer %q", apiELB.Name)
 		}
 	}
 
-	// TODO(vincepri): check if anything has changed and reconcile as necessary.
+	return apiELB, nil
+}
+
+// reconcileListeners replaces the listeners of the load balancer that differ from the spec, e.g. after the
+// API server port changed. A classic ELB has a single listener per load balancer port, so outdated listeners
+// are deleted before the new ones are created.
+func (s *Service) reconcileListeners(apiELB *infrav1.ClassicELB, spec *infrav1.ClassicELB) error {
+	var toDelete []*int64
+	for _, current := range apiELB.Listeners {
+		if !containsListener(spec.Listeners, current) {
+			toDelete = append(toDelete, aws.Int64(current.Port))
+		}
+	}
+
+	var toCreate []*elb.Listener
+	for _, ln := range spec.Listeners {
+		if !containsListener(apiELB.Listeners, ln) {
+			toCreate = append(toCreate, &elb.Listener{
+				Protocol:         aws.String(string(ln.Protocol)),
+				LoadBalancerPort: aws.Int64(ln.Port),
+				InstanceProtocol: aws.String(string(ln.InstanceProtocol)),
+				InstancePort:     aws.Int64(ln.InstancePort),
+			})
+		}
+	}
+
+	if len(toDelete) == 0 && len(toCreate) == 0 {
+		return nil
+	}
+
+	if len(toDelete) > 0 {
+		if _, err := s.scope.ELB.DeleteLoadBalancerListeners(&elb.DeleteLoadBalancerListenersInput{
+			LoadBalancerName:  aws.String(apiELB.Name),
+			LoadBalancerPorts: toDelete,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to delete listeners of classic load balancer %q", apiELB.Name)
+		}
+	}
+
+	if len(toCreate) > 0 {
+		if _, err := s.scope.ELB.CreateLoadBalancerListeners(&elb.CreateLoadBalancerListenersInput{
+			LoadBalancerName: aws.String(apiELB.Name),
+			Listeners:        toCreate,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to create listeners of classic load balancer %q", apiELB.Name)
+		}
+
+		// Proxy protocol is configured per instance port, new instance ports need the policy as well.
+		if apiELB.ProxyProtocol {
+			if err := s.configureProxyProtocol(apiELB.Name, spec.Listeners, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.scope.V(2).Info("Replaced classic load balancer listeners", "elb-name", apiELB.Name)
+	apiELB.Listeners = spec.Listeners
+	return nil
+}
+
+// reconcileSubnets attaches and detaches subnets so the load balancer ends up in the subnets from the spec.
+// Classic ELBs only support a single subnet per availability zone and must keep at least one subnet, so
+// subnets in availability zones the load balancer doesn't cover yet are attached first, then the subnets
+// which are no longer wanted are detached, and finally the subnets replacing them are attached.
+func (s *Service) reconcileSubnets(apiELB *infrav1.ClassicELB, spec *infrav1.ClassicELB) error {
+	currentSubnets := sets.NewString(apiELB.SubnetIDs...)
+	desiredSubnets := sets.NewString(spec.SubnetIDs...)
+	currentAZs := sets.NewString(apiELB.AvailabilityZones...)
+
+	var attachFirst, attachLast []string
+	for i, id := range spec.SubnetIDs {
+		if currentSubnets.Has(id) {
+			continue
+		}
+		if i < len(spec.AvailabilityZones) && !currentAZs.Has(spec.AvailabilityZones[i]) {
+			attachFirst = append(attachFirst, id)
+		} else {
+			attachLast = append(attachLast, id)
+		}
+	}
+
+	if err := s.attachSubnets(apiELB.Name, attachFirst); err != nil {
+		return err
+	}
+
+	if detach := currentSubnets.Difference(desiredSubnets); detach.Len() > 0 {
+		_, err := s.scope.ELB.DetachLoadBalancerFromSubnets(&elb.DetachLoadBalancerFromSubnetsInput{
+			LoadBalancerName: aws.String(apiELB.Name),
+			Subnets:          aws.StringSlice(detach.List()),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to detach apiserver load balancer %q from subnets", apiELB.Name)
+		}
+	}
+
+	if err := s.attachSubnets(apiELB.Name, attachLast); err != nil {
+		return err
+	}
+
+	apiELB.SubnetIDs = spec.SubnetIDs
+	apiELB.AvailabilityZones = spec.AvailabilityZones
+	return nil
+}
+
+func (s *Service) attachSubnets(name string, subnets []string) error {
+	if len(subnets) == 0 {
+		return nil
+	}
+
+	_, err := s.scope.ELB.AttachLoadBalancerToSubnets(&elb.AttachLoadBalancerToSubnetsInput{
+		LoadBalancerName: aws.String(name),
+		Subnets:          aws.StringSlice(subnets),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to attach apiserver load balancer %q to subnets", name)
+	}
+	return nil
+}
+
+func containsListener(listeners []*infrav1.ClassicELBListener, ln *infrav1.ClassicELBListener) bool {
+	for _, x := range listeners {
+		if *x == *ln {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileExistingAPIServerELB records the state of the pre-created API server load balancer,
+// which is used as is and never modified.
+func (s *Service) reconcileExistingAPIServerELB() error {
+	name := *s.scope.ControlPlaneLoadBalancer().ExistingName
+
+	apiELB, err := s.describeClassicELB(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find existing apiserver load balancer %q", name)
+	}
+
 	apiELB.DeepCopyInto(&s.scope.Network().APIServerELB)
-	s.scope.V(4).Info("Control plane load balancer", "api-server-elb", apiELB)
+	s.scope.V(4).Info("Using existing control plane load balancer", "api-server-elb", apiELB)
 
 	s.scope.V(2).Info("Reconcile load balancers completed successfully")
 	return nil
@@ -110,7 +291,7 @@ func (s *Service) ReconcileLoadbalancers() error {
 
 // GetAPIServerDNSName returns the DNS name endpoint for the API server
 func (s *Service) GetAPIServerDNSName() (string, error) {
-	elbName, err := GenerateELBName(s.scope.Name())
+	elbName, err := s.apiServerELBName()
 	if err != nil {
 		return "", err
 	}
@@ -131,6 +312,10 @@ func (s *Service) DeleteLoadbalancers() error {
 	}
 
 	for _, elb := range elbs {
+		if s.existingAPIServerELB() && elb == *s.scope.ControlPlaneLoadBalancer().ExistingName {
+			continue
+		}
+
 		s.scope.V(3).Info("deleting load balancer", "arn", elb)
 		if err := s.deleteClassicELB(elb); err != nil {
 			return err
@@ -169,10 +354,35 @@ func (s *Service) RegisterInstanceWithClassicELB(instanceID string, loadBalancer
 
 // RegisterInstanceWithAPIServerELB registers an instance with a classic ELB
 func (s *Service) RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error {
-	name, err := GenerateELBName(s.scope.Name())
+	name, err := s.apiServerELBName()
 	if err != nil {
 		return err
 	}
+
+	if s.existingAPIServerELB() {
+		// The subnets of an existing load balancer aren't necessarily known to the cluster,
+		// placing it in the right availability zones is up to whoever created it.
+		return s.RegisterInstanceWithClassicELB(i.ID, name)
+	}
+
+	if err := s.registerInstanceWithClassicELBInZone(i, name); err != nil {
+		return err
+	}
+
+	if s.internalAPIServerELBEnabled() {
+		internalName, err := GenerateInternalELBName(s.scope.Name())
+		if err != nil {
+			return err
+		}
+		return s.registerInstanceWithClassicELBInZone(i, internalName)
+	}
+
+	return nil
+}
+
+// registerInstanceWithClassicELBInZone registers an instance with a classic ELB after validating
+// that the load balancer has a subnet in the availability zone of the instance.
+func (s *Service) registerInstanceWithClassicELBInZone(i *infrav1.Instance, name string) error {
 	out, err := s.describeClassicELB(name)
 	if err != nil {
 		return err
@@ -192,7 +402,7 @@ func (s *Service) RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error {
 		}
 	}
 	if !found {
-		return errors.Errorf("failed to register instance with APIServer ELB %q: instance is in availability zone %q, no public subnets attached to the ELB in the same zone", name, instanceAZ)
+		return errors.Errorf("failed to register instance with APIServer ELB %q: instance is in availability zone %q, no subnets attached to the ELB in the same zone", name, instanceAZ)
 	}
 
 	input := &elb.RegisterInstancesWithLoadBalancerInput{
@@ -208,16 +418,73 @@ func (s *Service) RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error {
 	return nil
 }
 
+// DeregisterInstanceFromAPIServerELB deregisters an instance from an existing API server ELB.
+// Load balancers created by the provider are deleted with the cluster, so this is a no-op for them.
+func (s *Service) DeregisterInstanceFromAPIServerELB(i *infrav1.Instance) error {
+	if !s.existingAPIServerELB() {
+		return nil
+	}
+
+	name := *s.scope.ControlPlaneLoadBalancer().ExistingName
+	if _, err := s.scope.ELB.DeregisterInstancesFromLoadBalancer(&elb.DeregisterInstancesFromLoadBalancerInput{
+		Instances:        []*elb.Instance{{InstanceId: aws.String(i.ID)}},
+		LoadBalancerName: aws.String(name),
+	}); err != nil {
+		if code, ok := awserrors.Code(err); ok && (code == elb.ErrCodeAccessPointNotFoundException || code == elb.ErrCodeInvalidEndPointException) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to deregister instance %q from load balancer %q", i.ID, name)
+	}
+
+	return nil
+}
+
+// existingAPIServerELB returns true if the API server load balancer is pre-created and not managed by the provider.
+func (s *Service) existingAPIServerELB() bool {
+	lb := s.scope.ControlPlaneLoadBalancer()
+	return lb != nil && lb.ExistingName != nil && *lb.ExistingName != ""
+}
+
+// internalAPIServerELBEnabled returns true if an internal API server load balancer is provisioned
+// alongside the internet-facing one.
+func (s *Service) internalAPIServerELBEnabled() bool {
+	lb := s.scope.ControlPlaneLoadBalancer()
+	return lb != nil && lb.InternalLoadBalancer && !s.existingAPIServerELB() &&
+		s.scope.ControlPlaneLoadBalancerScheme() == infrav1.ClassicELBSchemeInternetFacing
+}
+
+// apiServerELBName returns the name of the API server load balancer.
+func (s *Service) apiServerELBName() (string, error) {
+	if s.existingAPIServerELB() {
+		return *s.scope.ControlPlaneLoadBalancer().ExistingName, nil
+	}
+	if lb := s.scope.ControlPlaneLoadBalancer(); lb != nil && lb.Name != nil {
+		return *lb.Name, nil
+	}
+	return GenerateELBName(s.scope.Name())
+}
+
 // GenerateELBName generates a formatted ELB name via either
 // concatenating the cluster name to the "-apiserver" suffix
 // or computing a hash for clusters with names above 32 characters.
 func GenerateELBName(clusterName string) (string, error) {
-	standardELBName := generateStandardELBName(clusterName)
+	return generateELBName(clusterName, infrav1.APIServerRoleTagValue, "k8s")
+}
+
+// GenerateInternalELBName generates a formatted name for the internal API server ELB via either
+// concatenating the cluster name to the "-apiserver-int" suffix
+// or computing a hash for clusters with names above 18 characters.
+func GenerateInternalELBName(clusterName string) (string, error) {
+	return generateELBName(clusterName, infrav1.APIServerRoleTagValue+"-int", "k8s-int")
+}
+
+func generateELBName(clusterName, suffix, hashedSuffix string) (string, error) {
+	standardELBName := generateStandardELBName(clusterName, suffix)
 	if len(standardELBName) <= 32 {
 		return standardELBName, nil
 	}
 
-	elbName, err := generateHashedELBName(clusterName)
+	elbName, err := generateHashedELBName(clusterName, hashedSuffix)
 	if err != nil {
 		return "", err
 	}
@@ -227,25 +494,25 @@ func GenerateELBName(clusterName string) (string, error) {
 
 // generateStandardELBName generates a formatted ELB name based on cluster
 // and ELB name
-func generateStandardELBName(clusterName string) string {
+func generateStandardELBName(clusterName, suffix string) string {
 	elbCompatibleClusterName := strings.Replace(clusterName, ".", "-", -1)
-	return fmt.Sprintf("%s-%s", elbCompatibleClusterName, infrav1.APIServerRoleTagValue)
+	return fmt.Sprintf("%s-%s", elbCompatibleClusterName, suffix)
 }
 
 // generateHashedELBName generates a 32-character hashed name based on cluster
 // and ELB name
-func generateHashedELBName(clusterName string) (string, error) {
-	// hashSize = 32 - length of "k8s" - length of "-" = 28
-	shortName, err := hash.Base36TruncatedHash(clusterName, 28)
+func generateHashedELBName(clusterName, suffix string) (string, error) {
+	// hashSize = 32 - length of suffix - length of "-"
+	shortName, err := hash.Base36TruncatedHash(clusterName, 32-len(suffix)-1)
 	if err != nil {
 		return "", errors.Wrap(err, "unable to create ELB name")
 	}
 
-	return fmt.Sprintf("%s-%s", shortName, "k8s"), nil
+	return fmt.Sprintf("%s-%s", shortName, suffix), nil
 }
 
 func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
-	elbName, err := GenerateELBName(s.scope.Name())
+	elbName, err := s.apiServerELBName()
 	if err != nil {
 		return nil, err
 	}
@@ -257,11 +524,11 @@ func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
 				Protocol:         infrav1.ClassicELBProtocolTCP,
 				Port:             int64(s.scope.APIServerPort()),
 				InstanceProtocol: infrav1.ClassicELBProtocolTCP,
-				InstancePort:     6443,
+				InstancePort:     int64(s.scope.APIServerInstancePort()),
 			},
 		},
 		HealthCheck: &infrav1.ClassicELBHealthCheck{
-			Target:             fmt.Sprintf("%v:%d", infrav1.ClassicELBProtocolSSL, 6443),
+			Target:             fmt.Sprintf("%v:%d", infrav1.ClassicELBProtocolSSL, s.scope.APIServerInstancePort()),
 			Interval:           10 * time.Second,
 			Timeout:            5 * time.Second,
 			HealthyThreshold:   5,
@@ -275,6 +542,22 @@ func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
 
 	if s.scope.AWSCluster.Spec.ControlPlaneLoadBalancer != nil {
 		res.Attributes.CrossZoneLoadBalancing = s.scope.AWSCluster.Spec.ControlPlaneLoadBalancer.CrossZoneLoadBalancing
+		res.ProxyProtocol = s.scope.AWSCluster.Spec.ControlPlaneLoadBalancer.ProxyProtocol
+		applyHealthCheckSpec(res.HealthCheck, s.scope.AWSCluster.Spec.ControlPlaneLoadBalancer.HealthCheck, s.scope.APIServerInstancePort())
+		res.SecurityGroupIDs = append(res.SecurityGroupIDs, s.scope.AWSCluster.Spec.ControlPlaneLoadBalancer.AdditionalSecurityGroups...)
+		if s.scope.AWSCluster.Spec.ControlPlaneLoadBalancer.IdleTimeout != nil {
+			res.Attributes.IdleTimeout = s.scope.AWSCluster.Spec.ControlPlaneLoadBalancer.IdleTimeout.Duration
+		}
+		if accessLogs := s.scope.AWSCluster.Spec.ControlPlaneLoadBalancer.AccessLogs; accessLogs != nil {
+			res.Attributes.AccessLog = &infrav1.ClassicELBAccessLog{
+				S3BucketName:   accessLogs.BucketName,
+				S3BucketPrefix: accessLogs.BucketPrefix,
+				EmitInterval:   60,
+			}
+			if accessLogs.EmitInterval != nil {
+				res.Attributes.AccessLog.EmitInterval = *accessLogs.EmitInterval
+			}
+		}
 	}
 
 	res.Tags = infrav1.Build(infrav1.BuildParams{
@@ -284,13 +567,67 @@ func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
 		Additional:  s.scope.AdditionalTags(),
 	})
 
-	// The load balancer APIs require us to only attach one subnet for each AZ.
+	if lb := s.scope.ControlPlaneLoadBalancer(); lb != nil && len(lb.Subnets) > 0 {
+		if err := setClassicELBSubnetsByID(res, s.scope.Subnets(), lb.Subnets); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
 	subnets := s.scope.Subnets().FilterPrivate()
 
 	if s.scope.ControlPlaneLoadBalancerScheme() == infrav1.ClassicELBSchemeInternetFacing {
 		subnets = s.scope.Subnets().FilterPublic()
 	}
 
+	setClassicELBSubnets(res, subnets)
+
+	return res, nil
+}
+
+// getAPIServerInternalClassicELBSpec returns the spec of the internal API server load balancer,
+// which matches the internet-facing one except for its name, scheme and subnets.
+func (s *Service) getAPIServerInternalClassicELBSpec() (*infrav1.ClassicELB, error) {
+	res, err := s.getAPIServerClassicELBSpec()
+	if err != nil {
+		return nil, err
+	}
+
+	res.Name, err = GenerateInternalELBName(s.scope.Name())
+	if err != nil {
+		return nil, err
+	}
+	res.Scheme = infrav1.ClassicELBSchemeInternal
+	res.AvailabilityZones = nil
+	res.SubnetIDs = nil
+
+	setClassicELBSubnets(res, s.scope.Subnets().FilterPrivate())
+
+	return res, nil
+}
+
+// setClassicELBSubnetsByID places the load balancer in the given subnets, which must be known to the cluster
+// and be in distinct availability zones.
+func setClassicELBSubnetsByID(res *infrav1.ClassicELB, subnets infrav1.Subnets, ids []string) error {
+	for _, id := range ids {
+		sn := subnets.FindByID(id)
+		if sn == nil {
+			return errors.Errorf("failed to find subnet %q for load balancer %q in the cluster's subnets", id, res.Name)
+		}
+		for _, az := range res.AvailabilityZones {
+			if sn.AvailabilityZone == az {
+				return errors.Errorf("failed to place load balancer %q in subnet %q, another subnet in availability zone %q is already used", res.Name, id, az)
+			}
+		}
+		res.AvailabilityZones = append(res.AvailabilityZones, sn.AvailabilityZone)
+		res.SubnetIDs = append(res.SubnetIDs, sn.ID)
+	}
+
+	return nil
+}
+
+func setClassicELBSubnets(res *infrav1.ClassicELB, subnets infrav1.Subnets) {
+	// The load balancer APIs require us to only attach one subnet for each AZ.
 	for _, sn := range subnets {
 		for _, az := range res.AvailabilityZones {
 			if sn.AvailabilityZone == az {
@@ -302,8 +639,6 @@ func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
 		res.AvailabilityZones = append(res.AvailabilityZones, sn.AvailabilityZone)
 		res.SubnetIDs = append(res.SubnetIDs, sn.ID)
 	}
-
-	return res, nil
 }
 
 func (s *Service) createClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicELB, error) {
@@ -330,22 +665,14 @@ func (s *Service) createClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicEL
 	}
 
 	if spec.HealthCheck != nil {
-		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-			if _, err := s.scope.ELB.ConfigureHealthCheck(&elb.ConfigureHealthCheckInput{
-				LoadBalancerName: aws.String(spec.Name),
-				HealthCheck: &elb.HealthCheck{
-					Target:             aws.String(spec.HealthCheck.Target),
-					Interval:           aws.Int64(int64(spec.HealthCheck.Interval.Seconds())),
-					Timeout:            aws.Int64(int64(spec.HealthCheck.Timeout.Seconds())),
-					HealthyThreshold:   aws.Int64(spec.HealthCheck.HealthyThreshold),
-					UnhealthyThreshold: aws.Int64(spec.HealthCheck.UnhealthyThreshold),
-				},
-			}); err != nil {
-				return false, err
-			}
-			return true, nil
-		}, awserrors.LoadBalancerNotFound); err != nil {
-			return nil, errors.Wrapf(err, "failed to configure health check for classic load balancer: %v", spec)
+		if err := s.configureHealthCheck(spec.Name, spec.HealthCheck); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec.ProxyProtocol {
+		if err := s.configureProxyProtocol(spec.Name, spec.Listeners, true); err != nil {
+			return nil, err
 		}
 	}
 
@@ -356,6 +683,88 @@ func (s *Service) createClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicEL
 	return res, nil
 }
 
+func (s *Service) configureHealthCheck(name string, healthCheck *infrav1.ClassicELBHealthCheck) error {
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.scope.ELB.ConfigureHealthCheck(&elb.ConfigureHealthCheckInput{
+			LoadBalancerName: aws.String(name),
+			HealthCheck: &elb.HealthCheck{
+				Target:             aws.String(healthCheck.Target),
+				Interval:           aws.Int64(int64(healthCheck.Interval.Seconds())),
+				Timeout:            aws.Int64(int64(healthCheck.Timeout.Seconds())),
+				HealthyThreshold:   aws.Int64(healthCheck.HealthyThreshold),
+				UnhealthyThreshold: aws.Int64(healthCheck.UnhealthyThreshold),
+			},
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.LoadBalancerNotFound); err != nil {
+		return errors.Wrapf(err, "failed to configure health check for classic load balancer: %v", name)
+	}
+
+	return nil
+}
+
+// configureProxyProtocol enables or disables proxy protocol on the instance ports of the listeners.
+func (s *Service) configureProxyProtocol(name string, listeners []*infrav1.ClassicELBListener, enabled bool) error {
+	var policyNames []string
+	if enabled {
+		_, err := s.scope.ELB.CreateLoadBalancerPolicy(&elb.CreateLoadBalancerPolicyInput{
+			LoadBalancerName: aws.String(name),
+			PolicyName:       aws.String(proxyProtocolPolicyName),
+			PolicyTypeName:   aws.String("ProxyProtocolPolicyType"),
+			PolicyAttributes: []*elb.PolicyAttribute{
+				{
+					AttributeName:  aws.String("ProxyProtocol"),
+					AttributeValue: aws.String("true"),
+				},
+			},
+		})
+		if err != nil {
+			// The policy is left in place when proxy protocol is disabled, so it may exist already.
+			if code, ok := awserrors.Code(err); !ok || code != elb.ErrCodeDuplicatePolicyNameException {
+				return errors.Wrapf(err, "failed to create proxy protocol policy for classic load balancer: %v", name)
+			}
+		}
+		policyNames = []string{proxyProtocolPolicyName}
+	}
+
+	for _, ln := range listeners {
+		if _, err := s.scope.ELB.SetLoadBalancerPoliciesForBackendServer(&elb.SetLoadBalancerPoliciesForBackendServerInput{
+			LoadBalancerName: aws.String(name),
+			InstancePort:     aws.Int64(ln.InstancePort),
+			PolicyNames:      aws.StringSlice(policyNames),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to configure proxy protocol on port %d for classic load balancer: %v", ln.InstancePort, name)
+		}
+	}
+
+	return nil
+}
+
+// applyHealthCheckSpec overrides the default health check with the values set in the spec.
+func applyHealthCheckSpec(healthCheck *infrav1.ClassicELBHealthCheck, spec *infrav1.AWSLoadBalancerHealthCheck, port int32) {
+	if spec == nil {
+		return
+	}
+
+	if spec.Protocol != nil {
+		healthCheck.Target = fmt.Sprintf("%v:%d", *spec.Protocol, port)
+	}
+	if spec.Interval != nil {
+		healthCheck.Interval = spec.Interval.Duration
+	}
+	if spec.Timeout != nil {
+		healthCheck.Timeout = spec.Timeout.Duration
+	}
+	if spec.HealthyThreshold != nil {
+		healthCheck.HealthyThreshold = *spec.HealthyThreshold
+	}
+	if spec.UnhealthyThreshold != nil {
+		healthCheck.UnhealthyThreshold = *spec.UnhealthyThreshold
+	}
+}
+
 func (s *Service) configureAttributes(name string, attributes infrav1.ClassicELBAttributes) error {
 	attrs := &elb.ModifyLoadBalancerAttributesInput{
 		LoadBalancerName: aws.String(name),
@@ -372,6 +781,19 @@ func (s *Service) configureAttributes(name string, attributes infrav1.ClassicELB
 		}
 	}
 
+	// Access logging is explicitly disabled when not set, so that removing it from the spec takes effect.
+	attrs.LoadBalancerAttributes.AccessLog = &elb.AccessLog{
+		Enabled: aws.Bool(false),
+	}
+	if attributes.AccessLog != nil {
+		attrs.LoadBalancerAttributes.AccessLog = &elb.AccessLog{
+			Enabled:        aws.Bool(true),
+			S3BucketName:   aws.String(attributes.AccessLog.S3BucketName),
+			S3BucketPrefix: aws.String(attributes.AccessLog.S3BucketPrefix),
+			EmitInterval:   aws.Int64(attributes.AccessLog.EmitInterval),
+		}
+	}
+
 	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
 		if _, err := s.scope.ELB.ModifyLoadBalancerAttributes(attrs); err != nil {
 			return false, err
@@ -521,6 +943,10 @@ func (s *Service) reconcileELBTags(name string, desiredTags map[string]string) e
 	}
 
 	for k := range currentTags {
+		// Tags with the aws: prefix are reserved and cannot be removed.
+		if strings.HasPrefix(k, "aws:") {
+			continue
+		}
 		if _, ok := desiredTags[k]; !ok {
 			s.scope.V(4).Info("removing tag from load balancer", "elb-name", name, "key", k)
 			removeTagsInput.Tags = append(removeTagsInput.Tags, &elb.TagKeyOnly{Key: aws.String(k)})
@@ -544,11 +970,42 @@ func (s *Service) reconcileELBTags(name string, desiredTags map[string]string) e
 
 func fromSDKTypeToClassicELB(v *elb.LoadBalancerDescription, attrs *elb.LoadBalancerAttributes) *infrav1.ClassicELB {
 	res := &infrav1.ClassicELB{
-		Name:             aws.StringValue(v.LoadBalancerName),
-		Scheme:           infrav1.ClassicELBScheme(*v.Scheme),
-		SubnetIDs:        aws.StringValueSlice(v.Subnets),
-		SecurityGroupIDs: aws.StringValueSlice(v.SecurityGroups),
-		DNSName:          aws.StringValue(v.DNSName),
+		Name:                  aws.StringValue(v.LoadBalancerName),
+		Scheme:                infrav1.ClassicELBScheme(*v.Scheme),
+		SubnetIDs:             aws.StringValueSlice(v.Subnets),
+		SecurityGroupIDs:      aws.StringValueSlice(v.SecurityGroups),
+		DNSName:               aws.StringValue(v.DNSName),
+		CanonicalHostedZoneID: aws.StringValue(v.CanonicalHostedZoneNameID),
+	}
+
+	for _, ld := range v.ListenerDescriptions {
+		if ld.Listener == nil {
+			continue
+		}
+		res.Listeners = append(res.Listeners, &infrav1.ClassicELBListener{
+			Protocol:         infrav1.ClassicELBProtocol(aws.StringValue(ld.Listener.Protocol)),
+			Port:             aws.Int64Value(ld.Listener.LoadBalancerPort),
+			InstanceProtocol: infrav1.ClassicELBProtocol(aws.StringValue(ld.Listener.InstanceProtocol)),
+			InstancePort:     aws.Int64Value(ld.Listener.InstancePort),
+		})
+	}
+
+	for _, backend := range v.BackendServerDescriptions {
+		for _, policyName := range backend.PolicyNames {
+			if aws.StringValue(policyName) == proxyProtocolPolicyName {
+				res.ProxyProtocol = true
+			}
+		}
+	}
+
+	if v.HealthCheck != nil {
+		res.HealthCheck = &infrav1.ClassicELBHealthCheck{
+			Target:             aws.StringValue(v.HealthCheck.Target),
+			Interval:           time.Duration(aws.Int64Value(v.HealthCheck.Interval)) * time.Second,
+			Timeout:            time.Duration(aws.Int64Value(v.HealthCheck.Timeout)) * time.Second,
+			HealthyThreshold:   aws.Int64Value(v.HealthCheck.HealthyThreshold),
+			UnhealthyThreshold: aws.Int64Value(v.HealthCheck.UnhealthyThreshold),
+		}
 	}
 
 	if attrs.ConnectionSettings != nil && attrs.ConnectionSettings.IdleTimeout != nil {
@@ -557,5 +1014,13 @@ func fromSDKTypeToClassicELB(v *elb.LoadBalancerDescription, attrs *elb.LoadBala
 
 	res.Attributes.CrossZoneLoadBalancing = aws.BoolValue(attrs.CrossZoneLoadBalancing.Enabled)
 
+	if attrs.AccessLog != nil && aws.BoolValue(attrs.AccessLog.Enabled) {
+		res.Attributes.AccessLog = &infrav1.ClassicELBAccessLog{
+			S3BucketName:   aws.StringValue(attrs.AccessLog.S3BucketName),
+			S3BucketPrefix: aws.StringValue(attrs.AccessLog.S3BucketPrefix),
+			EmitInterval:   aws.Int64Value(attrs.AccessLog.EmitInterval),
+		}
+	}
+
 	return res
 }
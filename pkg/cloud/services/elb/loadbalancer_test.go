@@ -17,11 +17,19 @@ limitations under the License.
 package elb
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/golang/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbiface"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
 
@@ -72,16 +80,106 @@ func TestGenerateELBName(t *testing.T) {
 	}
 }
 
+func TestGenerateInternalELBName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{
+			name:     "test",
+			expected: "test-apiserver-int",
+		},
+		{
+			name:     "0123456789012345678",
+			expected: "ie2f55hl13t5jxe1z8blw8y2-k8s-int",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elbName, err := GenerateInternalELBName(tt.name)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if elbName != tt.expected {
+				t.Errorf("expected ELB name: %v, got name: %v", tt.expected, elbName)
+			}
+
+			if len(elbName) > 32 {
+				t.Errorf("ELB name too long: %v vs. %s", len(elbName), "32")
+			}
+		})
+	}
+}
+
+func TestGetAPIServerInternalClassicELBSpec(t *testing.T) {
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo",
+				Name:      "bar",
+			},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				ControlPlaneLoadBalancer: &infrav1.AWSLoadBalancerSpec{
+					InternalLoadBalancer: true,
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Subnets: infrav1.Subnets{
+						{ID: "subnet-public", AvailabilityZone: "us-east-1a", IsPublic: true},
+						{ID: "subnet-private", AvailabilityZone: "us-east-1a"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Service{
+		scope: clusterScope,
+	}
+
+	if !s.internalAPIServerELBEnabled() {
+		t.Fatal("expected the internal load balancer to be enabled")
+	}
+
+	spec, err := s.getAPIServerInternalClassicELBSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if spec.Name != "bar-apiserver-int" {
+		t.Errorf("expected name %q, got %q", "bar-apiserver-int", spec.Name)
+	}
+	if spec.Scheme != infrav1.ClassicELBSchemeInternal {
+		t.Errorf("expected scheme %q, got %q", infrav1.ClassicELBSchemeInternal, spec.Scheme)
+	}
+	if !reflect.DeepEqual(spec.SubnetIDs, []string{"subnet-private"}) {
+		t.Errorf("expected subnets %v, got %v", []string{"subnet-private"}, spec.SubnetIDs)
+	}
+}
+
 func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 	tests := []struct {
-		name            string
-		lb              *infrav1.AWSLoadBalancerSpec
-		expectCrossZone bool
+		name              string
+		lb                *infrav1.AWSLoadBalancerSpec
+		expectCrossZone   bool
+		expectHealthCheck *infrav1.ClassicELBHealthCheck
 	}{
 		{
 			name:            "nil load balancer config",
 			lb:              nil,
 			expectCrossZone: false,
+			expectHealthCheck: &infrav1.ClassicELBHealthCheck{
+				Target:             "SSL:6443",
+				Interval:           10 * time.Second,
+				Timeout:            5 * time.Second,
+				HealthyThreshold:   5,
+				UnhealthyThreshold: 3,
+			},
 		},
 		{
 			name: "load balancer config with cross zone enabled",
@@ -89,6 +187,32 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 				CrossZoneLoadBalancing: true,
 			},
 			expectCrossZone: true,
+			expectHealthCheck: &infrav1.ClassicELBHealthCheck{
+				Target:             "SSL:6443",
+				Interval:           10 * time.Second,
+				Timeout:            5 * time.Second,
+				HealthyThreshold:   5,
+				UnhealthyThreshold: 3,
+			},
+		},
+		{
+			name: "load balancer config with custom health check",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				HealthCheck: &infrav1.AWSLoadBalancerHealthCheck{
+					Protocol:         &infrav1.ClassicELBProtocolTCP,
+					Interval:         &metav1.Duration{Duration: 5 * time.Second},
+					Timeout:          &metav1.Duration{Duration: 2 * time.Second},
+					HealthyThreshold: aws.Int64(2),
+				},
+			},
+			expectCrossZone: false,
+			expectHealthCheck: &infrav1.ClassicELBHealthCheck{
+				Target:             "TCP:6443",
+				Interval:           5 * time.Second,
+				Timeout:            2 * time.Second,
+				HealthyThreshold:   2,
+				UnhealthyThreshold: 3,
+			},
 		},
 	}
 
@@ -123,7 +247,795 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 			if e, a := tc.expectCrossZone, spec.Attributes.CrossZoneLoadBalancing; e != a {
 				t.Errorf("cross zone: expected %t, got %t", e, a)
 			}
+
+			if e, a := tc.expectHealthCheck, spec.HealthCheck; !reflect.DeepEqual(e, a) {
+				t.Errorf("health check: expected %+v, got %+v", e, a)
+			}
+		})
+	}
+
+}
+
+func TestReconcileLoadbalancers_ExistingELB(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	elbMock := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo",
+				Name:      "bar",
+			},
+		},
+		AWSClients: scope.AWSClients{
+			ELB: elbMock,
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				ControlPlaneLoadBalancer: &infrav1.AWSLoadBalancerSpec{
+					ExistingName: aws.String("corp-apiserver"),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only describe calls are expected, the existing load balancer must not be modified.
+	elbMock.EXPECT().DescribeLoadBalancers(gomock.Eq(&elb.DescribeLoadBalancersInput{
+		LoadBalancerNames: aws.StringSlice([]string{"corp-apiserver"}),
+	})).
+		Return(&elb.DescribeLoadBalancersOutput{
+			LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+				{
+					LoadBalancerName: aws.String("corp-apiserver"),
+					Scheme:           aws.String("internal"),
+					DNSName:          aws.String("corp-apiserver.elb.amazonaws.com"),
+					VPCId:            aws.String("vpc-corp"),
+				},
+			},
+		}, nil)
+	elbMock.EXPECT().DescribeLoadBalancerAttributes(gomock.AssignableToTypeOf(&elb.DescribeLoadBalancerAttributesInput{})).
+		Return(&elb.DescribeLoadBalancerAttributesOutput{
+			LoadBalancerAttributes: &elb.LoadBalancerAttributes{
+				CrossZoneLoadBalancing: &elb.CrossZoneLoadBalancing{Enabled: aws.Bool(false)},
+			},
+		}, nil)
+	elbMock.EXPECT().DeregisterInstancesFromLoadBalancer(gomock.Eq(&elb.DeregisterInstancesFromLoadBalancerInput{
+		Instances:        []*elb.Instance{{InstanceId: aws.String("i-controlplane")}},
+		LoadBalancerName: aws.String("corp-apiserver"),
+	})).
+		Return(&elb.DeregisterInstancesFromLoadBalancerOutput{}, nil)
+
+	s := NewService(clusterScope)
+	if err := s.ReconcileLoadbalancers(); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	if e, a := "corp-apiserver.elb.amazonaws.com", clusterScope.Network().APIServerELB.DNSName; e != a {
+		t.Errorf("DNS name: expected %q, got %q", e, a)
+	}
+
+	if err := s.DeregisterInstanceFromAPIServerELB(&infrav1.Instance{ID: "i-controlplane"}); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
+
+func TestGetAPIServerClassicELBSpec_IdleTimeout(t *testing.T) {
+	tests := []struct {
+		name              string
+		lb                *infrav1.AWSLoadBalancerSpec
+		expectIdleTimeout time.Duration
+	}{
+		{
+			name:              "nil load balancer config",
+			lb:                nil,
+			expectIdleTimeout: 10 * time.Minute,
+		},
+		{
+			name: "load balancer config with idle timeout",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				IdleTimeout: &metav1.Duration{Duration: time.Hour},
+			},
+			expectIdleTimeout: time.Hour,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						ControlPlaneLoadBalancer: tc.lb,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &Service{
+				scope: clusterScope,
+			}
+
+			spec, err := s.getAPIServerClassicELBSpec()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if e, a := tc.expectIdleTimeout, spec.Attributes.IdleTimeout; e != a {
+				t.Errorf("idle timeout: expected %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestGetAPIServerClassicELBSpec_AccessLogs(t *testing.T) {
+	tests := []struct {
+		name            string
+		lb              *infrav1.AWSLoadBalancerSpec
+		expectAccessLog *infrav1.ClassicELBAccessLog
+	}{
+		{
+			name:            "nil load balancer config",
+			lb:              nil,
+			expectAccessLog: nil,
+		},
+		{
+			name: "access logs with default emit interval",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				AccessLogs: &infrav1.AWSLoadBalancerAccessLogs{
+					BucketName:   "audit-logs",
+					BucketPrefix: "prod",
+				},
+			},
+			expectAccessLog: &infrav1.ClassicELBAccessLog{
+				S3BucketName:   "audit-logs",
+				S3BucketPrefix: "prod",
+				EmitInterval:   60,
+			},
+		},
+		{
+			name: "access logs with emit interval",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				AccessLogs: &infrav1.AWSLoadBalancerAccessLogs{
+					BucketName:   "audit-logs",
+					EmitInterval: aws.Int64(5),
+				},
+			},
+			expectAccessLog: &infrav1.ClassicELBAccessLog{
+				S3BucketName: "audit-logs",
+				EmitInterval: 5,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						ControlPlaneLoadBalancer: tc.lb,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &Service{
+				scope: clusterScope,
+			}
+
+			spec, err := s.getAPIServerClassicELBSpec()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.expectAccessLog, spec.Attributes.AccessLog) {
+				t.Errorf("access log: expected %+v, got %+v", tc.expectAccessLog, spec.Attributes.AccessLog)
+			}
+		})
+	}
+}
+
+func TestGetAPIServerClassicELBSpec_AdditionalSecurityGroups(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lb                     *infrav1.AWSLoadBalancerSpec
+		expectSecurityGroupIDs []string
+	}{
+		{
+			name:                   "nil load balancer config",
+			lb:                     nil,
+			expectSecurityGroupIDs: []string{"sg-apiserver-lb"},
+		},
+		{
+			name: "load balancer config with additional security groups",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				AdditionalSecurityGroups: []string{"sg-allowlist", "sg-inspection"},
+			},
+			expectSecurityGroupIDs: []string{"sg-apiserver-lb", "sg-allowlist", "sg-inspection"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						ControlPlaneLoadBalancer: tc.lb,
+					},
+					Status: infrav1.AWSClusterStatus{
+						Network: infrav1.Network{
+							SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+								infrav1.SecurityGroupAPIServerLB: {ID: "sg-apiserver-lb"},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &Service{
+				scope: clusterScope,
+			}
+
+			spec, err := s.getAPIServerClassicELBSpec()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.expectSecurityGroupIDs, spec.SecurityGroupIDs) {
+				t.Errorf("security groups: expected %v, got %v", tc.expectSecurityGroupIDs, spec.SecurityGroupIDs)
+			}
+		})
+	}
+}
+
+func TestGetAPIServerClassicELBSpec_APIServerPort(t *testing.T) {
+	tests := []struct {
+		name               string
+		clusterPort        *int32
+		awsPort            *int32
+		expectPort         int64
+		expectInstancePort int64
+		expectTarget       string
+	}{
+		{
+			name:               "default port",
+			expectPort:         6443,
+			expectInstancePort: 6443,
+			expectTarget:       "SSL:6443",
+		},
+		{
+			name:               "cluster network port only changes the listener port",
+			clusterPort:        pointer.Int32Ptr(8443),
+			expectPort:         8443,
+			expectInstancePort: 6443,
+			expectTarget:       "SSL:6443",
+		},
+		{
+			name:               "aws cluster port takes precedence",
+			clusterPort:        pointer.Int32Ptr(8443),
+			awsPort:            pointer.Int32Ptr(443),
+			expectPort:         443,
+			expectInstancePort: 443,
+			expectTarget:       "SSL:443",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+					Spec: clusterv1.ClusterSpec{
+						ClusterNetwork: &clusterv1.ClusterNetwork{
+							APIServerPort: tc.clusterPort,
+						},
+					},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						APIServerPort: tc.awsPort,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &Service{
+				scope: clusterScope,
+			}
+
+			spec, err := s.getAPIServerClassicELBSpec()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if e, a := tc.expectPort, spec.Listeners[0].Port; e != a {
+				t.Errorf("listener port: expected %v, got %v", e, a)
+			}
+			if e, a := tc.expectInstancePort, spec.Listeners[0].InstancePort; e != a {
+				t.Errorf("listener instance port: expected %v, got %v", e, a)
+			}
+			if e, a := tc.expectTarget, spec.HealthCheck.Target; e != a {
+				t.Errorf("health check target: expected %v, got %v", e, a)
+			}
 		})
 	}
+}
+
+func TestGetAPIServerClassicELBSpec_Subnets(t *testing.T) {
+	tests := []struct {
+		name            string
+		lb              *infrav1.AWSLoadBalancerSpec
+		expectSubnetIDs []string
+		expectErr       bool
+	}{
+		{
+			name:            "public subnets by default",
+			lb:              nil,
+			expectSubnetIDs: []string{"subnet-public-a", "subnet-public-b"},
+		},
+		{
+			name: "subnet overrides",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				Subnets: []string{"subnet-lb-a"},
+			},
+			expectSubnetIDs: []string{"subnet-lb-a"},
+		},
+		{
+			name: "unknown subnet",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				Subnets: []string{"subnet-unknown"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "more than one subnet in an availability zone",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				Subnets: []string{"subnet-lb-a", "subnet-public-a"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						ControlPlaneLoadBalancer: tc.lb,
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{ID: "subnet-public-a", AvailabilityZone: "us-east-1a", IsPublic: true},
+								{ID: "subnet-public-b", AvailabilityZone: "us-east-1b", IsPublic: true},
+								{ID: "subnet-lb-a", AvailabilityZone: "us-east-1a"},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &Service{
+				scope: clusterScope,
+			}
+
+			spec, err := s.getAPIServerClassicELBSpec()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectSubnetIDs, spec.SubnetIDs) {
+				t.Errorf("subnets: expected %v, got %v", tc.expectSubnetIDs, spec.SubnetIDs)
+			}
+		})
+	}
+}
+
+func TestReconcileELBTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	elbMock := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo",
+				Name:      "bar",
+			},
+		},
+		AWSClients: scope.AWSClients{
+			ELB: elbMock,
+		},
+		AWSCluster: &infrav1.AWSCluster{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	elbMock.EXPECT().DescribeTags(gomock.Eq(&elb.DescribeTagsInput{
+		LoadBalancerNames: aws.StringSlice([]string{"bar-apiserver"}),
+	})).
+		Return(&elb.DescribeTagsOutput{
+			TagDescriptions: []*elb.TagDescription{
+				{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					Tags: []*elb.Tag{
+						{Key: aws.String("cost-center"), Value: aws.String("old")},
+						{Key: aws.String("team"), Value: aws.String("platform")},
+						{Key: aws.String("aws:cloudformation:stack-name"), Value: aws.String("stack")},
+					},
+				},
+			},
+		}, nil)
+	elbMock.EXPECT().AddTags(gomock.Eq(&elb.AddTagsInput{
+		LoadBalancerNames: aws.StringSlice([]string{"bar-apiserver"}),
+		Tags:              []*elb.Tag{{Key: aws.String("cost-center"), Value: aws.String("new")}},
+	})).
+		Return(&elb.AddTagsOutput{}, nil)
+	// Reserved aws: tags are left alone.
+	elbMock.EXPECT().RemoveTags(gomock.Eq(&elb.RemoveTagsInput{
+		LoadBalancerNames: aws.StringSlice([]string{"bar-apiserver"}),
+		Tags:              []*elb.TagKeyOnly{{Key: aws.String("team")}},
+	})).
+		Return(&elb.RemoveTagsOutput{}, nil)
+
+	s := NewService(clusterScope)
+	if err := s.reconcileELBTags("bar-apiserver", map[string]string{"cost-center": "new"}); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+}
 
+func TestConfigureProxyProtocol(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		expect  func(m *mock_elbiface.MockELBAPIMockRecorder)
+	}{
+		{
+			name:    "enable proxy protocol",
+			enabled: true,
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.CreateLoadBalancerPolicy(gomock.AssignableToTypeOf(&elb.CreateLoadBalancerPolicyInput{})).
+					Return(nil, awserr.New(elb.ErrCodeDuplicatePolicyNameException, "duplicate", nil))
+				m.SetLoadBalancerPoliciesForBackendServer(gomock.Eq(&elb.SetLoadBalancerPoliciesForBackendServerInput{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					InstancePort:     aws.Int64(6443),
+					PolicyNames:      aws.StringSlice([]string{proxyProtocolPolicyName}),
+				})).
+					Return(&elb.SetLoadBalancerPoliciesForBackendServerOutput{}, nil)
+			},
+		},
+		{
+			name:    "disable proxy protocol",
+			enabled: false,
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.SetLoadBalancerPoliciesForBackendServer(gomock.Eq(&elb.SetLoadBalancerPoliciesForBackendServerInput{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					InstancePort:     aws.Int64(6443),
+					PolicyNames:      aws.StringSlice(nil),
+				})).
+					Return(&elb.SetLoadBalancerPoliciesForBackendServerOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			elbMock := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSClients: scope.AWSClients{
+					ELB: elbMock,
+				},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(elbMock.EXPECT())
+
+			s := NewService(clusterScope)
+			listeners := []*infrav1.ClassicELBListener{{InstancePort: 6443}}
+			if err := s.configureProxyProtocol("bar-apiserver", listeners, tc.enabled); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestReconcileListeners(t *testing.T) {
+	listener := func(port, instancePort int64) *infrav1.ClassicELBListener {
+		return &infrav1.ClassicELBListener{
+			Protocol:         infrav1.ClassicELBProtocolTCP,
+			Port:             port,
+			InstanceProtocol: infrav1.ClassicELBProtocolTCP,
+			InstancePort:     instancePort,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		current       []*infrav1.ClassicELBListener
+		proxyProtocol bool
+		expect        func(m *mock_elbiface.MockELBAPIMockRecorder)
+	}{
+		{
+			name:    "listeners match, does nothing",
+			current: []*infrav1.ClassicELBListener{listener(443, 8443)},
+			expect:  func(m *mock_elbiface.MockELBAPIMockRecorder) {},
+		},
+		{
+			name:    "port changed, replaces the listener",
+			current: []*infrav1.ClassicELBListener{listener(6443, 6443)},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DeleteLoadBalancerListeners(gomock.Eq(&elb.DeleteLoadBalancerListenersInput{
+					LoadBalancerName:  aws.String("bar-apiserver"),
+					LoadBalancerPorts: aws.Int64Slice([]int64{6443}),
+				})).
+					Return(&elb.DeleteLoadBalancerListenersOutput{}, nil)
+				m.CreateLoadBalancerListeners(gomock.Eq(&elb.CreateLoadBalancerListenersInput{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					Listeners: []*elb.Listener{
+						{
+							Protocol:         aws.String("TCP"),
+							LoadBalancerPort: aws.Int64(443),
+							InstanceProtocol: aws.String("TCP"),
+							InstancePort:     aws.Int64(8443),
+						},
+					},
+				})).
+					Return(&elb.CreateLoadBalancerListenersOutput{}, nil)
+			},
+		},
+		{
+			name:          "instance port changed with proxy protocol, enables it on the new instance port",
+			current:       []*infrav1.ClassicELBListener{listener(443, 6443)},
+			proxyProtocol: true,
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DeleteLoadBalancerListeners(gomock.AssignableToTypeOf(&elb.DeleteLoadBalancerListenersInput{})).
+					Return(&elb.DeleteLoadBalancerListenersOutput{}, nil)
+				m.CreateLoadBalancerListeners(gomock.AssignableToTypeOf(&elb.CreateLoadBalancerListenersInput{})).
+					Return(&elb.CreateLoadBalancerListenersOutput{}, nil)
+				m.CreateLoadBalancerPolicy(gomock.AssignableToTypeOf(&elb.CreateLoadBalancerPolicyInput{})).
+					Return(nil, awserr.New(elb.ErrCodeDuplicatePolicyNameException, "duplicate", nil))
+				m.SetLoadBalancerPoliciesForBackendServer(gomock.Eq(&elb.SetLoadBalancerPoliciesForBackendServerInput{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					InstancePort:     aws.Int64(8443),
+					PolicyNames:      aws.StringSlice([]string{proxyProtocolPolicyName}),
+				})).
+					Return(&elb.SetLoadBalancerPoliciesForBackendServerOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			elbMock := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSClients: scope.AWSClients{
+					ELB: elbMock,
+				},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(elbMock.EXPECT())
+
+			s := NewService(clusterScope)
+			current := &infrav1.ClassicELB{
+				Name:          "bar-apiserver",
+				Listeners:     tc.current,
+				ProxyProtocol: tc.proxyProtocol,
+			}
+			spec := &infrav1.ClassicELB{
+				Name:      "bar-apiserver",
+				Listeners: []*infrav1.ClassicELBListener{listener(443, 8443)},
+			}
+			if err := s.reconcileListeners(current, spec); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestReconcileSubnets(t *testing.T) {
+	tests := []struct {
+		name       string
+		currentIDs []string
+		currentAZs []string
+		desiredIDs []string
+		desiredAZs []string
+		expect     func(m *mock_elbiface.MockELBAPIMockRecorder)
+	}{
+		{
+			name:       "subnets match, does nothing",
+			currentIDs: []string{"subnet-a", "subnet-b"},
+			currentAZs: []string{"us-east-1a", "us-east-1b"},
+			desiredIDs: []string{"subnet-a", "subnet-b"},
+			desiredAZs: []string{"us-east-1a", "us-east-1b"},
+			expect:     func(m *mock_elbiface.MockELBAPIMockRecorder) {},
+		},
+		{
+			name:       "disjoint availability zones, attaches before detaching",
+			currentIDs: []string{"subnet-a"},
+			currentAZs: []string{"us-east-1a"},
+			desiredIDs: []string{"subnet-b"},
+			desiredAZs: []string{"us-east-1b"},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				gomock.InOrder(
+					m.AttachLoadBalancerToSubnets(gomock.Eq(&elb.AttachLoadBalancerToSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-b"}),
+					})).
+						Return(&elb.AttachLoadBalancerToSubnetsOutput{}, nil),
+					m.DetachLoadBalancerFromSubnets(gomock.Eq(&elb.DetachLoadBalancerFromSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-a"}),
+					})).
+						Return(&elb.DetachLoadBalancerFromSubnetsOutput{}, nil),
+				)
+			},
+		},
+		{
+			name:       "disjoint subnets in the same availability zones, detaches before attaching",
+			currentIDs: []string{"subnet-a", "subnet-b"},
+			currentAZs: []string{"us-east-1a", "us-east-1b"},
+			desiredIDs: []string{"subnet-c", "subnet-b"},
+			desiredAZs: []string{"us-east-1a", "us-east-1b"},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				gomock.InOrder(
+					m.DetachLoadBalancerFromSubnets(gomock.Eq(&elb.DetachLoadBalancerFromSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-a"}),
+					})).
+						Return(&elb.DetachLoadBalancerFromSubnetsOutput{}, nil),
+					m.AttachLoadBalancerToSubnets(gomock.Eq(&elb.AttachLoadBalancerToSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-c"}),
+					})).
+						Return(&elb.AttachLoadBalancerToSubnetsOutput{}, nil),
+				)
+			},
+		},
+		{
+			name:       "moves to a new availability zone and replaces a subnet in another",
+			currentIDs: []string{"subnet-a"},
+			currentAZs: []string{"us-east-1a"},
+			desiredIDs: []string{"subnet-c", "subnet-d"},
+			desiredAZs: []string{"us-east-1a", "us-east-1b"},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				gomock.InOrder(
+					m.AttachLoadBalancerToSubnets(gomock.Eq(&elb.AttachLoadBalancerToSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-d"}),
+					})).
+						Return(&elb.AttachLoadBalancerToSubnetsOutput{}, nil),
+					m.DetachLoadBalancerFromSubnets(gomock.Eq(&elb.DetachLoadBalancerFromSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-a"}),
+					})).
+						Return(&elb.DetachLoadBalancerFromSubnetsOutput{}, nil),
+					m.AttachLoadBalancerToSubnets(gomock.Eq(&elb.AttachLoadBalancerToSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-c"}),
+					})).
+						Return(&elb.AttachLoadBalancerToSubnetsOutput{}, nil),
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			elbMock := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSClients: scope.AWSClients{
+					ELB: elbMock,
+				},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(elbMock.EXPECT())
+
+			s := NewService(clusterScope)
+			current := &infrav1.ClassicELB{
+				Name:              "bar-apiserver",
+				SubnetIDs:         tc.currentIDs,
+				AvailabilityZones: tc.currentAZs,
+			}
+			spec := &infrav1.ClassicELB{
+				Name:              "bar-apiserver",
+				SubnetIDs:         tc.desiredIDs,
+				AvailabilityZones: tc.desiredAZs,
+			}
+			if err := s.reconcileSubnets(current, spec); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.desiredIDs, current.SubnetIDs) {
+				t.Errorf("subnets: expected %v, got %v", tc.desiredIDs, current.SubnetIDs)
+			}
+		})
+	}
 }
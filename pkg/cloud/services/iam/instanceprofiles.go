@@ -0,0 +1,457 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/endpoints"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/internal/hash"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// InstanceProfileRoleControlPlane is the role of the instance profile used by control plane machines.
+	InstanceProfileRoleControlPlane = "control-plane"
+
+	// InstanceProfileRoleNodes is the role of the instance profile used by worker machines.
+	InstanceProfileRoleNodes = "nodes"
+
+	// maxRoleNameLength is the maximum length of an IAM role name.
+	maxRoleNameLength = 64
+
+	// ownerHashLength is the length of the hash of the cluster's namespace and region in managed names.
+	ownerHashLength = 8
+
+	// ownerTagKey is the tag holding the namespaced name of the cluster that owns a managed role,
+	// as the cluster tag doesn't tell clusters with the same name in different namespaces apart.
+	ownerTagKey = infrav1.NameAWSProviderPrefix + "owner"
+)
+
+// ManagedInstanceProfileName returns the name of the instance profile, and of its role,
+// managed for the machines of the given role in a cluster. IAM names are global to the account,
+// so the name ends with a hash of the namespace and region of the cluster.
+func ManagedInstanceProfileName(namespace, clusterName, region, role string) (string, error) {
+	ownerHash, err := hash.Base36TruncatedHash(fmt.Sprintf("%s/%s/%s", namespace, clusterName, region), ownerHashLength)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate IAM instance profile name for cluster %q", clusterName)
+	}
+
+	return fmt.Sprintf("%s-%s-%s", clusterName, role, ownerHash), nil
+}
+
+// ManagedInstanceProfileSpec returns the spec of the instance profile managed for the machines
+// of the given role, or nil if the cluster doesn't manage one.
+func ManagedInstanceProfileSpec(profiles *infrav1.IAMInstanceProfiles, role string) *infrav1.IAMInstanceProfileSpec {
+	if profiles == nil {
+		return nil
+	}
+
+	switch role {
+	case InstanceProfileRoleControlPlane:
+		return profiles.ControlPlane
+	case InstanceProfileRoleNodes:
+		return profiles.Nodes
+	}
+
+	return nil
+}
+
+// ReconcileInstanceProfiles creates or updates the instance profiles managed for the cluster along with their roles.
+func (s *Service) ReconcileInstanceProfiles() error {
+	for _, role := range []string{InstanceProfileRoleControlPlane, InstanceProfileRoleNodes} {
+		spec := ManagedInstanceProfileSpec(s.scope.AWSCluster.Spec.IAMInstanceProfiles, role)
+		if spec == nil {
+			continue
+		}
+
+		if err := s.reconcileInstanceProfile(role, spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteInstanceProfiles deletes the instance profiles managed for the cluster along with their roles.
+// Roles that aren't tagged as owned by the cluster are left untouched.
+func (s *Service) DeleteInstanceProfiles() error {
+	for _, role := range []string{InstanceProfileRoleControlPlane, InstanceProfileRoleNodes} {
+		name, err := ManagedInstanceProfileName(s.scope.Namespace(), s.scope.Name(), s.scope.Region(), role)
+		if err != nil {
+			return err
+		}
+
+		if err := s.deleteInstanceProfile(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) reconcileInstanceProfile(role string, spec *infrav1.IAMInstanceProfileSpec) error {
+	name, err := ManagedInstanceProfileName(s.scope.Namespace(), s.scope.Name(), s.scope.Region(), role)
+	if err != nil {
+		return err
+	}
+	if len(name) > maxRoleNameLength {
+		return errors.Errorf("failed to reconcile IAM instance profile %q, the name is longer than %d characters", name, maxRoleNameLength)
+	}
+
+	s.scope.V(2).Info("Reconciling IAM instance profile", "name", name)
+
+	iamRole, err := s.reconcileRole(name, role, spec)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reconcileRolePolicies(iamRole, role, spec); err != nil {
+		return err
+	}
+
+	profile, err := s.getInstanceProfile(name)
+	if err != nil {
+		return err
+	}
+
+	if profile == nil {
+		out, err := s.scope.IAM.CreateInstanceProfile(&iam.CreateInstanceProfileInput{
+			InstanceProfileName: aws.String(name),
+			Path:                aws.String(ManagedPath),
+		})
+		if err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedCreateInstanceProfile", "Failed to create IAM instance profile %q: %v", name, err)
+			return errors.Wrapf(err, "failed to create IAM instance profile %q", name)
+		}
+		record.Eventf(s.scope.AWSCluster, "SuccessfulCreateInstanceProfile", "Created IAM instance profile %q", name)
+		profile = out.InstanceProfile
+	}
+
+	for _, r := range profile.Roles {
+		if aws.StringValue(r.RoleName) == name {
+			return nil
+		}
+	}
+
+	if _, err := s.scope.IAM.AddRoleToInstanceProfile(&iam.AddRoleToInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+		RoleName:            aws.String(name),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to add IAM role %q to instance profile %q", name, name)
+	}
+
+	s.scope.V(2).Info("Reconciled IAM instance profile", "name", name)
+	return nil
+}
+
+func (s *Service) reconcileRole(name, role string, spec *infrav1.IAMInstanceProfileSpec) (*iam.Role, error) {
+	existing, err := s.getRole(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		assumeRolePolicy, err := ec2AssumeRolePolicy(s.scope.Partition()).JSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal assume role policy")
+		}
+
+		tags := infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.scope.Name(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Role:        aws.String(role),
+			Additional:  s.scope.AdditionalTags(),
+		})
+		tags[ownerTagKey] = s.owner()
+
+		out, err := s.scope.IAM.CreateRole(&iam.CreateRoleInput{
+			RoleName:                 aws.String(name),
+			Path:                     aws.String(ManagedPath),
+			AssumeRolePolicyDocument: aws.String(assumeRolePolicy),
+			PermissionsBoundary:      spec.PermissionsBoundary,
+			Tags:                     converters.MapToIAMTags(tags),
+		})
+		if err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedCreateIAMRole", "Failed to create IAM role %q: %v", name, err)
+			return nil, errors.Wrapf(err, "failed to create IAM role %q", name)
+		}
+
+		record.Eventf(s.scope.AWSCluster, "SuccessfulCreateIAMRole", "Created IAM role %q", name)
+		return out.Role, nil
+	}
+
+	if !s.isOwned(existing) {
+		return nil, errors.Errorf("IAM role %q already exists and isn't owned by cluster %q", name, s.owner())
+	}
+
+	current := ""
+	if existing.PermissionsBoundary != nil {
+		current = aws.StringValue(existing.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+	desired := aws.StringValue(spec.PermissionsBoundary)
+
+	// The controllers aren't allowed to remove a permissions boundary, it's kept until the role is deleted.
+	if desired == "" || current == desired {
+		return existing, nil
+	}
+
+	if _, err := s.scope.IAM.PutRolePermissionsBoundary(&iam.PutRolePermissionsBoundaryInput{
+		RoleName:            aws.String(name),
+		PermissionsBoundary: aws.String(desired),
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to set permissions boundary of IAM role %q", name)
+	}
+
+	return existing, nil
+}
+
+func (s *Service) reconcileRolePolicies(iamRole *iam.Role, role string, spec *infrav1.IAMInstanceProfileSpec) error {
+	name := aws.StringValue(iamRole.RoleName)
+
+	desired := spec.ManagedPolicyARNs
+	if len(desired) == 0 {
+		defaults, err := defaultManagedPolicyARNs(aws.StringValue(iamRole.Arn), role)
+		if err != nil {
+			return err
+		}
+		desired = defaults
+	}
+
+	if s.scope.AWSCluster.Spec.SessionManager.Enabled {
+		policyARN, err := sessionManagerPolicyARN(aws.StringValue(iamRole.Arn))
+		if err != nil {
+			return err
+		}
+		if !containsString(desired, policyARN) {
+			desired = append(append([]string{}, desired...), policyARN)
+		}
+	}
+
+	attached, err := s.listAttachedRolePolicies(name)
+	if err != nil {
+		return err
+	}
+
+	for _, policyARN := range desired {
+		if attached[policyARN] {
+			continue
+		}
+
+		if _, err := s.scope.IAM.AttachRolePolicy(&iam.AttachRolePolicyInput{
+			RoleName:  aws.String(name),
+			PolicyArn: aws.String(policyARN),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to attach policy %q to IAM role %q", policyARN, name)
+		}
+	}
+
+	for policyARN := range attached {
+		if containsString(desired, policyARN) {
+			continue
+		}
+
+		if _, err := s.scope.IAM.DetachRolePolicy(&iam.DetachRolePolicyInput{
+			RoleName:  aws.String(name),
+			PolicyArn: aws.String(policyARN),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to detach policy %q from IAM role %q", policyARN, name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteInstanceProfile(name string) error {
+	existing, err := s.getRole(name)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil || !s.isOwned(existing) {
+		return nil
+	}
+
+	s.scope.V(2).Info("Deleting IAM instance profile", "name", name)
+
+	profile, err := s.getInstanceProfile(name)
+	if err != nil {
+		return err
+	}
+
+	if profile != nil {
+		for _, r := range profile.Roles {
+			if _, err := s.scope.IAM.RemoveRoleFromInstanceProfile(&iam.RemoveRoleFromInstanceProfileInput{
+				InstanceProfileName: aws.String(name),
+				RoleName:            r.RoleName,
+			}); err != nil {
+				return errors.Wrapf(err, "failed to remove IAM role %q from instance profile %q", aws.StringValue(r.RoleName), name)
+			}
+		}
+
+		if _, err := s.scope.IAM.DeleteInstanceProfile(&iam.DeleteInstanceProfileInput{
+			InstanceProfileName: aws.String(name),
+		}); err != nil {
+			record.Warnf(s.scope.AWSCluster, "FailedDeleteInstanceProfile", "Failed to delete IAM instance profile %q: %v", name, err)
+			return errors.Wrapf(err, "failed to delete IAM instance profile %q", name)
+		}
+		record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteInstanceProfile", "Deleted IAM instance profile %q", name)
+	}
+
+	attached, err := s.listAttachedRolePolicies(name)
+	if err != nil {
+		return err
+	}
+
+	for policyARN := range attached {
+		if _, err := s.scope.IAM.DetachRolePolicy(&iam.DetachRolePolicyInput{
+			RoleName:  aws.String(name),
+			PolicyArn: aws.String(policyARN),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to detach policy %q from IAM role %q", policyARN, name)
+		}
+	}
+
+	if _, err := s.scope.IAM.DeleteRole(&iam.DeleteRoleInput{
+		RoleName: aws.String(name),
+	}); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteIAMRole", "Failed to delete IAM role %q: %v", name, err)
+		return errors.Wrapf(err, "failed to delete IAM role %q", name)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteIAMRole", "Deleted IAM role %q", name)
+	s.scope.V(2).Info("Deleted IAM instance profile", "name", name)
+	return nil
+}
+
+// owner returns the namespaced name of the cluster, the value of the owner tag of its managed roles.
+func (s *Service) owner() string {
+	return fmt.Sprintf("%s/%s", s.scope.Namespace(), s.scope.Name())
+}
+
+// isOwned returns true if the role is tagged as owned by the cluster.
+func (s *Service) isOwned(role *iam.Role) bool {
+	tags := converters.IAMTagsToMap(role.Tags)
+	return tags.HasOwned(s.scope.Name()) && tags[ownerTagKey] == s.owner()
+}
+
+func (s *Service) getRole(name string) (*iam.Role, error) {
+	out, err := s.scope.IAM.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(name),
+	})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get IAM role %q", name)
+	}
+
+	return out.Role, nil
+}
+
+func (s *Service) getInstanceProfile(name string) (*iam.InstanceProfile, error) {
+	out, err := s.scope.IAM.GetInstanceProfile(&iam.GetInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+	})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get IAM instance profile %q", name)
+	}
+
+	return out.InstanceProfile, nil
+}
+
+// listAttachedRolePolicies returns the ARNs of the managed policies attached to a role.
+// A role can't have more managed policies attached than fit in a single page.
+func (s *Service) listAttachedRolePolicies(name string) (map[string]bool, error) {
+	out, err := s.scope.IAM.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(name),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list policies attached to IAM role %q", name)
+	}
+
+	attached := make(map[string]bool, len(out.AttachedPolicies))
+	for _, p := range out.AttachedPolicies {
+		attached[aws.StringValue(p.PolicyArn)] = true
+	}
+
+	return attached, nil
+}
+
+// defaultManagedPolicyARNs returns the ARNs of the policies clusterawsadm creates for machines of the given role,
+// in the partition and account of the role.
+func defaultManagedPolicyARNs(roleARN, role string) ([]string, error) {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse IAM role ARN %q", roleARN)
+	}
+
+	names := []string{NewManagedName("nodes")}
+	if role == InstanceProfileRoleControlPlane {
+		names = []string{NewManagedName("control-plane"), NewManagedName("nodes")}
+	}
+
+	arns := make([]string, 0, len(names))
+	for _, name := range names {
+		arns = append(arns, fmt.Sprintf("arn:%s:iam::%s:policy/%s", parsed.Partition, parsed.AccountID, name))
+	}
+
+	return arns, nil
+}
+
+// sessionManagerPolicyARN returns the ARN of the AWS managed policy the SSM agent needs to register
+// the instance with Session Manager, in the partition of the given role.
+func sessionManagerPolicyARN(roleARN string) (string, error) {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse IAM role ARN %q", roleARN)
+	}
+
+	return fmt.Sprintf("arn:%s:iam::aws:policy/AmazonSSMManagedInstanceCore", parsed.Partition), nil
+}
+
+func ec2AssumeRolePolicy(partition string) *PolicyDocument {
+	return &PolicyDocument{
+		Version: CurrentVersion,
+		Statement: []StatementEntry{
+			{
+				Effect:    EffectAllow,
+				Principal: Principals{PrincipalService: PrincipalID{endpoints.ServicePrincipal("ec2", partition)}},
+				Action:    Actions{"sts:AssumeRole"},
+			},
+		},
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
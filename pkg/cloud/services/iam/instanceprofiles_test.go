@@ -0,0 +1,366 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam/mock_iamiface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+const (
+	testRoleName = "test-cluster-nodes-8esqhl2y"
+	testRoleARN  = "arn:aws:iam::123456789012:role/cluster-api-provider-aws.sigs.k8s.io/test-cluster-nodes-8esqhl2y"
+	testNodesARN = "arn:aws:iam::123456789012:policy/nodes.cluster-api-provider-aws.sigs.k8s.io"
+)
+
+var ownedRoleTags = []*iam.Tag{
+	{
+		Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+		Value: aws.String("owned"),
+	},
+	{
+		Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/owner"),
+		Value: aws.String("default/test-cluster"),
+	},
+}
+
+func TestReconcileInstanceProfiles(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	notFound := awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil)
+
+	testCases := []struct {
+		name           string
+		spec           *infrav1.IAMInstanceProfileSpec
+		sessionManager bool
+		expect         func(m *mock_iamiface.MockIAMAPIMockRecorder)
+		expectErr      bool
+	}{
+		{
+			name: "creates the role and the instance profile with the default policies",
+			spec: &infrav1.IAMInstanceProfileSpec{
+				PermissionsBoundary: aws.String("arn:aws:iam::123456789012:policy/boundary"),
+			},
+			expect: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				gomock.InOrder(
+					m.GetRole(gomock.Eq(&iam.GetRoleInput{RoleName: aws.String(testRoleName)})).
+						Return(nil, notFound),
+					m.CreateRole(gomock.AssignableToTypeOf(&iam.CreateRoleInput{})).
+						DoAndReturn(func(input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+							if aws.StringValue(input.Path) != ManagedPath {
+								t.Fatalf("expected role path %q, got %q", ManagedPath, aws.StringValue(input.Path))
+							}
+							if owner := converters.IAMTagsToMap(input.Tags)["sigs.k8s.io/cluster-api-provider-aws/owner"]; owner != "default/test-cluster" {
+								t.Fatalf("expected role to be tagged with its owner, got %q", owner)
+							}
+							if aws.StringValue(input.PermissionsBoundary) != "arn:aws:iam::123456789012:policy/boundary" {
+								t.Fatalf("expected permissions boundary to be set, got %q", aws.StringValue(input.PermissionsBoundary))
+							}
+							return &iam.CreateRoleOutput{
+								Role: &iam.Role{
+									RoleName: input.RoleName,
+									Arn:      aws.String(testRoleARN),
+								},
+							}, nil
+						}),
+					m.ListAttachedRolePolicies(gomock.Eq(&iam.ListAttachedRolePoliciesInput{RoleName: aws.String(testRoleName)})).
+						Return(&iam.ListAttachedRolePoliciesOutput{}, nil),
+					m.AttachRolePolicy(gomock.Eq(&iam.AttachRolePolicyInput{
+						RoleName:  aws.String(testRoleName),
+						PolicyArn: aws.String(testNodesARN),
+					})).
+						Return(&iam.AttachRolePolicyOutput{}, nil),
+					m.GetInstanceProfile(gomock.Eq(&iam.GetInstanceProfileInput{InstanceProfileName: aws.String(testRoleName)})).
+						Return(nil, notFound),
+					m.CreateInstanceProfile(gomock.Eq(&iam.CreateInstanceProfileInput{
+						InstanceProfileName: aws.String(testRoleName),
+						Path:                aws.String(ManagedPath),
+					})).
+						Return(&iam.CreateInstanceProfileOutput{
+							InstanceProfile: &iam.InstanceProfile{InstanceProfileName: aws.String(testRoleName)},
+						}, nil),
+					m.AddRoleToInstanceProfile(gomock.Eq(&iam.AddRoleToInstanceProfileInput{
+						InstanceProfileName: aws.String(testRoleName),
+						RoleName:            aws.String(testRoleName),
+					})).
+						Return(&iam.AddRoleToInstanceProfileOutput{}, nil),
+				)
+			},
+		},
+		{
+			name: "updates the policies and keeps the permissions boundary of an existing role",
+			spec: &infrav1.IAMInstanceProfileSpec{
+				ManagedPolicyARNs: []string{"arn:aws:iam::123456789012:policy/custom"},
+			},
+			expect: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				gomock.InOrder(
+					m.GetRole(gomock.Any()).
+						Return(&iam.GetRoleOutput{
+							Role: &iam.Role{
+								RoleName: aws.String(testRoleName),
+								Arn:      aws.String(testRoleARN),
+								Tags:     ownedRoleTags,
+								PermissionsBoundary: &iam.AttachedPermissionsBoundary{
+									PermissionsBoundaryArn: aws.String("arn:aws:iam::123456789012:policy/boundary"),
+								},
+							},
+						}, nil),
+					m.ListAttachedRolePolicies(gomock.Any()).
+						Return(&iam.ListAttachedRolePoliciesOutput{
+							AttachedPolicies: []*iam.AttachedPolicy{
+								{PolicyArn: aws.String(testNodesARN)},
+							},
+						}, nil),
+					m.AttachRolePolicy(gomock.Eq(&iam.AttachRolePolicyInput{
+						RoleName:  aws.String(testRoleName),
+						PolicyArn: aws.String("arn:aws:iam::123456789012:policy/custom"),
+					})).
+						Return(&iam.AttachRolePolicyOutput{}, nil),
+					m.DetachRolePolicy(gomock.Eq(&iam.DetachRolePolicyInput{
+						RoleName:  aws.String(testRoleName),
+						PolicyArn: aws.String(testNodesARN),
+					})).
+						Return(&iam.DetachRolePolicyOutput{}, nil),
+					m.GetInstanceProfile(gomock.Any()).
+						Return(&iam.GetInstanceProfileOutput{
+							InstanceProfile: &iam.InstanceProfile{
+								InstanceProfileName: aws.String(testRoleName),
+								Roles: []*iam.Role{
+									{RoleName: aws.String(testRoleName)},
+								},
+							},
+						}, nil),
+				)
+			},
+		},
+		{
+			name:           "attaches the session manager policy when session manager access is enabled",
+			spec:           &infrav1.IAMInstanceProfileSpec{},
+			sessionManager: true,
+			expect: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				gomock.InOrder(
+					m.GetRole(gomock.Any()).
+						Return(&iam.GetRoleOutput{
+							Role: &iam.Role{
+								RoleName: aws.String(testRoleName),
+								Arn:      aws.String(testRoleARN),
+								Tags:     ownedRoleTags,
+							},
+						}, nil),
+					m.ListAttachedRolePolicies(gomock.Any()).
+						Return(&iam.ListAttachedRolePoliciesOutput{
+							AttachedPolicies: []*iam.AttachedPolicy{
+								{PolicyArn: aws.String(testNodesARN)},
+							},
+						}, nil),
+					m.AttachRolePolicy(gomock.Eq(&iam.AttachRolePolicyInput{
+						RoleName:  aws.String(testRoleName),
+						PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"),
+					})).
+						Return(&iam.AttachRolePolicyOutput{}, nil),
+					m.GetInstanceProfile(gomock.Any()).
+						Return(&iam.GetInstanceProfileOutput{
+							InstanceProfile: &iam.InstanceProfile{
+								InstanceProfileName: aws.String(testRoleName),
+								Roles: []*iam.Role{
+									{RoleName: aws.String(testRoleName)},
+								},
+							},
+						}, nil),
+				)
+			},
+		},
+		{
+			name: "fails when a role with the same name isn't owned by the cluster",
+			spec: &infrav1.IAMInstanceProfileSpec{},
+			expect: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.GetRole(gomock.Any()).
+					Return(&iam.GetRoleOutput{
+						Role: &iam.Role{
+							RoleName: aws.String(testRoleName),
+							Arn:      aws.String(testRoleARN),
+						},
+					}, nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			iamMock := mock_iamiface.NewMockIAMAPI(mockCtrl)
+
+			clusterScope := newTestScope(t, iamMock, &infrav1.IAMInstanceProfiles{Nodes: tc.spec})
+			clusterScope.AWSCluster.Spec.SessionManager.Enabled = tc.sessionManager
+			s := NewService(clusterScope)
+			tc.expect(iamMock.EXPECT())
+
+			err := s.ReconcileInstanceProfiles()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteInstanceProfiles(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	notFound := awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil)
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_iamiface.MockIAMAPIMockRecorder)
+	}{
+		{
+			name: "deletes the owned instance profile and role",
+			expect: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.GetRole(gomock.Eq(&iam.GetRoleInput{RoleName: aws.String("test-cluster-control-plane-8esqhl2y")})).
+					Return(nil, notFound)
+				gomock.InOrder(
+					m.GetRole(gomock.Eq(&iam.GetRoleInput{RoleName: aws.String(testRoleName)})).
+						Return(&iam.GetRoleOutput{
+							Role: &iam.Role{
+								RoleName: aws.String(testRoleName),
+								Tags:     ownedRoleTags,
+							},
+						}, nil),
+					m.GetInstanceProfile(gomock.Any()).
+						Return(&iam.GetInstanceProfileOutput{
+							InstanceProfile: &iam.InstanceProfile{
+								InstanceProfileName: aws.String(testRoleName),
+								Roles: []*iam.Role{
+									{RoleName: aws.String(testRoleName)},
+								},
+							},
+						}, nil),
+					m.RemoveRoleFromInstanceProfile(gomock.Eq(&iam.RemoveRoleFromInstanceProfileInput{
+						InstanceProfileName: aws.String(testRoleName),
+						RoleName:            aws.String(testRoleName),
+					})).
+						Return(&iam.RemoveRoleFromInstanceProfileOutput{}, nil),
+					m.DeleteInstanceProfile(gomock.Eq(&iam.DeleteInstanceProfileInput{
+						InstanceProfileName: aws.String(testRoleName),
+					})).
+						Return(&iam.DeleteInstanceProfileOutput{}, nil),
+					m.ListAttachedRolePolicies(gomock.Any()).
+						Return(&iam.ListAttachedRolePoliciesOutput{
+							AttachedPolicies: []*iam.AttachedPolicy{
+								{PolicyArn: aws.String(testNodesARN)},
+							},
+						}, nil),
+					m.DetachRolePolicy(gomock.Eq(&iam.DetachRolePolicyInput{
+						RoleName:  aws.String(testRoleName),
+						PolicyArn: aws.String(testNodesARN),
+					})).
+						Return(&iam.DetachRolePolicyOutput{}, nil),
+					m.DeleteRole(gomock.Eq(&iam.DeleteRoleInput{RoleName: aws.String(testRoleName)})).
+						Return(&iam.DeleteRoleOutput{}, nil),
+				)
+			},
+		},
+		{
+			name: "leaves roles that aren't owned by the cluster",
+			expect: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.GetRole(gomock.Eq(&iam.GetRoleInput{RoleName: aws.String("test-cluster-control-plane-8esqhl2y")})).
+					Return(nil, notFound)
+				m.GetRole(gomock.Eq(&iam.GetRoleInput{RoleName: aws.String(testRoleName)})).
+					Return(&iam.GetRoleOutput{
+						Role: &iam.Role{RoleName: aws.String(testRoleName)},
+					}, nil)
+			},
+		},
+		{
+			name: "leaves roles owned by a cluster with the same name in another namespace",
+			expect: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.GetRole(gomock.Eq(&iam.GetRoleInput{RoleName: aws.String("test-cluster-control-plane-8esqhl2y")})).
+					Return(nil, notFound)
+				m.GetRole(gomock.Eq(&iam.GetRoleInput{RoleName: aws.String(testRoleName)})).
+					Return(&iam.GetRoleOutput{
+						Role: &iam.Role{
+							RoleName: aws.String(testRoleName),
+							Tags: []*iam.Tag{
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+									Value: aws.String("owned"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/owner"),
+									Value: aws.String("other/test-cluster"),
+								},
+							},
+						},
+					}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			iamMock := mock_iamiface.NewMockIAMAPI(mockCtrl)
+
+			s := NewService(newTestScope(t, iamMock, nil))
+			tc.expect(iamMock.EXPECT())
+
+			if err := s.DeleteInstanceProfiles(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func newTestScope(t *testing.T, iamMock *mock_iamiface.MockIAMAPI, profiles *infrav1.IAMInstanceProfiles) *scope.ClusterScope {
+	t.Helper()
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+		},
+		AWSClients: scope.AWSClients{
+			IAM: iamMock,
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				Region:              "us-west-2",
+				IAMInstanceProfiles: profiles,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	return scope
+}
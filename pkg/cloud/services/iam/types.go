@@ -38,6 +38,9 @@ const (
 	// IAMSuffix is a standard prefix for resources for Cluster API Provider AWS
 	IAMSuffix = "cluster-api-provider-aws.sigs.k8s.io"
 
+	// ManagedPath is the path of the IAM roles and instance profiles managed by the controllers.
+	ManagedPath = "/" + IAMSuffix + "/"
+
 	// PrincipalAWS is the principal covering AWS arns.
 	PrincipalAWS = "AWS"
 
@@ -30,12 +30,17 @@ type EC2MachineInterface interface {
 	GetRunningInstanceByTags(scope *scope.MachineScope) (*infrav1.Instance, error)
 
 	GetCoreSecurityGroups(machine *scope.MachineScope) ([]string, error)
+	GetAdditionalSecurityGroupsIDs(machine *scope.MachineScope) ([]string, error)
 	GetInstanceSecurityGroups(instanceID string) (map[string][]string, error)
 	UpdateInstanceSecurityGroups(id string, securityGroups []string) error
 	UpdateResourceTags(resourceID *string, create map[string]string, remove map[string]string) error
+	UpdateInstanceResourceTags(instance *infrav1.Instance, create map[string]string, remove map[string]string) error
 
 	TerminateInstanceAndWait(instanceID string) error
 	DetachSecurityGroupsFromNetworkInterface(groups []string, interfaceID string) error
+
+	ReconcileElasticIP(scope *scope.MachineScope, instance *infrav1.Instance) error
+	ReleaseElasticIP(scope *scope.MachineScope) error
 }
 
 // SecretsManagerInterface encapsulated the methods exposed to the
@@ -44,3 +49,10 @@ type SecretsManagerInterface interface {
 	Delete(m *scope.MachineScope) error
 	Create(m *scope.MachineScope, data []byte) (string, int32, error)
 }
+
+// ObjectStoreInterface encapsulates the methods exposed to the machine
+// actuator to store bootstrap data in S3
+type ObjectStoreInterface interface {
+	Delete(m *scope.MachineScope) error
+	Create(m *scope.MachineScope, data []byte) (objectURL string, err error)
+}
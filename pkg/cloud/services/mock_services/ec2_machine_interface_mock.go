@@ -79,6 +79,21 @@ func (mr *MockEC2MachineInterfaceMockRecorder) DetachSecurityGroupsFromNetworkIn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachSecurityGroupsFromNetworkInterface", reflect.TypeOf((*MockEC2MachineInterface)(nil).DetachSecurityGroupsFromNetworkInterface), arg0, arg1)
 }
 
+// GetAdditionalSecurityGroupsIDs mocks base method
+func (m *MockEC2MachineInterface) GetAdditionalSecurityGroupsIDs(arg0 *scope.MachineScope) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdditionalSecurityGroupsIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdditionalSecurityGroupsIDs indicates an expected call of GetAdditionalSecurityGroupsIDs
+func (mr *MockEC2MachineInterfaceMockRecorder) GetAdditionalSecurityGroupsIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdditionalSecurityGroupsIDs", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetAdditionalSecurityGroupsIDs), arg0)
+}
+
 // GetCoreSecurityGroups mocks base method
 func (m *MockEC2MachineInterface) GetCoreSecurityGroups(arg0 *scope.MachineScope) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +154,34 @@ func (mr *MockEC2MachineInterfaceMockRecorder) InstanceIfExists(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceIfExists", reflect.TypeOf((*MockEC2MachineInterface)(nil).InstanceIfExists), arg0)
 }
 
+// ReconcileElasticIP mocks base method
+func (m *MockEC2MachineInterface) ReconcileElasticIP(arg0 *scope.MachineScope, arg1 *v1alpha3.Instance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileElasticIP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileElasticIP indicates an expected call of ReconcileElasticIP
+func (mr *MockEC2MachineInterfaceMockRecorder) ReconcileElasticIP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileElasticIP", reflect.TypeOf((*MockEC2MachineInterface)(nil).ReconcileElasticIP), arg0, arg1)
+}
+
+// ReleaseElasticIP mocks base method
+func (m *MockEC2MachineInterface) ReleaseElasticIP(arg0 *scope.MachineScope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseElasticIP", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseElasticIP indicates an expected call of ReleaseElasticIP
+func (mr *MockEC2MachineInterfaceMockRecorder) ReleaseElasticIP(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseElasticIP", reflect.TypeOf((*MockEC2MachineInterface)(nil).ReleaseElasticIP), arg0)
+}
+
 // TerminateInstance mocks base method
 func (m *MockEC2MachineInterface) TerminateInstance(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -167,6 +210,20 @@ func (mr *MockEC2MachineInterfaceMockRecorder) TerminateInstanceAndWait(arg0 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateInstanceAndWait", reflect.TypeOf((*MockEC2MachineInterface)(nil).TerminateInstanceAndWait), arg0)
 }
 
+// UpdateInstanceResourceTags mocks base method
+func (m *MockEC2MachineInterface) UpdateInstanceResourceTags(arg0 *v1alpha3.Instance, arg1, arg2 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstanceResourceTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateInstanceResourceTags indicates an expected call of UpdateInstanceResourceTags
+func (mr *MockEC2MachineInterfaceMockRecorder) UpdateInstanceResourceTags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstanceResourceTags", reflect.TypeOf((*MockEC2MachineInterface)(nil).UpdateInstanceResourceTags), arg0, arg1, arg2)
+}
+
 // UpdateInstanceSecurityGroups mocks base method
 func (m *MockEC2MachineInterface) UpdateInstanceSecurityGroups(arg0 string, arg1 []string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route53
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// ReconcileControlPlaneEndpointDNS creates or updates the alias record pointing at the API server load balancer.
+func (s *Service) ReconcileControlPlaneEndpointDNS() error {
+	spec := s.scope.AWSCluster.Spec.ControlPlaneEndpointDNS
+	if spec == nil {
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling control plane endpoint DNS record", "record-name", spec.RecordName)
+
+	apiELB := s.scope.Network().APIServerELB
+	if apiELB.DNSName == "" || apiELB.CanonicalHostedZoneID == "" {
+		return errors.New("failed to reconcile control plane endpoint DNS record, API server load balancer is not ready")
+	}
+
+	existing, err := s.describeRecord(spec.HostedZoneID, spec.RecordName)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && aliasTargetMatches(existing, apiELB.DNSName, apiELB.CanonicalHostedZoneID) {
+		s.scope.V(4).Info("Control plane endpoint DNS record is up to date", "record-name", spec.RecordName)
+		return nil
+	}
+
+	rs := &route53.ResourceRecordSet{
+		Name: aws.String(spec.RecordName),
+		Type: aws.String(route53.RRTypeA),
+		AliasTarget: &route53.AliasTarget{
+			DNSName:              aws.String(apiELB.DNSName),
+			HostedZoneId:         aws.String(apiELB.CanonicalHostedZoneID),
+			EvaluateTargetHealth: aws.Bool(false),
+		},
+	}
+
+	if err := s.changeRecord(spec.HostedZoneID, route53.ChangeActionUpsert, rs); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedUpsertDNSRecord", "Failed to create or update DNS record %q in hosted zone %q: %v", spec.RecordName, spec.HostedZoneID, err)
+		return errors.Wrapf(err, "failed to create or update DNS record %q in hosted zone %q", spec.RecordName, spec.HostedZoneID)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulUpsertDNSRecord", "Created or updated DNS record %q in hosted zone %q", spec.RecordName, spec.HostedZoneID)
+	s.scope.V(2).Info("Reconciled control plane endpoint DNS record", "record-name", spec.RecordName)
+	return nil
+}
+
+// DeleteControlPlaneEndpointDNS deletes the alias record pointing at the API server load balancer.
+// Records pointing somewhere else are left untouched.
+func (s *Service) DeleteControlPlaneEndpointDNS() error {
+	spec := s.scope.AWSCluster.Spec.ControlPlaneEndpointDNS
+	if spec == nil {
+		return nil
+	}
+
+	s.scope.V(2).Info("Deleting control plane endpoint DNS record", "record-name", spec.RecordName)
+
+	existing, err := s.describeRecord(spec.HostedZoneID, spec.RecordName)
+	if err != nil {
+		return err
+	}
+
+	apiELB := s.scope.Network().APIServerELB
+	if existing == nil || !aliasTargetMatches(existing, apiELB.DNSName, apiELB.CanonicalHostedZoneID) {
+		s.scope.V(4).Info("Skipping control plane endpoint DNS record deletion, record not found", "record-name", spec.RecordName)
+		return nil
+	}
+
+	if err := s.changeRecord(spec.HostedZoneID, route53.ChangeActionDelete, existing); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteDNSRecord", "Failed to delete DNS record %q in hosted zone %q: %v", spec.RecordName, spec.HostedZoneID, err)
+		return errors.Wrapf(err, "failed to delete DNS record %q in hosted zone %q", spec.RecordName, spec.HostedZoneID)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteDNSRecord", "Deleted DNS record %q in hosted zone %q", spec.RecordName, spec.HostedZoneID)
+	s.scope.V(2).Info("Deleted control plane endpoint DNS record", "record-name", spec.RecordName)
+	return nil
+}
+
+// describeRecord returns the A record set with the given name, or nil if it doesn't exist.
+func (s *Service) describeRecord(hostedZoneID, name string) (*route53.ResourceRecordSet, error) {
+	out, err := s.scope.Route53.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(hostedZoneID),
+		StartRecordName: aws.String(name),
+		StartRecordType: aws.String(route53.RRTypeA),
+		MaxItems:        aws.String("1"),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list DNS records in hosted zone %q", hostedZoneID)
+	}
+
+	// The listing starts at the given name, so the first record may be the one following it.
+	for _, rs := range out.ResourceRecordSets {
+		if normalizeDNSName(aws.StringValue(rs.Name)) == normalizeDNSName(name) && aws.StringValue(rs.Type) == route53.RRTypeA {
+			return rs, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Service) changeRecord(hostedZoneID, action string, rs *route53.ResourceRecordSet) error {
+	_, err := s.scope.Route53.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(hostedZoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: []*route53.Change{
+				{
+					Action:            aws.String(action),
+					ResourceRecordSet: rs,
+				},
+			},
+		},
+	})
+	return err
+}
+
+func aliasTargetMatches(rs *route53.ResourceRecordSet, dnsName, hostedZoneID string) bool {
+	if rs.AliasTarget == nil {
+		return false
+	}
+
+	// Route53 stores alias targets of classic load balancers with a dualstack prefix.
+	target := strings.TrimPrefix(normalizeDNSName(aws.StringValue(rs.AliasTarget.DNSName)), "dualstack.")
+	return target == strings.TrimPrefix(normalizeDNSName(dnsName), "dualstack.") &&
+		aws.StringValue(rs.AliasTarget.HostedZoneId) == hostedZoneID
+}
+
+// normalizeDNSName returns the lowercase, fully qualified form of the name as returned by Route53.
+func normalizeDNSName(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
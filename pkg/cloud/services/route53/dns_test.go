@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route53
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/route53/mock_route53iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+const (
+	testHostedZoneID = "Z0123456789"
+	testRecordName   = "api.test-cluster.example.com"
+	testELBDNSName   = "test-cluster-apiserver-123.us-west-2.elb.amazonaws.com"
+	testELBZoneID    = "Z1H1FL5HABSF5"
+)
+
+func TestReconcileControlPlaneEndpointDNS(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_route53iface.MockRoute53APIMockRecorder)
+	}{
+		{
+			name: "record does not exist, creates it",
+			expect: func(m *mock_route53iface.MockRoute53APIMockRecorder) {
+				m.ListResourceRecordSets(gomock.AssignableToTypeOf(&route53.ListResourceRecordSetsInput{})).
+					Return(&route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []*route53.ResourceRecordSet{
+							{
+								Name: aws.String("www.test-cluster.example.com."),
+								Type: aws.String(route53.RRTypeA),
+							},
+						},
+					}, nil)
+				m.ChangeResourceRecordSets(gomock.Eq(&route53.ChangeResourceRecordSetsInput{
+					HostedZoneId: aws.String(testHostedZoneID),
+					ChangeBatch: &route53.ChangeBatch{
+						Changes: []*route53.Change{
+							{
+								Action: aws.String(route53.ChangeActionUpsert),
+								ResourceRecordSet: &route53.ResourceRecordSet{
+									Name: aws.String(testRecordName),
+									Type: aws.String(route53.RRTypeA),
+									AliasTarget: &route53.AliasTarget{
+										DNSName:              aws.String(testELBDNSName),
+										HostedZoneId:         aws.String(testELBZoneID),
+										EvaluateTargetHealth: aws.Bool(false),
+									},
+								},
+							},
+						},
+					},
+				})).
+					Return(&route53.ChangeResourceRecordSetsOutput{}, nil)
+			},
+		},
+		{
+			name: "record points at the load balancer, no changes",
+			expect: func(m *mock_route53iface.MockRoute53APIMockRecorder) {
+				m.ListResourceRecordSets(gomock.AssignableToTypeOf(&route53.ListResourceRecordSetsInput{})).
+					Return(&route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []*route53.ResourceRecordSet{
+							{
+								Name: aws.String(testRecordName + "."),
+								Type: aws.String(route53.RRTypeA),
+								AliasTarget: &route53.AliasTarget{
+									DNSName:      aws.String("dualstack." + testELBDNSName + "."),
+									HostedZoneId: aws.String(testELBZoneID),
+								},
+							},
+						},
+					}, nil)
+			},
+		},
+		{
+			name: "record points elsewhere, updates it",
+			expect: func(m *mock_route53iface.MockRoute53APIMockRecorder) {
+				m.ListResourceRecordSets(gomock.AssignableToTypeOf(&route53.ListResourceRecordSetsInput{})).
+					Return(&route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []*route53.ResourceRecordSet{
+							{
+								Name: aws.String(testRecordName + "."),
+								Type: aws.String(route53.RRTypeA),
+								AliasTarget: &route53.AliasTarget{
+									DNSName:      aws.String("old-apiserver.us-west-2.elb.amazonaws.com."),
+									HostedZoneId: aws.String(testELBZoneID),
+								},
+							},
+						},
+					}, nil)
+				m.ChangeResourceRecordSets(gomock.AssignableToTypeOf(&route53.ChangeResourceRecordSetsInput{})).
+					Return(&route53.ChangeResourceRecordSetsOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			route53Mock := mock_route53iface.NewMockRoute53API(mockCtrl)
+
+			s := NewService(newTestScope(t, route53Mock))
+			tc.expect(route53Mock.EXPECT())
+
+			if err := s.ReconcileControlPlaneEndpointDNS(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteControlPlaneEndpointDNS(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_route53iface.MockRoute53APIMockRecorder)
+	}{
+		{
+			name: "record points at the load balancer, deletes it",
+			expect: func(m *mock_route53iface.MockRoute53APIMockRecorder) {
+				existing := &route53.ResourceRecordSet{
+					Name: aws.String(testRecordName + "."),
+					Type: aws.String(route53.RRTypeA),
+					AliasTarget: &route53.AliasTarget{
+						DNSName:              aws.String(testELBDNSName + "."),
+						HostedZoneId:         aws.String(testELBZoneID),
+						EvaluateTargetHealth: aws.Bool(false),
+					},
+				}
+				m.ListResourceRecordSets(gomock.AssignableToTypeOf(&route53.ListResourceRecordSetsInput{})).
+					Return(&route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []*route53.ResourceRecordSet{existing},
+					}, nil)
+				m.ChangeResourceRecordSets(gomock.Eq(&route53.ChangeResourceRecordSetsInput{
+					HostedZoneId: aws.String(testHostedZoneID),
+					ChangeBatch: &route53.ChangeBatch{
+						Changes: []*route53.Change{
+							{
+								Action:            aws.String(route53.ChangeActionDelete),
+								ResourceRecordSet: existing,
+							},
+						},
+					},
+				})).
+					Return(&route53.ChangeResourceRecordSetsOutput{}, nil)
+			},
+		},
+		{
+			name: "record points elsewhere, leaves it",
+			expect: func(m *mock_route53iface.MockRoute53APIMockRecorder) {
+				m.ListResourceRecordSets(gomock.AssignableToTypeOf(&route53.ListResourceRecordSetsInput{})).
+					Return(&route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []*route53.ResourceRecordSet{
+							{
+								Name: aws.String(testRecordName + "."),
+								Type: aws.String(route53.RRTypeA),
+								ResourceRecords: []*route53.ResourceRecord{
+									{Value: aws.String("10.0.0.1")},
+								},
+							},
+						},
+					}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			route53Mock := mock_route53iface.NewMockRoute53API(mockCtrl)
+
+			s := NewService(newTestScope(t, route53Mock))
+			tc.expect(route53Mock.EXPECT())
+
+			if err := s.DeleteControlPlaneEndpointDNS(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func newTestScope(t *testing.T, route53Mock *mock_route53iface.MockRoute53API) *scope.ClusterScope {
+	t.Helper()
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSClients: scope.AWSClients{
+			Route53: route53Mock,
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				Region: "us-west-2",
+				ControlPlaneEndpointDNS: &infrav1.ControlPlaneEndpointDNSSpec{
+					HostedZoneID: testHostedZoneID,
+					RecordName:   testRecordName,
+				},
+			},
+			Status: infrav1.AWSClusterStatus{
+				Network: infrav1.Network{
+					APIServerELB: infrav1.ClassicELB{
+						DNSName:               testELBDNSName,
+						CanonicalHostedZoneID: testELBZoneID,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	return scope
+}
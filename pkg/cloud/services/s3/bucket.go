@@ -0,0 +1,354 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// noSuchTagSet is the error code returned when getting the tags of a bucket without tags.
+const noSuchTagSet = "NoSuchTagSet"
+
+// ReconcileBucket creates the S3 bucket of the cluster if it doesn't exist, and grants the roles of
+// the machines' instance profiles read access to the bootstrap data of their role.
+func (s *Service) ReconcileBucket() error {
+	if s.scope.AWSCluster.Spec.S3Bucket == nil {
+		return nil
+	}
+
+	name := s.scope.AWSCluster.Spec.S3Bucket.Name
+	s.scope.V(2).Info("Reconciling S3 bucket", "name", name)
+
+	if err := s.createBucketIfNotExist(name); err != nil {
+		return err
+	}
+
+	if _, err := s.scope.S3.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+		Bucket: aws.String(name),
+		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(true),
+			BlockPublicPolicy:     aws.Bool(true),
+			IgnorePublicAcls:      aws.Bool(true),
+			RestrictPublicBuckets: aws.Bool(true),
+		},
+	}); err != nil {
+		return errors.Wrapf(err, "failed to block public access to S3 bucket %q", name)
+	}
+
+	policy, err := s.bucketPolicy(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.scope.S3.PutBucketPolicy(&s3.PutBucketPolicyInput{
+		Bucket: aws.String(name),
+		Policy: aws.String(policy),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to set policy of S3 bucket %q", name)
+	}
+
+	s.scope.V(2).Info("Reconciled S3 bucket", "name", name)
+	return nil
+}
+
+// DeleteBucket deletes the S3 bucket of the cluster if it's owned by the cluster.
+func (s *Service) DeleteBucket() error {
+	if s.scope.AWSCluster.Spec.S3Bucket == nil {
+		return nil
+	}
+
+	name := s.scope.AWSCluster.Spec.S3Bucket.Name
+	tags, err := s.getBucketTags(name)
+	if err != nil {
+		return err
+	}
+	if tags == nil || !tags.HasOwned(s.scope.Name()) {
+		s.scope.V(2).Info("Skipping deletion of S3 bucket not owned by the cluster", "name", name)
+		return nil
+	}
+
+	// A bucket can only be deleted once it's empty, e.g. when machines were deleted without cleaning up
+	// their bootstrap data.
+	if err := s.emptyBucket(name); err != nil {
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteS3Bucket", "Failed to delete objects of S3 bucket %q: %v", name, err)
+		return err
+	}
+
+	if _, err := s.scope.S3.DeleteBucket(&s3.DeleteBucketInput{
+		Bucket: aws.String(name),
+	}); err != nil {
+		if code, _ := awserrors.Code(err); code == s3.ErrCodeNoSuchBucket {
+			return nil
+		}
+		record.Warnf(s.scope.AWSCluster, "FailedDeleteS3Bucket", "Failed to delete S3 bucket %q: %v", name, err)
+		return errors.Wrapf(err, "failed to delete S3 bucket %q", name)
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulDeleteS3Bucket", "Deleted S3 bucket %q", name)
+	return nil
+}
+
+// emptyBucket deletes the bootstrap data of all machines from the bucket.
+func (s *Service) emptyBucket(name string) error {
+	for _, role := range []string{iam.InstanceProfileRoleControlPlane, iam.InstanceProfileRoleNodes} {
+		input := &s3.ListObjectsV2Input{
+			Bucket: aws.String(name),
+			Prefix: aws.String(role + "/"),
+		}
+		for {
+			out, err := s.scope.S3.ListObjectsV2(input)
+			if err != nil {
+				if code, _ := awserrors.Code(err); code == s3.ErrCodeNoSuchBucket {
+					return nil
+				}
+				return errors.Wrapf(err, "failed to list objects of S3 bucket %q", name)
+			}
+
+			if len(out.Contents) > 0 {
+				objects := make([]*s3.ObjectIdentifier, 0, len(out.Contents))
+				for _, obj := range out.Contents {
+					objects = append(objects, &s3.ObjectIdentifier{Key: obj.Key})
+				}
+				res, err := s.scope.S3.DeleteObjects(&s3.DeleteObjectsInput{
+					Bucket: aws.String(name),
+					Delete: &s3.Delete{
+						Objects: objects,
+						Quiet:   aws.Bool(true),
+					},
+				})
+				if err != nil {
+					return errors.Wrapf(err, "failed to delete objects of S3 bucket %q", name)
+				}
+				if len(res.Errors) > 0 {
+					return errors.Errorf("failed to delete object %q of S3 bucket %q: %s", aws.StringValue(res.Errors[0].Key), name, aws.StringValue(res.Errors[0].Message))
+				}
+			}
+
+			if !aws.BoolValue(out.IsTruncated) {
+				break
+			}
+			input.ContinuationToken = out.NextContinuationToken
+		}
+	}
+
+	return nil
+}
+
+// Create stores the bootstrap data of a machine in the S3 bucket of the cluster and returns its URL.
+func (s *Service) Create(m *scope.MachineScope, data []byte) (string, error) {
+	if s.scope.AWSCluster.Spec.S3Bucket == nil {
+		return "", errors.New("failed to store bootstrap data: the AWSCluster's spec.s3Bucket isn't set")
+	}
+
+	bucket := s.scope.AWSCluster.Spec.S3Bucket.Name
+	key := objectKey(m)
+
+	if _, err := s.scope.S3.PutObject(&s3.PutObjectInput{
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(key),
+		Body:                 aws.ReadSeekCloser(bytes.NewReader(data)),
+		ServerSideEncryption: aws.String(s3.ServerSideEncryptionAes256),
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to store bootstrap data in S3 bucket %q", bucket)
+	}
+
+	return fmt.Sprintf("s3://%s/%s", bucket, key), nil
+}
+
+// Delete deletes the bootstrap data of a machine from the S3 bucket of the cluster.
+func (s *Service) Delete(m *scope.MachineScope) error {
+	if s.scope.AWSCluster.Spec.S3Bucket == nil {
+		return nil
+	}
+
+	bucket := s.scope.AWSCluster.Spec.S3Bucket.Name
+	if _, err := s.scope.S3.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(objectKey(m)),
+	}); err != nil {
+		if code, _ := awserrors.Code(err); code == s3.ErrCodeNoSuchBucket {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete bootstrap data from S3 bucket %q", bucket)
+	}
+
+	return nil
+}
+
+func (s *Service) createBucketIfNotExist(name string) error {
+	input := &s3.CreateBucketInput{
+		Bucket: aws.String(name),
+	}
+	// us-east-1 is the default location and can't be set as location constraint.
+	if region := s.scope.Region(); region != "us-east-1" {
+		input.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(region),
+		}
+	}
+
+	_, err := s.scope.S3.CreateBucket(input)
+	if err != nil {
+		if code, _ := awserrors.Code(err); code != s3.ErrCodeBucketAlreadyOwnedByYou {
+			record.Warnf(s.scope.AWSCluster, "FailedCreateS3Bucket", "Failed to create S3 bucket %q: %v", name, err)
+			return errors.Wrapf(err, "failed to create S3 bucket %q", name)
+		}
+
+		tags, err := s.getBucketTags(name)
+		if err != nil {
+			return err
+		}
+		if !tags.HasOwned(s.scope.Name()) {
+			return errors.Errorf("S3 bucket %q already exists and isn't owned by cluster %q", name, s.scope.Name())
+		}
+		return nil
+	}
+
+	record.Eventf(s.scope.AWSCluster, "SuccessfulCreateS3Bucket", "Created S3 bucket %q", name)
+
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Additional:  s.scope.AdditionalTags(),
+	})
+	if _, err := s.scope.S3.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket: aws.String(name),
+		Tagging: &s3.Tagging{
+			TagSet: converters.MapToS3Tags(tags),
+		},
+	}); err != nil {
+		return errors.Wrapf(err, "failed to tag S3 bucket %q", name)
+	}
+
+	return nil
+}
+
+// getBucketTags returns the tags of a bucket, or nil if the bucket doesn't exist.
+func (s *Service) getBucketTags(name string) (infrav1.Tags, error) {
+	out, err := s.scope.S3.GetBucketTagging(&s3.GetBucketTaggingInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		switch code, _ := awserrors.Code(err); code {
+		case s3.ErrCodeNoSuchBucket:
+			return nil, nil
+		case noSuchTagSet:
+			return infrav1.Tags{}, nil
+		default:
+			return nil, errors.Wrapf(err, "failed to get tags of S3 bucket %q", name)
+		}
+	}
+
+	return converters.S3TagsToMap(out.TagSet), nil
+}
+
+// bucketPolicy returns a policy allowing the roles of the machines' instance profiles to read the
+// bootstrap data of their role.
+func (s *Service) bucketPolicy(bucket string) (string, error) {
+	var statements iam.Statements
+	for _, role := range []string{iam.InstanceProfileRoleControlPlane, iam.InstanceProfileRoleNodes} {
+		profiles, err := s.instanceProfiles(role)
+		if err != nil {
+			return "", err
+		}
+
+		roleARNs, err := s.instanceProfileRoleARNs(profiles)
+		if err != nil {
+			return "", err
+		}
+
+		parsed, err := arn.Parse(roleARNs[0])
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse IAM role ARN %q", roleARNs[0])
+		}
+
+		statements = append(statements, iam.StatementEntry{
+			Effect:    iam.EffectAllow,
+			Principal: iam.Principals{iam.PrincipalAWS: iam.PrincipalID(roleARNs)},
+			Action:    iam.Actions{"s3:GetObject"},
+			Resource:  iam.Resources{fmt.Sprintf("arn:%s:s3:::%s/%s/*", parsed.Partition, bucket, role)},
+		})
+	}
+
+	policy := iam.PolicyDocument{
+		Version:   iam.CurrentVersion,
+		Statement: statements,
+	}
+	return policy.JSON()
+}
+
+// instanceProfiles returns the names of the instance profiles used by the machines of the given role.
+func (s *Service) instanceProfiles(role string) ([]string, error) {
+	bucket := s.scope.AWSCluster.Spec.S3Bucket
+	switch {
+	case role == iam.InstanceProfileRoleControlPlane && bucket.ControlPlaneIAMInstanceProfile != "":
+		return []string{bucket.ControlPlaneIAMInstanceProfile}, nil
+	case role == iam.InstanceProfileRoleNodes && len(bucket.NodesIAMInstanceProfiles) > 0:
+		return bucket.NodesIAMInstanceProfiles, nil
+	case iam.ManagedInstanceProfileSpec(s.scope.AWSCluster.Spec.IAMInstanceProfiles, role) != nil:
+		name, err := iam.ManagedInstanceProfileName(s.scope.Namespace(), s.scope.Name(), s.scope.Region(), role)
+		if err != nil {
+			return nil, err
+		}
+		return []string{name}, nil
+	default:
+		return []string{iam.NewManagedName(role)}, nil
+	}
+}
+
+func (s *Service) instanceProfileRoleARNs(profiles []string) ([]string, error) {
+	var roleARNs []string
+	for _, name := range profiles {
+		out, err := s.scope.IAM.GetInstanceProfile(&awsiam.GetInstanceProfileInput{
+			InstanceProfileName: aws.String(name),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get IAM instance profile %q", name)
+		}
+		if len(out.InstanceProfile.Roles) == 0 {
+			return nil, errors.Errorf("IAM instance profile %q has no role", name)
+		}
+		for _, r := range out.InstanceProfile.Roles {
+			roleARNs = append(roleARNs, aws.StringValue(r.Arn))
+		}
+	}
+
+	return roleARNs, nil
+}
+
+// objectKey returns the key of the object storing the bootstrap data of a machine.
+func objectKey(m *scope.MachineScope) string {
+	role := iam.InstanceProfileRoleNodes
+	if m.IsControlPlane() {
+		role = iam.InstanceProfileRoleControlPlane
+	}
+
+	return path.Join(role, m.Name())
+}
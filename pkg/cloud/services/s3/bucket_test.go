@@ -0,0 +1,319 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam/mock_iamiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/s3/mock_s3iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testBucketName = "cluster-api-provider-aws-test-cluster"
+
+var ownedBucketTags = []*s3.Tag{
+	{
+		Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+		Value: aws.String("owned"),
+	},
+}
+
+func TestReconcileBucket(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	instanceProfile := func(name string) *iam.GetInstanceProfileOutput {
+		return &iam.GetInstanceProfileOutput{
+			InstanceProfile: &iam.InstanceProfile{
+				InstanceProfileName: aws.String(name),
+				Roles: []*iam.Role{
+					{Arn: aws.String("arn:aws:iam::123456789012:role/" + name)},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		expect    func(s3Mock *mock_s3iface.MockS3APIMockRecorder, iamMock *mock_iamiface.MockIAMAPIMockRecorder)
+		expectErr bool
+	}{
+		{
+			name: "creates the bucket and grants the instance profile roles read access",
+			expect: func(s3Mock *mock_s3iface.MockS3APIMockRecorder, iamMock *mock_iamiface.MockIAMAPIMockRecorder) {
+				gomock.InOrder(
+					s3Mock.CreateBucket(gomock.Eq(&s3.CreateBucketInput{
+						Bucket: aws.String(testBucketName),
+						CreateBucketConfiguration: &s3.CreateBucketConfiguration{
+							LocationConstraint: aws.String("us-west-2"),
+						},
+					})).
+						Return(&s3.CreateBucketOutput{}, nil),
+					s3Mock.PutBucketTagging(gomock.AssignableToTypeOf(&s3.PutBucketTaggingInput{})).
+						Return(&s3.PutBucketTaggingOutput{}, nil),
+					s3Mock.PutPublicAccessBlock(gomock.AssignableToTypeOf(&s3.PutPublicAccessBlockInput{})).
+						Return(&s3.PutPublicAccessBlockOutput{}, nil),
+					iamMock.GetInstanceProfile(gomock.Eq(&iam.GetInstanceProfileInput{
+						InstanceProfileName: aws.String("control-plane.cluster-api-provider-aws.sigs.k8s.io"),
+					})).
+						Return(instanceProfile("control-plane.cluster-api-provider-aws.sigs.k8s.io"), nil),
+					iamMock.GetInstanceProfile(gomock.Eq(&iam.GetInstanceProfileInput{
+						InstanceProfileName: aws.String("nodes.cluster-api-provider-aws.sigs.k8s.io"),
+					})).
+						Return(instanceProfile("nodes.cluster-api-provider-aws.sigs.k8s.io"), nil),
+					s3Mock.PutBucketPolicy(gomock.AssignableToTypeOf(&s3.PutBucketPolicyInput{})).
+						DoAndReturn(func(input *s3.PutBucketPolicyInput) (*s3.PutBucketPolicyOutput, error) {
+							var policy struct {
+								Statement []struct {
+									Principal map[string][]string
+									Resource  []string
+								}
+							}
+							if err := json.Unmarshal([]byte(aws.StringValue(input.Policy)), &policy); err != nil {
+								t.Fatalf("cannot parse bucket policy: %v", err)
+							}
+							if len(policy.Statement) != 2 {
+								t.Fatalf("expected 2 statements, got %d", len(policy.Statement))
+							}
+							nodes := policy.Statement[1]
+							if nodes.Resource[0] != "arn:aws:s3:::cluster-api-provider-aws-test-cluster/nodes/*" {
+								t.Fatalf("unexpected resource %q", nodes.Resource[0])
+							}
+							if nodes.Principal["AWS"][0] != "arn:aws:iam::123456789012:role/nodes.cluster-api-provider-aws.sigs.k8s.io" {
+								t.Fatalf("unexpected principal %q", nodes.Principal["AWS"][0])
+							}
+							return &s3.PutBucketPolicyOutput{}, nil
+						}),
+				)
+			},
+		},
+		{
+			name: "fails when the bucket isn't owned by the cluster",
+			expect: func(s3Mock *mock_s3iface.MockS3APIMockRecorder, iamMock *mock_iamiface.MockIAMAPIMockRecorder) {
+				gomock.InOrder(
+					s3Mock.CreateBucket(gomock.Any()).
+						Return(nil, awserr.New(s3.ErrCodeBucketAlreadyOwnedByYou, "owned", nil)),
+					s3Mock.GetBucketTagging(gomock.Eq(&s3.GetBucketTaggingInput{Bucket: aws.String(testBucketName)})).
+						Return(nil, awserr.New(noSuchTagSet, "no tags", nil)),
+				)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s3Mock := mock_s3iface.NewMockS3API(mockCtrl)
+			iamMock := mock_iamiface.NewMockIAMAPI(mockCtrl)
+
+			s := NewService(newTestScope(t, s3Mock, iamMock))
+			tc.expect(s3Mock.EXPECT(), iamMock.EXPECT())
+
+			err := s.ReconcileBucket()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteBucket(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_s3iface.MockS3APIMockRecorder)
+	}{
+		{
+			name: "deletes the owned bucket",
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				gomock.InOrder(
+					m.GetBucketTagging(gomock.Any()).
+						Return(&s3.GetBucketTaggingOutput{TagSet: ownedBucketTags}, nil),
+					m.ListObjectsV2(gomock.Any()).
+						Return(&s3.ListObjectsV2Output{}, nil).Times(2),
+					m.DeleteBucket(gomock.Eq(&s3.DeleteBucketInput{Bucket: aws.String(testBucketName)})).
+						Return(&s3.DeleteBucketOutput{}, nil),
+				)
+			},
+		},
+		{
+			name: "deletes the remaining objects before deleting the bucket",
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				gomock.InOrder(
+					m.GetBucketTagging(gomock.Any()).
+						Return(&s3.GetBucketTaggingOutput{TagSet: ownedBucketTags}, nil),
+					m.ListObjectsV2(gomock.Eq(&s3.ListObjectsV2Input{
+						Bucket: aws.String(testBucketName),
+						Prefix: aws.String("control-plane/"),
+					})).
+						Return(&s3.ListObjectsV2Output{
+							Contents:              []*s3.Object{{Key: aws.String("control-plane/machine-0")}},
+							IsTruncated:           aws.Bool(true),
+							NextContinuationToken: aws.String("next"),
+						}, nil),
+					m.DeleteObjects(gomock.Eq(&s3.DeleteObjectsInput{
+						Bucket: aws.String(testBucketName),
+						Delete: &s3.Delete{
+							Objects: []*s3.ObjectIdentifier{{Key: aws.String("control-plane/machine-0")}},
+							Quiet:   aws.Bool(true),
+						},
+					})).
+						Return(&s3.DeleteObjectsOutput{}, nil),
+					m.ListObjectsV2(gomock.Eq(&s3.ListObjectsV2Input{
+						Bucket:            aws.String(testBucketName),
+						Prefix:            aws.String("control-plane/"),
+						ContinuationToken: aws.String("next"),
+					})).
+						Return(&s3.ListObjectsV2Output{
+							Contents: []*s3.Object{{Key: aws.String("control-plane/machine-1")}},
+						}, nil),
+					m.DeleteObjects(gomock.AssignableToTypeOf(&s3.DeleteObjectsInput{})).
+						Return(&s3.DeleteObjectsOutput{}, nil),
+					m.ListObjectsV2(gomock.Eq(&s3.ListObjectsV2Input{
+						Bucket: aws.String(testBucketName),
+						Prefix: aws.String("nodes/"),
+					})).
+						Return(&s3.ListObjectsV2Output{
+							Contents: []*s3.Object{{Key: aws.String("nodes/machine-2")}},
+						}, nil),
+					m.DeleteObjects(gomock.AssignableToTypeOf(&s3.DeleteObjectsInput{})).
+						Return(&s3.DeleteObjectsOutput{}, nil),
+					m.DeleteBucket(gomock.Eq(&s3.DeleteBucketInput{Bucket: aws.String(testBucketName)})).
+						Return(&s3.DeleteBucketOutput{}, nil),
+				)
+			},
+		},
+		{
+			name: "leaves buckets that aren't owned by the cluster",
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				m.GetBucketTagging(gomock.Any()).
+					Return(&s3.GetBucketTaggingOutput{}, nil)
+			},
+		},
+		{
+			name: "does nothing when the bucket doesn't exist",
+			expect: func(m *mock_s3iface.MockS3APIMockRecorder) {
+				m.GetBucketTagging(gomock.Any()).
+					Return(nil, awserr.New(s3.ErrCodeNoSuchBucket, "not found", nil))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s3Mock := mock_s3iface.NewMockS3API(mockCtrl)
+
+			s := NewService(newTestScope(t, s3Mock, nil))
+			tc.expect(s3Mock.EXPECT())
+
+			if err := s.DeleteBucket(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s3Mock := mock_s3iface.NewMockS3API(mockCtrl)
+	clusterScope := newTestScope(t, s3Mock, nil)
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-machine",
+			Labels: map[string]string{clusterv1.MachineControlPlaneLabelName: ""},
+		},
+	}
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:     fake.NewFakeClient(),
+		Cluster:    clusterScope.Cluster,
+		Machine:    machine,
+		AWSCluster: clusterScope.AWSCluster,
+		AWSMachine: &infrav1.AWSMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-machine"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	s3Mock.EXPECT().PutObject(gomock.AssignableToTypeOf(&s3.PutObjectInput{})).
+		DoAndReturn(func(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			if aws.StringValue(input.Key) != "control-plane/test-machine" {
+				t.Fatalf("unexpected object key %q", aws.StringValue(input.Key))
+			}
+			return &s3.PutObjectOutput{}, nil
+		})
+
+	url, err := NewService(clusterScope).Create(machineScope, []byte(`{"ignition":{}}`))
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+	if url != "s3://cluster-api-provider-aws-test-cluster/control-plane/test-machine" {
+		t.Fatalf("unexpected object URL %q", url)
+	}
+}
+
+func newTestScope(t *testing.T, s3Mock *mock_s3iface.MockS3API, iamMock *mock_iamiface.MockIAMAPI) *scope.ClusterScope {
+	t.Helper()
+
+	clients := scope.AWSClients{
+		S3: s3Mock,
+	}
+	if iamMock != nil {
+		clients.IAM = iamMock
+	}
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSClients: clients,
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				Region:   "us-west-2",
+				S3Bucket: &infrav1.S3Bucket{Name: testBucketName},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	return scope
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	bottlerocketTOML = `[settings.kubernetes]
+api-server = {{TOMLString .APIServerEndpoint}}
+cluster-certificate = {{TOMLString (Base64Encode .ClusterCACert)}}
+cluster-name = {{TOMLString .ClusterName}}
+bootstrap-token = {{TOMLString .BootstrapToken}}
+{{- if .NodeLabels}}
+
+[settings.kubernetes.node-labels]
+{{- range .NodeLabels}}
+{{TOMLString .Key}} = {{TOMLString .Value}}
+{{- end}}
+{{- end}}
+{{- if .AdminContainer}}
+
+[settings.host-containers.admin]
+enabled = {{.AdminContainer}}
+{{- end}}
+{{- if .ControlContainer}}
+
+[settings.host-containers.control]
+enabled = {{.ControlContainer}}
+{{- end}}
+`
+
+	// kubeadmJoinConfigPath is the path of the file holding the join configuration in kubeadm bootstrap data.
+	kubeadmJoinConfigPath = "/tmp/kubeadm-join-config.yaml"
+)
+
+// BottlerocketInput defines the context to generate the user data of a Bottlerocket instance.
+type BottlerocketInput struct {
+	ClusterName       string
+	ClusterCACert     string
+	APIServerEndpoint string
+	BootstrapToken    string
+	NodeLabels        []BottlerocketNodeLabel
+
+	// AdminContainer and ControlContainer enable or disable the host containers. They are left
+	// to the defaults of the image when nil.
+	AdminContainer   *bool
+	ControlContainer *bool
+}
+
+// BottlerocketNodeLabel is a label registered on the node by the kubelet.
+type BottlerocketNodeLabel struct {
+	Key   string
+	Value string
+}
+
+type kubeadmCloudConfig struct {
+	WriteFiles []struct {
+		Path    string `yaml:"path"`
+		Content string `yaml:"content"`
+	} `yaml:"write_files"`
+}
+
+type kubeadmJoinConfiguration struct {
+	Kind      string `yaml:"kind"`
+	Discovery struct {
+		BootstrapToken *struct {
+			APIServerEndpoint string `yaml:"apiServerEndpoint"`
+			Token             string `yaml:"token"`
+		} `yaml:"bootstrapToken"`
+	} `yaml:"discovery"`
+	NodeRegistration struct {
+		KubeletExtraArgs map[string]string `yaml:"kubeletExtraArgs"`
+	} `yaml:"nodeRegistration"`
+}
+
+// NewBottlerocketInputFromKubeadm reads the API server endpoint, bootstrap token and node labels
+// of the kubeadm join configuration written by the kubeadm bootstrap data.
+func NewBottlerocketInputFromKubeadm(bootstrapData []byte) (*BottlerocketInput, error) {
+	cloudConfig := &kubeadmCloudConfig{}
+	if err := yaml.Unmarshal(bootstrapData, cloudConfig); err != nil {
+		return nil, errors.Wrap(err, "failed to parse bootstrap data as kubeadm cloud-config")
+	}
+
+	for _, file := range cloudConfig.WriteFiles {
+		if file.Path != kubeadmJoinConfigPath {
+			continue
+		}
+
+		joinConfig := &kubeadmJoinConfiguration{}
+		if err := yaml.Unmarshal([]byte(file.Content), joinConfig); err != nil {
+			return nil, errors.Wrap(err, "failed to parse kubeadm join configuration")
+		}
+		if joinConfig.Discovery.BootstrapToken == nil {
+			return nil, errors.New("kubeadm join configuration doesn't use bootstrap token discovery")
+		}
+
+		input := &BottlerocketInput{
+			APIServerEndpoint: joinConfig.Discovery.BootstrapToken.APIServerEndpoint,
+			BootstrapToken:    joinConfig.Discovery.BootstrapToken.Token,
+		}
+		if !strings.HasPrefix(input.APIServerEndpoint, "https://") {
+			input.APIServerEndpoint = "https://" + input.APIServerEndpoint
+		}
+
+		labels := joinConfig.NodeRegistration.KubeletExtraArgs["node-labels"]
+		for _, label := range strings.Split(labels, ",") {
+			if label == "" {
+				continue
+			}
+			kv := strings.SplitN(label, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.Errorf("invalid node label %q", label)
+			}
+			input.NodeLabels = append(input.NodeLabels, BottlerocketNodeLabel{Key: kv[0], Value: kv[1]})
+		}
+		sort.Slice(input.NodeLabels, func(i, j int) bool {
+			return input.NodeLabels[i].Key < input.NodeLabels[j].Key
+		})
+
+		return input, nil
+	}
+
+	return nil, errors.Errorf("bootstrap data doesn't write a kubeadm join configuration to %s, only worker machines are supported", kubeadmJoinConfigPath)
+}
+
+// NewBottlerocket returns the TOML user data to be used on a Bottlerocket instance.
+func NewBottlerocket(input *BottlerocketInput) (string, error) {
+	return generate("bottlerocket", bottlerocketTOML, input)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/utils/pointer"
+)
+
+const kubeadmJoinBootstrapData = `## template: jinja
+#cloud-config
+
+write_files:
+-   path: /tmp/kubeadm-join-config.yaml
+    owner: root:root
+    permissions: '0640'
+    content: |
+      ---
+      apiVersion: kubeadm.k8s.io/v1beta1
+      discovery:
+        bootstrapToken:
+          apiServerEndpoint: test-apiserver.elb.amazonaws.com:6443
+          caCertHashes:
+          - sha256:0123456789abcdef
+          token: abcdef.0123456789abcdef
+      kind: JoinConfiguration
+      nodeRegistration:
+        kubeletExtraArgs:
+          cloud-provider: aws
+          node-labels: role=worker,tier=backend
+        name: '{{ ds.meta_data.local_hostname }}'
+runcmd:
+  - 'kubeadm join --config /tmp/kubeadm-join-config.yaml'
+`
+
+func TestNewBottlerocketInputFromKubeadm(t *testing.T) {
+	t.Run("reads the kubeadm join configuration", func(t *testing.T) {
+		input, err := NewBottlerocketInputFromKubeadm([]byte(kubeadmJoinBootstrapData))
+		if err != nil {
+			t.Fatalf("got an unexpected error: %v", err)
+		}
+
+		expected := &BottlerocketInput{
+			APIServerEndpoint: "https://test-apiserver.elb.amazonaws.com:6443",
+			BootstrapToken:    "abcdef.0123456789abcdef",
+			NodeLabels: []BottlerocketNodeLabel{
+				{Key: "role", Value: "worker"},
+				{Key: "tier", Value: "backend"},
+			},
+		}
+		if !reflect.DeepEqual(input, expected) {
+			t.Fatalf("expected %+v, got %+v", expected, input)
+		}
+	})
+
+	t.Run("fails without a kubeadm join configuration", func(t *testing.T) {
+		if _, err := NewBottlerocketInputFromKubeadm([]byte("#cloud-config\nruncmd:\n- kubeadm init\n")); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestNewBottlerocket(t *testing.T) {
+	out, err := NewBottlerocket(&BottlerocketInput{
+		ClusterName:       "test-cluster",
+		ClusterCACert:     "ca",
+		APIServerEndpoint: "https://test-apiserver.elb.amazonaws.com:6443",
+		BootstrapToken:    "abcdef.0123456789abcdef",
+		NodeLabels: []BottlerocketNodeLabel{
+			{Key: "role", Value: "worker"},
+		},
+		AdminContainer: pointer.BoolPtr(false),
+	})
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	expected := `[settings.kubernetes]
+api-server = "https://test-apiserver.elb.amazonaws.com:6443"
+cluster-certificate = "Y2E="
+cluster-name = "test-cluster"
+bootstrap-token = "abcdef.0123456789abcdef"
+
+[settings.kubernetes.node-labels]
+"role" = "worker"
+
+[settings.host-containers.admin]
+enabled = false
+`
+	if out != expected {
+		t.Fatalf("expected user data\n%s\ngot\n%s", expected, out)
+	}
+}
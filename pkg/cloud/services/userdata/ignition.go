@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// IgnitionInput defines the context to generate the user data of an instance bootstrapped with Ignition.
+type IgnitionInput struct {
+	// Version is the Ignition config specification version, for example: 2.3.
+	Version string
+	// Source is the URL of the Ignition config replacing the user data, for example: s3://bucket/key.
+	Source string
+}
+
+type ignitionConfig struct {
+	Ignition struct {
+		Version string `json:"version"`
+		Config  struct {
+			Replace struct {
+				Source string `json:"source"`
+			} `json:"replace"`
+		} `json:"config"`
+	} `json:"ignition"`
+}
+
+// NewIgnition returns an Ignition config replacing itself with the config stored at the input's source.
+func NewIgnition(input *IgnitionInput) ([]byte, error) {
+	config := ignitionConfig{}
+	config.Ignition.Version = input.Version + ".0"
+	config.Ignition.Config.Replace.Source = input.Source
+
+	out, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate ignition config")
+	}
+
+	return out, nil
+}
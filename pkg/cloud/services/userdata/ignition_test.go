@@ -0,0 +1,36 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"testing"
+)
+
+func TestNewIgnition(t *testing.T) {
+	out, err := NewIgnition(&IgnitionInput{
+		Version: "2.3",
+		Source:  "s3://test-bucket/nodes/test-machine",
+	})
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	expected := `{"ignition":{"version":"2.3.0","config":{"replace":{"source":"s3://test-bucket/nodes/test-machine"}}}}`
+	if string(out) != expected {
+		t.Fatalf("expected user data %s, got %s", expected, out)
+	}
+}
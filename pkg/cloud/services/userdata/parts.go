@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+)
+
+const (
+	cloudConfigContentType = "text/cloud-config"
+
+	// cloudConfigMergeType makes cloud-init append the lists of additional cloud-configs, e.g. runcmd,
+	// to the ones of the bootstrap data instead of replacing them.
+	cloudConfigMergeType = "list(append)+dict(no_replace,recurse_list)+str()"
+)
+
+var (
+	multipartHeader = strings.Join([]string{
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=\"%s\"",
+		"\n",
+	}, "\n")
+
+	// contentTypePrefixes are the first lines cloud-init uses to detect the type of user data without MIME headers.
+	contentTypePrefixes = []struct {
+		prefix      string
+		contentType string
+	}{
+		{prefix: "#cloud-config", contentType: cloudConfigContentType},
+		{prefix: "#cloud-boothook", contentType: "text/cloud-boothook"},
+		{prefix: "#!", contentType: "text/x-shellscript"},
+	}
+)
+
+// MergeParts creates a multi-part MIME document made of the bootstrap data followed by the additional parts.
+// The bootstrap data is returned unchanged when there are no additional parts.
+func MergeParts(bootstrapData []byte, parts []infrav1.UserDataPart) ([]byte, error) {
+	if len(parts) == 0 {
+		return bootstrapData, nil
+	}
+
+	contentType := ""
+	for _, p := range contentTypePrefixes {
+		if bytes.HasPrefix(bootstrapData, []byte(p.prefix)) {
+			contentType = p.contentType
+			break
+		}
+	}
+	if contentType == "" {
+		return nil, errors.New("failed to merge user data parts, bootstrap data must be a cloud-config or a shell script")
+	}
+
+	var buf bytes.Buffer
+	mpWriter := multipart.NewWriter(&buf)
+	buf.WriteString(fmt.Sprintf(multipartHeader, mpWriter.Boundary()))
+
+	if err := writePart(mpWriter, textproto.MIMEHeader{"Content-Type": {contentType}}, bootstrapData); err != nil {
+		return nil, err
+	}
+
+	for _, part := range parts {
+		header := textproto.MIMEHeader{"Content-Type": {part.ContentType}}
+		if part.ContentType == cloudConfigContentType {
+			header.Set("Merge-Type", cloudConfigMergeType)
+		}
+
+		if err := writePart(mpWriter, header, []byte(part.Content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mpWriter.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close user data document")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writePart(mpWriter *multipart.Writer, header textproto.MIMEHeader, content []byte) error {
+	w, err := mpWriter.CreatePart(header)
+	if err != nil {
+		return errors.Wrap(err, "failed to create user data part")
+	}
+
+	if _, err := w.Write(content); err != nil {
+		return errors.Wrap(err, "failed to write user data part")
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"reflect"
+	"testing"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
+)
+
+func TestMergeParts(t *testing.T) {
+	bootstrapData := []byte("#cloud-config\nruncmd:\n- kubeadm init\n")
+
+	t.Run("returns the bootstrap data without parts", func(t *testing.T) {
+		out, err := MergeParts(bootstrapData, nil)
+		if err != nil {
+			t.Fatalf("got an unexpected error: %v", err)
+		}
+		if !bytes.Equal(out, bootstrapData) {
+			t.Fatalf("expected bootstrap data to be unchanged, got %q", out)
+		}
+	})
+
+	t.Run("appends parts to the bootstrap data", func(t *testing.T) {
+		out, err := MergeParts(bootstrapData, []infrav1.UserDataPart{
+			{ContentType: "text/x-shellscript", Content: "#!/bin/sh\nupdate-ca-certificates\n"},
+			{ContentType: "text/cloud-config", Content: "#cloud-config\nruncmd:\n- echo done\n"},
+		})
+		if err != nil {
+			t.Fatalf("got an unexpected error: %v", err)
+		}
+
+		msg, err := mail.ReadMessage(bytes.NewBuffer(out))
+		if err != nil {
+			t.Fatalf("cannot parse MIME document: %v\n%s", err, out)
+		}
+		_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		if err != nil {
+			t.Fatalf("cannot parse content type: %v", err)
+		}
+
+		var contentTypes, mergeTypes []string
+		reader := multipart.NewReader(msg.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if _, err := ioutil.ReadAll(part); err != nil {
+				t.Fatalf("cannot read part: %v", err)
+			}
+			contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+			mergeTypes = append(mergeTypes, part.Header.Get("Merge-Type"))
+		}
+
+		expectedContentTypes := []string{"text/cloud-config", "text/x-shellscript", "text/cloud-config"}
+		if !reflect.DeepEqual(contentTypes, expectedContentTypes) {
+			t.Fatalf("expected content types %v, got %v", expectedContentTypes, contentTypes)
+		}
+		expectedMergeTypes := []string{"", "", cloudConfigMergeType}
+		if !reflect.DeepEqual(mergeTypes, expectedMergeTypes) {
+			t.Fatalf("expected merge types %v, got %v", expectedMergeTypes, mergeTypes)
+		}
+	})
+
+	t.Run("fails with bootstrap data of an unknown format", func(t *testing.T) {
+		_, err := MergeParts([]byte("Content-Type: multipart/mixed"), []infrav1.UserDataPart{
+			{ContentType: "text/x-shellscript", Content: "#!/bin/sh\n"},
+		})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -30,7 +30,10 @@ var (
 	defaultTemplateFuncMap = template.FuncMap{
 		"Base64Encode": templateBase64Encode,
 		"Indent":       templateYAMLIndent,
+		"TOMLString":   templateTOMLString,
 	}
+
+	tomlStringReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
 )
 
 func templateBase64Encode(s string) string {
@@ -43,6 +46,10 @@ func templateYAMLIndent(i int, input string) string {
 	return strings.Repeat(" ", i) + strings.Join(split, ident)
 }
 
+func templateTOMLString(s string) string {
+	return `"` + tomlStringReplacer.Replace(s) + `"`
+}
+
 // GzipBytes will gzip a byte array
 func GzipBytes(dat []byte) ([]byte, error) {
 	var buf bytes.Buffer